@@ -30,9 +30,18 @@ goog.exportSymbol('proto.badezimmer.DeviceKind', null, global);
 goog.exportSymbol('proto.badezimmer.DeviceStatus', null, global);
 goog.exportSymbol('proto.badezimmer.ErrorCode', null, global);
 goog.exportSymbol('proto.badezimmer.ErrorDetails', null, global);
+goog.exportSymbol('proto.badezimmer.GetHistoryRequest', null, global);
+goog.exportSymbol('proto.badezimmer.GetHistoryResponse', null, global);
+goog.exportSymbol('proto.badezimmer.GetSeverityStatsRequest', null, global);
+goog.exportSymbol('proto.badezimmer.GetStatusRequest', null, global);
+goog.exportSymbol('proto.badezimmer.HelloRequest', null, global);
+goog.exportSymbol('proto.badezimmer.HelloResponse', null, global);
+goog.exportSymbol('proto.badezimmer.LeakSample', null, global);
 goog.exportSymbol('proto.badezimmer.LightLampActionRequest', null, global);
 goog.exportSymbol('proto.badezimmer.ListConnectedDevicesRequest', null, global);
 goog.exportSymbol('proto.badezimmer.ListConnectedDevicesResponse', null, global);
+goog.exportSymbol('proto.badezimmer.ListServicesRequest', null, global);
+goog.exportSymbol('proto.badezimmer.ListServicesResponse', null, global);
 goog.exportSymbol('proto.badezimmer.MDNS', null, global);
 goog.exportSymbol('proto.badezimmer.MDNS.DataCase', null, global);
 goog.exportSymbol('proto.badezimmer.MDNSARecord', null, global);
@@ -45,10 +54,15 @@ goog.exportSymbol('proto.badezimmer.MDNSRecord.RecordCase', null, global);
 goog.exportSymbol('proto.badezimmer.MDNSSRVRecord', null, global);
 goog.exportSymbol('proto.badezimmer.MDNSTextRecord', null, global);
 goog.exportSymbol('proto.badezimmer.MDNSType', null, global);
+goog.exportSymbol('proto.badezimmer.PingRequest', null, global);
+goog.exportSymbol('proto.badezimmer.PongResponse', null, global);
 goog.exportSymbol('proto.badezimmer.SendActuatorCommandRequest', null, global);
 goog.exportSymbol('proto.badezimmer.SendActuatorCommandRequest.ActionCase', null, global);
 goog.exportSymbol('proto.badezimmer.SendActuatorCommandResponse', null, global);
+goog.exportSymbol('proto.badezimmer.ServiceTypeCount', null, global);
+goog.exportSymbol('proto.badezimmer.SeverityStatsResponse', null, global);
 goog.exportSymbol('proto.badezimmer.SinkActionRequest', null, global);
+goog.exportSymbol('proto.badezimmer.SubscribeRequest', null, global);
 goog.exportSymbol('proto.badezimmer.TransportProtocol', null, global);
 /**
  * Generated by JsPbCodeGenerator.
@@ -218,6 +232,300 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.badezimmer.SendActuatorCommandResponse.displayName = 'proto.badezimmer.SendActuatorCommandResponse';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.badezimmer.GetHistoryRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.badezimmer.GetHistoryRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.badezimmer.GetHistoryRequest.displayName = 'proto.badezimmer.GetHistoryRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.badezimmer.GetStatusRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.badezimmer.GetStatusRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.badezimmer.GetStatusRequest.displayName = 'proto.badezimmer.GetStatusRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.badezimmer.LeakSample = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.badezimmer.LeakSample, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.badezimmer.LeakSample.displayName = 'proto.badezimmer.LeakSample';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.badezimmer.GetHistoryResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.badezimmer.GetHistoryResponse.repeatedFields_, null);
+};
+goog.inherits(proto.badezimmer.GetHistoryResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.badezimmer.GetHistoryResponse.displayName = 'proto.badezimmer.GetHistoryResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.badezimmer.SubscribeRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.badezimmer.SubscribeRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.badezimmer.SubscribeRequest.displayName = 'proto.badezimmer.SubscribeRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.badezimmer.PingRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.badezimmer.PingRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.badezimmer.PingRequest.displayName = 'proto.badezimmer.PingRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.badezimmer.PongResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.badezimmer.PongResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.badezimmer.PongResponse.displayName = 'proto.badezimmer.PongResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.badezimmer.HelloRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.badezimmer.HelloRequest.repeatedFields_, null);
+};
+goog.inherits(proto.badezimmer.HelloRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.badezimmer.HelloRequest.displayName = 'proto.badezimmer.HelloRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.badezimmer.HelloResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.badezimmer.HelloResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.badezimmer.HelloResponse.displayName = 'proto.badezimmer.HelloResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.badezimmer.ListServicesRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.badezimmer.ListServicesRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.badezimmer.ListServicesRequest.displayName = 'proto.badezimmer.ListServicesRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.badezimmer.ServiceTypeCount = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.badezimmer.ServiceTypeCount, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.badezimmer.ServiceTypeCount.displayName = 'proto.badezimmer.ServiceTypeCount';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.badezimmer.ListServicesResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.badezimmer.ListServicesResponse.repeatedFields_, null);
+};
+goog.inherits(proto.badezimmer.ListServicesResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.badezimmer.ListServicesResponse.displayName = 'proto.badezimmer.ListServicesResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.badezimmer.GetSeverityStatsRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.badezimmer.GetSeverityStatsRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.badezimmer.GetSeverityStatsRequest.displayName = 'proto.badezimmer.GetSeverityStatsRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.badezimmer.SeverityStatsResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.badezimmer.SeverityStatsResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.badezimmer.SeverityStatsResponse.displayName = 'proto.badezimmer.SeverityStatsResponse';
+}
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -1697,7 +2005,7 @@ proto.badezimmer.ErrorDetails.prototype.clearMetadataMap = function() {
  * @private {!Array<!Array<number>>}
  * @const
  */
-proto.badezimmer.BadezimmerRequest.oneofGroups_ = [[1,2,3]];
+proto.badezimmer.BadezimmerRequest.oneofGroups_ = [[1,2,3,4,5,6,7,8,9,10]];
 
 /**
  * @enum {number}
@@ -1706,7 +2014,14 @@ proto.badezimmer.BadezimmerRequest.RequestCase = {
   REQUEST_NOT_SET: 0,
   EMPTY: 1,
   LIST_DEVICES: 2,
-  SEND_ACTUATOR_COMMAND: 3
+  SEND_ACTUATOR_COMMAND: 3,
+  GET_HISTORY: 4,
+  SUBSCRIBE: 5,
+  PING: 6,
+  HELLO: 7,
+  LIST_SERVICES: 8,
+  GET_SEVERITY_STATS: 9,
+  GET_STATUS: 10
 };
 
 /**
@@ -1749,7 +2064,16 @@ proto.badezimmer.BadezimmerRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
 empty: (f = msg.getEmpty()) && google_protobuf_empty_pb.Empty.toObject(includeInstance, f),
 listDevices: (f = msg.getListDevices()) && proto.badezimmer.ListConnectedDevicesRequest.toObject(includeInstance, f),
-sendActuatorCommand: (f = msg.getSendActuatorCommand()) && proto.badezimmer.SendActuatorCommandRequest.toObject(includeInstance, f)
+sendActuatorCommand: (f = msg.getSendActuatorCommand()) && proto.badezimmer.SendActuatorCommandRequest.toObject(includeInstance, f),
+getHistory: (f = msg.getGetHistory()) && proto.badezimmer.GetHistoryRequest.toObject(includeInstance, f),
+subscribe: (f = msg.getSubscribe()) && proto.badezimmer.SubscribeRequest.toObject(includeInstance, f),
+ping: (f = msg.getPing()) && proto.badezimmer.PingRequest.toObject(includeInstance, f),
+hello: (f = msg.getHello()) && proto.badezimmer.HelloRequest.toObject(includeInstance, f),
+listServices: (f = msg.getListServices()) && proto.badezimmer.ListServicesRequest.toObject(includeInstance, f),
+getSeverityStats: (f = msg.getGetSeverityStats()) && proto.badezimmer.GetSeverityStatsRequest.toObject(includeInstance, f),
+getStatus: (f = msg.getGetStatus()) && proto.badezimmer.GetStatusRequest.toObject(includeInstance, f),
+requestId: jspb.Message.getFieldWithDefault(msg, 16, ""),
+headersMap: (f = msg.getHeadersMap()) ? f.toObject(includeInstance, undefined) : []
   };
 
   if (includeInstance) {
@@ -1801,13 +2125,58 @@ proto.badezimmer.BadezimmerRequest.deserializeBinaryFromReader = function(msg, r
       reader.readMessage(value,proto.badezimmer.SendActuatorCommandRequest.deserializeBinaryFromReader);
       msg.setSendActuatorCommand(value);
       break;
-    default:
-      reader.skipField();
+    case 4:
+      var value = new proto.badezimmer.GetHistoryRequest;
+      reader.readMessage(value,proto.badezimmer.GetHistoryRequest.deserializeBinaryFromReader);
+      msg.setGetHistory(value);
       break;
-    }
-  }
-  return msg;
-};
+    case 5:
+      var value = new proto.badezimmer.SubscribeRequest;
+      reader.readMessage(value,proto.badezimmer.SubscribeRequest.deserializeBinaryFromReader);
+      msg.setSubscribe(value);
+      break;
+    case 6:
+      var value = new proto.badezimmer.PingRequest;
+      reader.readMessage(value,proto.badezimmer.PingRequest.deserializeBinaryFromReader);
+      msg.setPing(value);
+      break;
+    case 7:
+      var value = new proto.badezimmer.HelloRequest;
+      reader.readMessage(value,proto.badezimmer.HelloRequest.deserializeBinaryFromReader);
+      msg.setHello(value);
+      break;
+    case 8:
+      var value = new proto.badezimmer.ListServicesRequest;
+      reader.readMessage(value,proto.badezimmer.ListServicesRequest.deserializeBinaryFromReader);
+      msg.setListServices(value);
+      break;
+    case 9:
+      var value = new proto.badezimmer.GetSeverityStatsRequest;
+      reader.readMessage(value,proto.badezimmer.GetSeverityStatsRequest.deserializeBinaryFromReader);
+      msg.setGetSeverityStats(value);
+      break;
+    case 10:
+      var value = new proto.badezimmer.GetStatusRequest;
+      reader.readMessage(value,proto.badezimmer.GetStatusRequest.deserializeBinaryFromReader);
+      msg.setGetStatus(value);
+      break;
+    case 16:
+      var value = /** @type {string} */ (reader.readStringRequireUtf8());
+      msg.setRequestId(value);
+      break;
+    case 17:
+      var value = msg.getHeadersMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readStringRequireUtf8, jspb.BinaryReader.prototype.readStringRequireUtf8, null, "", "");
+         });
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
 
 
 /**
@@ -1854,6 +2223,78 @@ proto.badezimmer.BadezimmerRequest.serializeBinaryToWriter = function(message, w
       proto.badezimmer.SendActuatorCommandRequest.serializeBinaryToWriter
     );
   }
+  f = message.getGetHistory();
+  if (f != null) {
+    writer.writeMessage(
+      4,
+      f,
+      proto.badezimmer.GetHistoryRequest.serializeBinaryToWriter
+    );
+  }
+  f = message.getSubscribe();
+  if (f != null) {
+    writer.writeMessage(
+      5,
+      f,
+      proto.badezimmer.SubscribeRequest.serializeBinaryToWriter
+    );
+  }
+  f = message.getPing();
+  if (f != null) {
+    writer.writeMessage(
+      6,
+      f,
+      proto.badezimmer.PingRequest.serializeBinaryToWriter
+    );
+  }
+  f = message.getHello();
+  if (f != null) {
+    writer.writeMessage(
+      7,
+      f,
+      proto.badezimmer.HelloRequest.serializeBinaryToWriter
+    );
+  }
+  f = message.getListServices();
+  if (f != null) {
+    writer.writeMessage(
+      8,
+      f,
+      proto.badezimmer.ListServicesRequest.serializeBinaryToWriter
+    );
+  }
+  f = message.getGetSeverityStats();
+  if (f != null) {
+    writer.writeMessage(
+      9,
+      f,
+      proto.badezimmer.GetSeverityStatsRequest.serializeBinaryToWriter
+    );
+  }
+  f = message.getGetStatus();
+  if (f != null) {
+    writer.writeMessage(
+      10,
+      f,
+      proto.badezimmer.GetStatusRequest.serializeBinaryToWriter
+    );
+  }
+  f = message.getRequestId();
+  if (f.length > 0) {
+    writer.writeString(
+      16,
+      f
+    );
+  }
+  f = message.getHeadersMap(true);
+  if (f && f.getLength() > 0) {
+jspb.internal.public_for_gencode.serializeMapToBinary(
+    message.getHeadersMap(true),
+    17,
+    writer,
+    jspb.BinaryWriter.prototype.writeString,
+    jspb.BinaryWriter.prototype.writeString);
+  }
 };
 
 
@@ -1968,215 +2409,105 @@ proto.badezimmer.BadezimmerRequest.prototype.hasSendActuatorCommand = function()
 };
 
 
-
-/**
- * Oneof group definitions for this message. Each group defines the field
- * numbers belonging to that group. When of these fields' value is set, all
- * other fields in the group are cleared. During deserialization, if multiple
- * fields are encountered for a group, only the last value seen will be kept.
- * @private {!Array<!Array<number>>}
- * @const
- */
-proto.badezimmer.BadezimmerResponse.oneofGroups_ = [[1,2,3,4]];
-
 /**
- * @enum {number}
+ * optional GetHistoryRequest get_history = 4;
+ * @return {?proto.badezimmer.GetHistoryRequest}
  */
-proto.badezimmer.BadezimmerResponse.ResponseCase = {
-  RESPONSE_NOT_SET: 0,
-  EMPTY: 1,
-  ERROR: 2,
-  LIST_DEVICES_RESPONSE: 3,
-  SEND_ACTUATOR_COMMAND_RESPONSE: 4
+proto.badezimmer.BadezimmerRequest.prototype.getGetHistory = function() {
+  return /** @type{?proto.badezimmer.GetHistoryRequest} */ (
+    jspb.Message.getWrapperField(this, proto.badezimmer.GetHistoryRequest, 4));
 };
 
+
 /**
- * @return {proto.badezimmer.BadezimmerResponse.ResponseCase}
- */
-proto.badezimmer.BadezimmerResponse.prototype.getResponseCase = function() {
-  return /** @type {proto.badezimmer.BadezimmerResponse.ResponseCase} */(jspb.Message.computeOneofCase(this, proto.badezimmer.BadezimmerResponse.oneofGroups_[0]));
+ * @param {?proto.badezimmer.GetHistoryRequest|undefined} value
+ * @return {!proto.badezimmer.BadezimmerRequest} returns this
+*/
+proto.badezimmer.BadezimmerRequest.prototype.setGetHistory = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 4, proto.badezimmer.BadezimmerRequest.oneofGroups_[0], value);
 };
 
 
-
-if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * Creates an object representation of this proto.
- * Field names that are reserved in JavaScript and will be renamed to pb_name.
- * Optional fields that are not set will be set to undefined.
- * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
- * For the list of reserved names please see:
- *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
- * @param {boolean=} opt_includeInstance Deprecated. whether to include the
- *     JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @return {!Object}
+ * Clears the message field making it undefined.
+ * @return {!proto.badezimmer.BadezimmerRequest} returns this
  */
-proto.badezimmer.BadezimmerResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.badezimmer.BadezimmerResponse.toObject(opt_includeInstance, this);
+proto.badezimmer.BadezimmerRequest.prototype.clearGetHistory = function() {
+  return this.setGetHistory(undefined);
 };
 
 
 /**
- * Static version of the {@see toObject} method.
- * @param {boolean|undefined} includeInstance Deprecated. Whether to include
- *     the JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @param {!proto.badezimmer.BadezimmerResponse} msg The msg instance to transform.
- * @return {!Object}
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.badezimmer.BadezimmerResponse.toObject = function(includeInstance, msg) {
-  var f, obj = {
-empty: (f = msg.getEmpty()) && google_protobuf_empty_pb.Empty.toObject(includeInstance, f),
-error: (f = msg.getError()) && proto.badezimmer.ErrorDetails.toObject(includeInstance, f),
-listDevicesResponse: (f = msg.getListDevicesResponse()) && proto.badezimmer.ListConnectedDevicesResponse.toObject(includeInstance, f),
-sendActuatorCommandResponse: (f = msg.getSendActuatorCommandResponse()) && proto.badezimmer.SendActuatorCommandResponse.toObject(includeInstance, f)
-  };
-
-  if (includeInstance) {
-    obj.$jspbMessageInstance = msg;
-  }
-  return obj;
+proto.badezimmer.BadezimmerRequest.prototype.hasGetHistory = function() {
+  return jspb.Message.getField(this, 4) != null;
 };
-}
 
 
 /**
- * Deserializes binary data (in protobuf wire format).
- * @param {jspb.binary.bytesource.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.badezimmer.BadezimmerResponse}
+ * optional SubscribeRequest subscribe = 5;
+ * @return {?proto.badezimmer.SubscribeRequest}
  */
-proto.badezimmer.BadezimmerResponse.deserializeBinary = function(bytes) {
-  var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.badezimmer.BadezimmerResponse;
-  return proto.badezimmer.BadezimmerResponse.deserializeBinaryFromReader(msg, reader);
+proto.badezimmer.BadezimmerRequest.prototype.getSubscribe = function() {
+  return /** @type{?proto.badezimmer.SubscribeRequest} */ (
+    jspb.Message.getWrapperField(this, proto.badezimmer.SubscribeRequest, 5));
 };
 
 
 /**
- * Deserializes binary data (in protobuf wire format) from the
- * given reader into the given message object.
- * @param {!proto.badezimmer.BadezimmerResponse} msg The message object to deserialize into.
- * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.badezimmer.BadezimmerResponse}
- */
-proto.badezimmer.BadezimmerResponse.deserializeBinaryFromReader = function(msg, reader) {
-  while (reader.nextField()) {
-    if (reader.isEndGroup()) {
-      break;
-    }
-    var field = reader.getFieldNumber();
-    switch (field) {
-    case 1:
-      var value = new google_protobuf_empty_pb.Empty;
-      reader.readMessage(value,google_protobuf_empty_pb.Empty.deserializeBinaryFromReader);
-      msg.setEmpty(value);
-      break;
-    case 2:
-      var value = new proto.badezimmer.ErrorDetails;
-      reader.readMessage(value,proto.badezimmer.ErrorDetails.deserializeBinaryFromReader);
-      msg.setError(value);
-      break;
-    case 3:
-      var value = new proto.badezimmer.ListConnectedDevicesResponse;
-      reader.readMessage(value,proto.badezimmer.ListConnectedDevicesResponse.deserializeBinaryFromReader);
-      msg.setListDevicesResponse(value);
-      break;
-    case 4:
-      var value = new proto.badezimmer.SendActuatorCommandResponse;
-      reader.readMessage(value,proto.badezimmer.SendActuatorCommandResponse.deserializeBinaryFromReader);
-      msg.setSendActuatorCommandResponse(value);
-      break;
-    default:
-      reader.skipField();
-      break;
-    }
-  }
-  return msg;
+ * @param {?proto.badezimmer.SubscribeRequest|undefined} value
+ * @return {!proto.badezimmer.BadezimmerRequest} returns this
+*/
+proto.badezimmer.BadezimmerRequest.prototype.setSubscribe = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 5, proto.badezimmer.BadezimmerRequest.oneofGroups_[0], value);
 };
 
 
 /**
- * Serializes the message to binary data (in protobuf wire format).
- * @return {!Uint8Array}
+ * Clears the message field making it undefined.
+ * @return {!proto.badezimmer.BadezimmerRequest} returns this
  */
-proto.badezimmer.BadezimmerResponse.prototype.serializeBinary = function() {
-  var writer = new jspb.BinaryWriter();
-  proto.badezimmer.BadezimmerResponse.serializeBinaryToWriter(this, writer);
-  return writer.getResultBuffer();
+proto.badezimmer.BadezimmerRequest.prototype.clearSubscribe = function() {
+  return this.setSubscribe(undefined);
 };
 
 
 /**
- * Serializes the given message to binary data (in protobuf wire
- * format), writing to the given BinaryWriter.
- * @param {!proto.badezimmer.BadezimmerResponse} message
- * @param {!jspb.BinaryWriter} writer
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.badezimmer.BadezimmerResponse.serializeBinaryToWriter = function(message, writer) {
-  var f = undefined;
-  f = message.getEmpty();
-  if (f != null) {
-    writer.writeMessage(
-      1,
-      f,
-      google_protobuf_empty_pb.Empty.serializeBinaryToWriter
-    );
-  }
-  f = message.getError();
-  if (f != null) {
-    writer.writeMessage(
-      2,
-      f,
-      proto.badezimmer.ErrorDetails.serializeBinaryToWriter
-    );
-  }
-  f = message.getListDevicesResponse();
-  if (f != null) {
-    writer.writeMessage(
-      3,
-      f,
-      proto.badezimmer.ListConnectedDevicesResponse.serializeBinaryToWriter
-    );
-  }
-  f = message.getSendActuatorCommandResponse();
-  if (f != null) {
-    writer.writeMessage(
-      4,
-      f,
-      proto.badezimmer.SendActuatorCommandResponse.serializeBinaryToWriter
-    );
-  }
+proto.badezimmer.BadezimmerRequest.prototype.hasSubscribe = function() {
+  return jspb.Message.getField(this, 5) != null;
 };
 
 
 /**
- * optional google.protobuf.Empty empty = 1;
- * @return {?proto.google.protobuf.Empty}
+ * optional PingRequest ping = 6;
+ * @return {?proto.badezimmer.PingRequest}
  */
-proto.badezimmer.BadezimmerResponse.prototype.getEmpty = function() {
-  return /** @type{?proto.google.protobuf.Empty} */ (
-    jspb.Message.getWrapperField(this, google_protobuf_empty_pb.Empty, 1));
+proto.badezimmer.BadezimmerRequest.prototype.getPing = function() {
+  return /** @type{?proto.badezimmer.PingRequest} */ (
+    jspb.Message.getWrapperField(this, proto.badezimmer.PingRequest, 6));
 };
 
 
 /**
- * @param {?proto.google.protobuf.Empty|undefined} value
- * @return {!proto.badezimmer.BadezimmerResponse} returns this
+ * @param {?proto.badezimmer.PingRequest|undefined} value
+ * @return {!proto.badezimmer.BadezimmerRequest} returns this
 */
-proto.badezimmer.BadezimmerResponse.prototype.setEmpty = function(value) {
-  return jspb.Message.setOneofWrapperField(this, 1, proto.badezimmer.BadezimmerResponse.oneofGroups_[0], value);
+proto.badezimmer.BadezimmerRequest.prototype.setPing = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 6, proto.badezimmer.BadezimmerRequest.oneofGroups_[0], value);
 };
 
 
 /**
  * Clears the message field making it undefined.
- * @return {!proto.badezimmer.BadezimmerResponse} returns this
+ * @return {!proto.badezimmer.BadezimmerRequest} returns this
  */
-proto.badezimmer.BadezimmerResponse.prototype.clearEmpty = function() {
-  return this.setEmpty(undefined);
+proto.badezimmer.BadezimmerRequest.prototype.clearPing = function() {
+  return this.setPing(undefined);
 };
 
 
@@ -2184,36 +2515,36 @@ proto.badezimmer.BadezimmerResponse.prototype.clearEmpty = function() {
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.badezimmer.BadezimmerResponse.prototype.hasEmpty = function() {
-  return jspb.Message.getField(this, 1) != null;
+proto.badezimmer.BadezimmerRequest.prototype.hasPing = function() {
+  return jspb.Message.getField(this, 6) != null;
 };
 
 
 /**
- * optional ErrorDetails error = 2;
- * @return {?proto.badezimmer.ErrorDetails}
+ * optional HelloRequest hello = 7;
+ * @return {?proto.badezimmer.HelloRequest}
  */
-proto.badezimmer.BadezimmerResponse.prototype.getError = function() {
-  return /** @type{?proto.badezimmer.ErrorDetails} */ (
-    jspb.Message.getWrapperField(this, proto.badezimmer.ErrorDetails, 2));
+proto.badezimmer.BadezimmerRequest.prototype.getHello = function() {
+  return /** @type{?proto.badezimmer.HelloRequest} */ (
+    jspb.Message.getWrapperField(this, proto.badezimmer.HelloRequest, 7));
 };
 
 
 /**
- * @param {?proto.badezimmer.ErrorDetails|undefined} value
- * @return {!proto.badezimmer.BadezimmerResponse} returns this
+ * @param {?proto.badezimmer.HelloRequest|undefined} value
+ * @return {!proto.badezimmer.BadezimmerRequest} returns this
 */
-proto.badezimmer.BadezimmerResponse.prototype.setError = function(value) {
-  return jspb.Message.setOneofWrapperField(this, 2, proto.badezimmer.BadezimmerResponse.oneofGroups_[0], value);
+proto.badezimmer.BadezimmerRequest.prototype.setHello = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 7, proto.badezimmer.BadezimmerRequest.oneofGroups_[0], value);
 };
 
 
 /**
  * Clears the message field making it undefined.
- * @return {!proto.badezimmer.BadezimmerResponse} returns this
+ * @return {!proto.badezimmer.BadezimmerRequest} returns this
  */
-proto.badezimmer.BadezimmerResponse.prototype.clearError = function() {
-  return this.setError(undefined);
+proto.badezimmer.BadezimmerRequest.prototype.clearHello = function() {
+  return this.setHello(undefined);
 };
 
 
@@ -2221,82 +2552,2836 @@ proto.badezimmer.BadezimmerResponse.prototype.clearError = function() {
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.badezimmer.BadezimmerResponse.prototype.hasError = function() {
-  return jspb.Message.getField(this, 2) != null;
+proto.badezimmer.BadezimmerRequest.prototype.hasHello = function() {
+  return jspb.Message.getField(this, 7) != null;
+};
+
+
+/**
+ * optional ListServicesRequest list_services = 8;
+ * @return {?proto.badezimmer.ListServicesRequest}
+ */
+proto.badezimmer.BadezimmerRequest.prototype.getListServices = function() {
+  return /** @type{?proto.badezimmer.ListServicesRequest} */ (
+    jspb.Message.getWrapperField(this, proto.badezimmer.ListServicesRequest, 8));
+};
+
+
+/**
+ * @param {?proto.badezimmer.ListServicesRequest|undefined} value
+ * @return {!proto.badezimmer.BadezimmerRequest} returns this
+*/
+proto.badezimmer.BadezimmerRequest.prototype.setListServices = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 8, proto.badezimmer.BadezimmerRequest.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.badezimmer.BadezimmerRequest} returns this
+ */
+proto.badezimmer.BadezimmerRequest.prototype.clearListServices = function() {
+  return this.setListServices(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.badezimmer.BadezimmerRequest.prototype.hasListServices = function() {
+  return jspb.Message.getField(this, 8) != null;
+};
+
+
+/**
+ * optional GetSeverityStatsRequest get_severity_stats = 9;
+ * @return {?proto.badezimmer.GetSeverityStatsRequest}
+ */
+proto.badezimmer.BadezimmerRequest.prototype.getGetSeverityStats = function() {
+  return /** @type{?proto.badezimmer.GetSeverityStatsRequest} */ (
+    jspb.Message.getWrapperField(this, proto.badezimmer.GetSeverityStatsRequest, 9));
+};
+
+
+/**
+ * @param {?proto.badezimmer.GetSeverityStatsRequest|undefined} value
+ * @return {!proto.badezimmer.BadezimmerRequest} returns this
+*/
+proto.badezimmer.BadezimmerRequest.prototype.setGetSeverityStats = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 9, proto.badezimmer.BadezimmerRequest.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.badezimmer.BadezimmerRequest} returns this
+ */
+proto.badezimmer.BadezimmerRequest.prototype.clearGetSeverityStats = function() {
+  return this.setGetSeverityStats(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.badezimmer.BadezimmerRequest.prototype.hasGetSeverityStats = function() {
+  return jspb.Message.getField(this, 9) != null;
+};
+
+
+/**
+ * optional GetStatusRequest get_status = 10;
+ * @return {?proto.badezimmer.GetStatusRequest}
+ */
+proto.badezimmer.BadezimmerRequest.prototype.getGetStatus = function() {
+  return /** @type{?proto.badezimmer.GetStatusRequest} */ (
+    jspb.Message.getWrapperField(this, proto.badezimmer.GetStatusRequest, 10));
+};
+
+
+/**
+ * @param {?proto.badezimmer.GetStatusRequest|undefined} value
+ * @return {!proto.badezimmer.BadezimmerRequest} returns this
+*/
+proto.badezimmer.BadezimmerRequest.prototype.setGetStatus = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 10, proto.badezimmer.BadezimmerRequest.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.badezimmer.BadezimmerRequest} returns this
+ */
+proto.badezimmer.BadezimmerRequest.prototype.clearGetStatus = function() {
+  return this.setGetStatus(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.badezimmer.BadezimmerRequest.prototype.hasGetStatus = function() {
+  return jspb.Message.getField(this, 10) != null;
+};
+
+
+/**
+ * optional string request_id = 16;
+ * @return {string}
+ */
+proto.badezimmer.BadezimmerRequest.prototype.getRequestId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 16, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.badezimmer.BadezimmerRequest} returns this
+ */
+proto.badezimmer.BadezimmerRequest.prototype.setRequestId = function(value) {
+  return jspb.Message.setProto3StringField(this, 16, value);
+};
+
+
+/**
+ * map<string, string> headers = 17;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,string>}
+ */
+proto.badezimmer.BadezimmerRequest.prototype.getHeadersMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,string>} */ (
+      jspb.Message.getMapField(this, 17, opt_noLazyCreate,
+      null));
+};
+
+
+/**
+ * Clears values from the map. The map will be non-null.
+ * @return {!proto.badezimmer.BadezimmerRequest} returns this
+ */
+proto.badezimmer.BadezimmerRequest.prototype.clearHeadersMap = function() {
+  this.getHeadersMap().clear();
+  return this;
+};
+
+
+
+/**
+ * Oneof group definitions for this message. Each group defines the field
+ * numbers belonging to that group. When of these fields' value is set, all
+ * other fields in the group are cleared. During deserialization, if multiple
+ * fields are encountered for a group, only the last value seen will be kept.
+ * @private {!Array<!Array<number>>}
+ * @const
+ */
+proto.badezimmer.BadezimmerResponse.oneofGroups_ = [[1,2,3,4,5,6,7,8,9,10,11]];
+
+/**
+ * @enum {number}
+ */
+proto.badezimmer.BadezimmerResponse.ResponseCase = {
+  RESPONSE_NOT_SET: 0,
+  EMPTY: 1,
+  ERROR: 2,
+  LIST_DEVICES_RESPONSE: 3,
+  SEND_ACTUATOR_COMMAND_RESPONSE: 4,
+  GET_HISTORY_RESPONSE: 5,
+  LEAK_UPDATE: 6,
+  PONG: 7,
+  HELLO_RESPONSE: 8,
+  LIST_SERVICES_RESPONSE: 9,
+  SEVERITY_STATS_RESPONSE: 10,
+  STATUS_RESPONSE: 11
+};
+
+/**
+ * @return {proto.badezimmer.BadezimmerResponse.ResponseCase}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.getResponseCase = function() {
+  return /** @type {proto.badezimmer.BadezimmerResponse.ResponseCase} */(jspb.Message.computeOneofCase(this, proto.badezimmer.BadezimmerResponse.oneofGroups_[0]));
+};
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.badezimmer.BadezimmerResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.badezimmer.BadezimmerResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.BadezimmerResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+empty: (f = msg.getEmpty()) && google_protobuf_empty_pb.Empty.toObject(includeInstance, f),
+error: (f = msg.getError()) && proto.badezimmer.ErrorDetails.toObject(includeInstance, f),
+listDevicesResponse: (f = msg.getListDevicesResponse()) && proto.badezimmer.ListConnectedDevicesResponse.toObject(includeInstance, f),
+sendActuatorCommandResponse: (f = msg.getSendActuatorCommandResponse()) && proto.badezimmer.SendActuatorCommandResponse.toObject(includeInstance, f),
+getHistoryResponse: (f = msg.getGetHistoryResponse()) && proto.badezimmer.GetHistoryResponse.toObject(includeInstance, f),
+leakUpdate: (f = msg.getLeakUpdate()) && proto.badezimmer.LeakSample.toObject(includeInstance, f),
+pong: (f = msg.getPong()) && proto.badezimmer.PongResponse.toObject(includeInstance, f),
+helloResponse: (f = msg.getHelloResponse()) && proto.badezimmer.HelloResponse.toObject(includeInstance, f),
+listServicesResponse: (f = msg.getListServicesResponse()) && proto.badezimmer.ListServicesResponse.toObject(includeInstance, f),
+severityStatsResponse: (f = msg.getSeverityStatsResponse()) && proto.badezimmer.SeverityStatsResponse.toObject(includeInstance, f),
+statusResponse: (f = msg.getStatusResponse()) && proto.badezimmer.LeakSample.toObject(includeInstance, f),
+requestId: jspb.Message.getFieldWithDefault(msg, 16, ""),
+headersMap: (f = msg.getHeadersMap()) ? f.toObject(includeInstance, undefined) : []
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.binary.bytesource.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.badezimmer.BadezimmerResponse}
+ */
+proto.badezimmer.BadezimmerResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.badezimmer.BadezimmerResponse;
+  return proto.badezimmer.BadezimmerResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.badezimmer.BadezimmerResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.badezimmer.BadezimmerResponse}
+ */
+proto.badezimmer.BadezimmerResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new google_protobuf_empty_pb.Empty;
+      reader.readMessage(value,google_protobuf_empty_pb.Empty.deserializeBinaryFromReader);
+      msg.setEmpty(value);
+      break;
+    case 2:
+      var value = new proto.badezimmer.ErrorDetails;
+      reader.readMessage(value,proto.badezimmer.ErrorDetails.deserializeBinaryFromReader);
+      msg.setError(value);
+      break;
+    case 3:
+      var value = new proto.badezimmer.ListConnectedDevicesResponse;
+      reader.readMessage(value,proto.badezimmer.ListConnectedDevicesResponse.deserializeBinaryFromReader);
+      msg.setListDevicesResponse(value);
+      break;
+    case 4:
+      var value = new proto.badezimmer.SendActuatorCommandResponse;
+      reader.readMessage(value,proto.badezimmer.SendActuatorCommandResponse.deserializeBinaryFromReader);
+      msg.setSendActuatorCommandResponse(value);
+      break;
+    case 5:
+      var value = new proto.badezimmer.GetHistoryResponse;
+      reader.readMessage(value,proto.badezimmer.GetHistoryResponse.deserializeBinaryFromReader);
+      msg.setGetHistoryResponse(value);
+      break;
+    case 6:
+      var value = new proto.badezimmer.LeakSample;
+      reader.readMessage(value,proto.badezimmer.LeakSample.deserializeBinaryFromReader);
+      msg.setLeakUpdate(value);
+      break;
+    case 7:
+      var value = new proto.badezimmer.PongResponse;
+      reader.readMessage(value,proto.badezimmer.PongResponse.deserializeBinaryFromReader);
+      msg.setPong(value);
+      break;
+    case 8:
+      var value = new proto.badezimmer.HelloResponse;
+      reader.readMessage(value,proto.badezimmer.HelloResponse.deserializeBinaryFromReader);
+      msg.setHelloResponse(value);
+      break;
+    case 9:
+      var value = new proto.badezimmer.ListServicesResponse;
+      reader.readMessage(value,proto.badezimmer.ListServicesResponse.deserializeBinaryFromReader);
+      msg.setListServicesResponse(value);
+      break;
+    case 10:
+      var value = new proto.badezimmer.SeverityStatsResponse;
+      reader.readMessage(value,proto.badezimmer.SeverityStatsResponse.deserializeBinaryFromReader);
+      msg.setSeverityStatsResponse(value);
+      break;
+    case 11:
+      var value = new proto.badezimmer.LeakSample;
+      reader.readMessage(value,proto.badezimmer.LeakSample.deserializeBinaryFromReader);
+      msg.setStatusResponse(value);
+      break;
+    case 16:
+      var value = /** @type {string} */ (reader.readStringRequireUtf8());
+      msg.setRequestId(value);
+      break;
+    case 17:
+      var value = msg.getHeadersMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readStringRequireUtf8, jspb.BinaryReader.prototype.readStringRequireUtf8, null, "", "");
+         });
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.badezimmer.BadezimmerResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.badezimmer.BadezimmerResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.BadezimmerResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getEmpty();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      google_protobuf_empty_pb.Empty.serializeBinaryToWriter
+    );
+  }
+  f = message.getError();
+  if (f != null) {
+    writer.writeMessage(
+      2,
+      f,
+      proto.badezimmer.ErrorDetails.serializeBinaryToWriter
+    );
+  }
+  f = message.getListDevicesResponse();
+  if (f != null) {
+    writer.writeMessage(
+      3,
+      f,
+      proto.badezimmer.ListConnectedDevicesResponse.serializeBinaryToWriter
+    );
+  }
+  f = message.getSendActuatorCommandResponse();
+  if (f != null) {
+    writer.writeMessage(
+      4,
+      f,
+      proto.badezimmer.SendActuatorCommandResponse.serializeBinaryToWriter
+    );
+  }
+  f = message.getGetHistoryResponse();
+  if (f != null) {
+    writer.writeMessage(
+      5,
+      f,
+      proto.badezimmer.GetHistoryResponse.serializeBinaryToWriter
+    );
+  }
+  f = message.getLeakUpdate();
+  if (f != null) {
+    writer.writeMessage(
+      6,
+      f,
+      proto.badezimmer.LeakSample.serializeBinaryToWriter
+    );
+  }
+  f = message.getPong();
+  if (f != null) {
+    writer.writeMessage(
+      7,
+      f,
+      proto.badezimmer.PongResponse.serializeBinaryToWriter
+    );
+  }
+  f = message.getHelloResponse();
+  if (f != null) {
+    writer.writeMessage(
+      8,
+      f,
+      proto.badezimmer.HelloResponse.serializeBinaryToWriter
+    );
+  }
+  f = message.getListServicesResponse();
+  if (f != null) {
+    writer.writeMessage(
+      9,
+      f,
+      proto.badezimmer.ListServicesResponse.serializeBinaryToWriter
+    );
+  }
+  f = message.getSeverityStatsResponse();
+  if (f != null) {
+    writer.writeMessage(
+      10,
+      f,
+      proto.badezimmer.SeverityStatsResponse.serializeBinaryToWriter
+    );
+  }
+  f = message.getStatusResponse();
+  if (f != null) {
+    writer.writeMessage(
+      11,
+      f,
+      proto.badezimmer.LeakSample.serializeBinaryToWriter
+    );
+  }
+  f = message.getRequestId();
+  if (f.length > 0) {
+    writer.writeString(
+      16,
+      f
+    );
+  }
+  f = message.getHeadersMap(true);
+  if (f && f.getLength() > 0) {
+jspb.internal.public_for_gencode.serializeMapToBinary(
+    message.getHeadersMap(true),
+    17,
+    writer,
+    jspb.BinaryWriter.prototype.writeString,
+    jspb.BinaryWriter.prototype.writeString);
+  }
+};
+
+
+/**
+ * optional google.protobuf.Empty empty = 1;
+ * @return {?proto.google.protobuf.Empty}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.getEmpty = function() {
+  return /** @type{?proto.google.protobuf.Empty} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_empty_pb.Empty, 1));
+};
+
+
+/**
+ * @param {?proto.google.protobuf.Empty|undefined} value
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+*/
+proto.badezimmer.BadezimmerResponse.prototype.setEmpty = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 1, proto.badezimmer.BadezimmerResponse.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+ */
+proto.badezimmer.BadezimmerResponse.prototype.clearEmpty = function() {
+  return this.setEmpty(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.hasEmpty = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+/**
+ * optional ErrorDetails error = 2;
+ * @return {?proto.badezimmer.ErrorDetails}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.getError = function() {
+  return /** @type{?proto.badezimmer.ErrorDetails} */ (
+    jspb.Message.getWrapperField(this, proto.badezimmer.ErrorDetails, 2));
+};
+
+
+/**
+ * @param {?proto.badezimmer.ErrorDetails|undefined} value
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+*/
+proto.badezimmer.BadezimmerResponse.prototype.setError = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 2, proto.badezimmer.BadezimmerResponse.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+ */
+proto.badezimmer.BadezimmerResponse.prototype.clearError = function() {
+  return this.setError(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.hasError = function() {
+  return jspb.Message.getField(this, 2) != null;
+};
+
+
+/**
+ * optional ListConnectedDevicesResponse list_devices_response = 3;
+ * @return {?proto.badezimmer.ListConnectedDevicesResponse}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.getListDevicesResponse = function() {
+  return /** @type{?proto.badezimmer.ListConnectedDevicesResponse} */ (
+    jspb.Message.getWrapperField(this, proto.badezimmer.ListConnectedDevicesResponse, 3));
+};
+
+
+/**
+ * @param {?proto.badezimmer.ListConnectedDevicesResponse|undefined} value
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+*/
+proto.badezimmer.BadezimmerResponse.prototype.setListDevicesResponse = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 3, proto.badezimmer.BadezimmerResponse.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+ */
+proto.badezimmer.BadezimmerResponse.prototype.clearListDevicesResponse = function() {
+  return this.setListDevicesResponse(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.hasListDevicesResponse = function() {
+  return jspb.Message.getField(this, 3) != null;
+};
+
+
+/**
+ * optional SendActuatorCommandResponse send_actuator_command_response = 4;
+ * @return {?proto.badezimmer.SendActuatorCommandResponse}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.getSendActuatorCommandResponse = function() {
+  return /** @type{?proto.badezimmer.SendActuatorCommandResponse} */ (
+    jspb.Message.getWrapperField(this, proto.badezimmer.SendActuatorCommandResponse, 4));
+};
+
+
+/**
+ * @param {?proto.badezimmer.SendActuatorCommandResponse|undefined} value
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+*/
+proto.badezimmer.BadezimmerResponse.prototype.setSendActuatorCommandResponse = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 4, proto.badezimmer.BadezimmerResponse.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+ */
+proto.badezimmer.BadezimmerResponse.prototype.clearSendActuatorCommandResponse = function() {
+  return this.setSendActuatorCommandResponse(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.hasSendActuatorCommandResponse = function() {
+  return jspb.Message.getField(this, 4) != null;
+};
+
+
+/**
+ * optional GetHistoryResponse get_history_response = 5;
+ * @return {?proto.badezimmer.GetHistoryResponse}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.getGetHistoryResponse = function() {
+  return /** @type{?proto.badezimmer.GetHistoryResponse} */ (
+    jspb.Message.getWrapperField(this, proto.badezimmer.GetHistoryResponse, 5));
+};
+
+
+/**
+ * @param {?proto.badezimmer.GetHistoryResponse|undefined} value
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+*/
+proto.badezimmer.BadezimmerResponse.prototype.setGetHistoryResponse = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 5, proto.badezimmer.BadezimmerResponse.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+ */
+proto.badezimmer.BadezimmerResponse.prototype.clearGetHistoryResponse = function() {
+  return this.setGetHistoryResponse(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.hasGetHistoryResponse = function() {
+  return jspb.Message.getField(this, 5) != null;
+};
+
+
+/**
+ * optional LeakSample leak_update = 6;
+ * @return {?proto.badezimmer.LeakSample}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.getLeakUpdate = function() {
+  return /** @type{?proto.badezimmer.LeakSample} */ (
+    jspb.Message.getWrapperField(this, proto.badezimmer.LeakSample, 6));
+};
+
+
+/**
+ * @param {?proto.badezimmer.LeakSample|undefined} value
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+*/
+proto.badezimmer.BadezimmerResponse.prototype.setLeakUpdate = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 6, proto.badezimmer.BadezimmerResponse.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+ */
+proto.badezimmer.BadezimmerResponse.prototype.clearLeakUpdate = function() {
+  return this.setLeakUpdate(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.hasLeakUpdate = function() {
+  return jspb.Message.getField(this, 6) != null;
+};
+
+
+/**
+ * optional PongResponse pong = 7;
+ * @return {?proto.badezimmer.PongResponse}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.getPong = function() {
+  return /** @type{?proto.badezimmer.PongResponse} */ (
+    jspb.Message.getWrapperField(this, proto.badezimmer.PongResponse, 7));
+};
+
+
+/**
+ * @param {?proto.badezimmer.PongResponse|undefined} value
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+*/
+proto.badezimmer.BadezimmerResponse.prototype.setPong = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 7, proto.badezimmer.BadezimmerResponse.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+ */
+proto.badezimmer.BadezimmerResponse.prototype.clearPong = function() {
+  return this.setPong(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.hasPong = function() {
+  return jspb.Message.getField(this, 7) != null;
+};
+
+
+/**
+ * optional HelloResponse hello_response = 8;
+ * @return {?proto.badezimmer.HelloResponse}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.getHelloResponse = function() {
+  return /** @type{?proto.badezimmer.HelloResponse} */ (
+    jspb.Message.getWrapperField(this, proto.badezimmer.HelloResponse, 8));
+};
+
+
+/**
+ * @param {?proto.badezimmer.HelloResponse|undefined} value
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+*/
+proto.badezimmer.BadezimmerResponse.prototype.setHelloResponse = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 8, proto.badezimmer.BadezimmerResponse.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+ */
+proto.badezimmer.BadezimmerResponse.prototype.clearHelloResponse = function() {
+  return this.setHelloResponse(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.hasHelloResponse = function() {
+  return jspb.Message.getField(this, 8) != null;
+};
+
+
+/**
+ * optional ListServicesResponse list_services_response = 9;
+ * @return {?proto.badezimmer.ListServicesResponse}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.getListServicesResponse = function() {
+  return /** @type{?proto.badezimmer.ListServicesResponse} */ (
+    jspb.Message.getWrapperField(this, proto.badezimmer.ListServicesResponse, 9));
+};
+
+
+/**
+ * @param {?proto.badezimmer.ListServicesResponse|undefined} value
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+*/
+proto.badezimmer.BadezimmerResponse.prototype.setListServicesResponse = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 9, proto.badezimmer.BadezimmerResponse.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+ */
+proto.badezimmer.BadezimmerResponse.prototype.clearListServicesResponse = function() {
+  return this.setListServicesResponse(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.hasListServicesResponse = function() {
+  return jspb.Message.getField(this, 9) != null;
+};
+
+
+/**
+ * optional SeverityStatsResponse severity_stats_response = 10;
+ * @return {?proto.badezimmer.SeverityStatsResponse}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.getSeverityStatsResponse = function() {
+  return /** @type{?proto.badezimmer.SeverityStatsResponse} */ (
+    jspb.Message.getWrapperField(this, proto.badezimmer.SeverityStatsResponse, 10));
+};
+
+
+/**
+ * @param {?proto.badezimmer.SeverityStatsResponse|undefined} value
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+*/
+proto.badezimmer.BadezimmerResponse.prototype.setSeverityStatsResponse = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 10, proto.badezimmer.BadezimmerResponse.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+ */
+proto.badezimmer.BadezimmerResponse.prototype.clearSeverityStatsResponse = function() {
+  return this.setSeverityStatsResponse(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.hasSeverityStatsResponse = function() {
+  return jspb.Message.getField(this, 10) != null;
+};
+
+
+/**
+ * optional LeakSample status_response = 11;
+ * @return {?proto.badezimmer.LeakSample}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.getStatusResponse = function() {
+  return /** @type{?proto.badezimmer.LeakSample} */ (
+    jspb.Message.getWrapperField(this, proto.badezimmer.LeakSample, 11));
+};
+
+
+/**
+ * @param {?proto.badezimmer.LeakSample|undefined} value
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+*/
+proto.badezimmer.BadezimmerResponse.prototype.setStatusResponse = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 11, proto.badezimmer.BadezimmerResponse.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+ */
+proto.badezimmer.BadezimmerResponse.prototype.clearStatusResponse = function() {
+  return this.setStatusResponse(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.hasStatusResponse = function() {
+  return jspb.Message.getField(this, 11) != null;
+};
+
+
+/**
+ * optional string request_id = 16;
+ * @return {string}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.getRequestId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 16, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+ */
+proto.badezimmer.BadezimmerResponse.prototype.setRequestId = function(value) {
+  return jspb.Message.setProto3StringField(this, 16, value);
+};
+
+
+/**
+ * map<string, string> headers = 17;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,string>}
+ */
+proto.badezimmer.BadezimmerResponse.prototype.getHeadersMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,string>} */ (
+      jspb.Message.getMapField(this, 17, opt_noLazyCreate,
+      null));
+};
+
+
+/**
+ * Clears values from the map. The map will be non-null.
+ * @return {!proto.badezimmer.BadezimmerResponse} returns this
+ */
+proto.badezimmer.BadezimmerResponse.prototype.clearHeadersMap = function() {
+  this.getHeadersMap().clear();
+  return this;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.badezimmer.SendActuatorCommandResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.badezimmer.SendActuatorCommandResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.badezimmer.SendActuatorCommandResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.SendActuatorCommandResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+message: (f = jspb.Message.getField(msg, 2)) == null ? undefined : f
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.binary.bytesource.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.badezimmer.SendActuatorCommandResponse}
+ */
+proto.badezimmer.SendActuatorCommandResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.badezimmer.SendActuatorCommandResponse;
+  return proto.badezimmer.SendActuatorCommandResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.badezimmer.SendActuatorCommandResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.badezimmer.SendActuatorCommandResponse}
+ */
+proto.badezimmer.SendActuatorCommandResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 2:
+      var value = /** @type {string} */ (reader.readStringRequireUtf8());
+      msg.setMessage(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.badezimmer.SendActuatorCommandResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.badezimmer.SendActuatorCommandResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.badezimmer.SendActuatorCommandResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.SendActuatorCommandResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = /** @type {string} */ (jspb.Message.getField(message, 2));
+  if (f != null) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string message = 2;
+ * @return {string}
+ */
+proto.badezimmer.SendActuatorCommandResponse.prototype.getMessage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.badezimmer.SendActuatorCommandResponse} returns this
+ */
+proto.badezimmer.SendActuatorCommandResponse.prototype.setMessage = function(value) {
+  return jspb.Message.setField(this, 2, value);
+};
+
+
+/**
+ * Clears the field making it undefined.
+ * @return {!proto.badezimmer.SendActuatorCommandResponse} returns this
+ */
+proto.badezimmer.SendActuatorCommandResponse.prototype.clearMessage = function() {
+  return jspb.Message.setField(this, 2, undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.badezimmer.SendActuatorCommandResponse.prototype.hasMessage = function() {
+  return jspb.Message.getField(this, 2) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.badezimmer.GetHistoryRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.badezimmer.GetHistoryRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.badezimmer.GetHistoryRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.GetHistoryRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.binary.bytesource.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.badezimmer.GetHistoryRequest}
+ */
+proto.badezimmer.GetHistoryRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.badezimmer.GetHistoryRequest;
+  return proto.badezimmer.GetHistoryRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.badezimmer.GetHistoryRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.badezimmer.GetHistoryRequest}
+ */
+proto.badezimmer.GetHistoryRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.badezimmer.GetHistoryRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.badezimmer.GetHistoryRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.badezimmer.GetHistoryRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.GetHistoryRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.badezimmer.GetStatusRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.badezimmer.GetStatusRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.badezimmer.GetStatusRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.GetStatusRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.binary.bytesource.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.badezimmer.GetStatusRequest}
+ */
+proto.badezimmer.GetStatusRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.badezimmer.GetStatusRequest;
+  return proto.badezimmer.GetStatusRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.badezimmer.GetStatusRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.badezimmer.GetStatusRequest}
+ */
+proto.badezimmer.GetStatusRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.badezimmer.GetStatusRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.badezimmer.GetStatusRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.badezimmer.GetStatusRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.GetStatusRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.badezimmer.LeakSample.prototype.toObject = function(opt_includeInstance) {
+  return proto.badezimmer.LeakSample.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.badezimmer.LeakSample} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.LeakSample.toObject = function(includeInstance, msg) {
+  var f, obj = {
+severity: jspb.Message.getFieldWithDefault(msg, 1, ""),
+location: jspb.Message.getFieldWithDefault(msg, 2, ""),
+timestamp: (f = msg.getTimestamp()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.binary.bytesource.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.badezimmer.LeakSample}
+ */
+proto.badezimmer.LeakSample.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.badezimmer.LeakSample;
+  return proto.badezimmer.LeakSample.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.badezimmer.LeakSample} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.badezimmer.LeakSample}
+ */
+proto.badezimmer.LeakSample.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readStringRequireUtf8());
+      msg.setSeverity(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readStringRequireUtf8());
+      msg.setLocation(value);
+      break;
+    case 3:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setTimestamp(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.badezimmer.LeakSample.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.badezimmer.LeakSample.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.badezimmer.LeakSample} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.LeakSample.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getSeverity();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getLocation();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getTimestamp();
+  if (f != null) {
+    writer.writeMessage(
+      3,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional string severity = 1;
+ * @return {string}
+ */
+proto.badezimmer.LeakSample.prototype.getSeverity = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.badezimmer.LeakSample} returns this
+ */
+proto.badezimmer.LeakSample.prototype.setSeverity = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string location = 2;
+ * @return {string}
+ */
+proto.badezimmer.LeakSample.prototype.getLocation = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.badezimmer.LeakSample} returns this
+ */
+proto.badezimmer.LeakSample.prototype.setLocation = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional google.protobuf.Timestamp timestamp = 3;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.badezimmer.LeakSample.prototype.getTimestamp = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 3));
+};
+
+
+/**
+ * @param {?proto.google.protobuf.Timestamp|undefined} value
+ * @return {!proto.badezimmer.LeakSample} returns this
+*/
+proto.badezimmer.LeakSample.prototype.setTimestamp = function(value) {
+  return jspb.Message.setWrapperField(this, 3, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.badezimmer.LeakSample} returns this
+ */
+proto.badezimmer.LeakSample.prototype.clearTimestamp = function() {
+  return this.setTimestamp(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.badezimmer.LeakSample.prototype.hasTimestamp = function() {
+  return jspb.Message.getField(this, 3) != null;
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.badezimmer.GetHistoryResponse.repeatedFields_ = [1];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.badezimmer.GetHistoryResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.badezimmer.GetHistoryResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.badezimmer.GetHistoryResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.GetHistoryResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+samplesList: jspb.Message.toObjectList(msg.getSamplesList(),
+    proto.badezimmer.LeakSample.toObject, includeInstance)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.binary.bytesource.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.badezimmer.GetHistoryResponse}
+ */
+proto.badezimmer.GetHistoryResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.badezimmer.GetHistoryResponse;
+  return proto.badezimmer.GetHistoryResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.badezimmer.GetHistoryResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.badezimmer.GetHistoryResponse}
+ */
+proto.badezimmer.GetHistoryResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new proto.badezimmer.LeakSample;
+      reader.readMessage(value,proto.badezimmer.LeakSample.deserializeBinaryFromReader);
+      msg.addSamples(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.badezimmer.GetHistoryResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.badezimmer.GetHistoryResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.badezimmer.GetHistoryResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.GetHistoryResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getSamplesList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      1,
+      f,
+      proto.badezimmer.LeakSample.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * repeated LeakSample samples = 1;
+ * @return {!Array<!proto.badezimmer.LeakSample>}
+ */
+proto.badezimmer.GetHistoryResponse.prototype.getSamplesList = function() {
+  return /** @type{!Array<!proto.badezimmer.LeakSample>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.badezimmer.LeakSample, 1));
+};
+
+
+/**
+ * @param {!Array<!proto.badezimmer.LeakSample>} value
+ * @return {!proto.badezimmer.GetHistoryResponse} returns this
+*/
+proto.badezimmer.GetHistoryResponse.prototype.setSamplesList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 1, value);
+};
+
+
+/**
+ * @param {!proto.badezimmer.LeakSample=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.badezimmer.LeakSample}
+ */
+proto.badezimmer.GetHistoryResponse.prototype.addSamples = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 1, opt_value, proto.badezimmer.LeakSample, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.badezimmer.GetHistoryResponse} returns this
+ */
+proto.badezimmer.GetHistoryResponse.prototype.clearSamplesList = function() {
+  return this.setSamplesList([]);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.badezimmer.SubscribeRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.badezimmer.SubscribeRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.badezimmer.SubscribeRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.SubscribeRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.binary.bytesource.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.badezimmer.SubscribeRequest}
+ */
+proto.badezimmer.SubscribeRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.badezimmer.SubscribeRequest;
+  return proto.badezimmer.SubscribeRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.badezimmer.SubscribeRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.badezimmer.SubscribeRequest}
+ */
+proto.badezimmer.SubscribeRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.badezimmer.SubscribeRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.badezimmer.SubscribeRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.badezimmer.SubscribeRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.SubscribeRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.badezimmer.PingRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.badezimmer.PingRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.badezimmer.PingRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.PingRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.binary.bytesource.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.badezimmer.PingRequest}
+ */
+proto.badezimmer.PingRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.badezimmer.PingRequest;
+  return proto.badezimmer.PingRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.badezimmer.PingRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.badezimmer.PingRequest}
+ */
+proto.badezimmer.PingRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.badezimmer.PingRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.badezimmer.PingRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.badezimmer.PingRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.PingRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.badezimmer.PongResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.badezimmer.PongResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.badezimmer.PongResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.PongResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+serverTime: (f = msg.getServerTime()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.binary.bytesource.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.badezimmer.PongResponse}
+ */
+proto.badezimmer.PongResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.badezimmer.PongResponse;
+  return proto.badezimmer.PongResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.badezimmer.PongResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.badezimmer.PongResponse}
+ */
+proto.badezimmer.PongResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setServerTime(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.badezimmer.PongResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.badezimmer.PongResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.badezimmer.PongResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.PongResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getServerTime();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional google.protobuf.Timestamp server_time = 1;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.badezimmer.PongResponse.prototype.getServerTime = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 1));
+};
+
+
+/**
+ * @param {?proto.google.protobuf.Timestamp|undefined} value
+ * @return {!proto.badezimmer.PongResponse} returns this
+*/
+proto.badezimmer.PongResponse.prototype.setServerTime = function(value) {
+  return jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.badezimmer.PongResponse} returns this
+ */
+proto.badezimmer.PongResponse.prototype.clearServerTime = function() {
+  return this.setServerTime(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.badezimmer.PongResponse.prototype.hasServerTime = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.badezimmer.HelloRequest.repeatedFields_ = [1];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.badezimmer.HelloRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.badezimmer.HelloRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.badezimmer.HelloRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.HelloRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+compressionList: (f = jspb.Message.getRepeatedField(msg, 1)) == null ? undefined : f
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.binary.bytesource.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.badezimmer.HelloRequest}
+ */
+proto.badezimmer.HelloRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.badezimmer.HelloRequest;
+  return proto.badezimmer.HelloRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.badezimmer.HelloRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.badezimmer.HelloRequest}
+ */
+proto.badezimmer.HelloRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readStringRequireUtf8());
+      msg.addCompression(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.badezimmer.HelloRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.badezimmer.HelloRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.badezimmer.HelloRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.HelloRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getCompressionList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * repeated string compression = 1;
+ * @return {!Array<string>}
+ */
+proto.badezimmer.HelloRequest.prototype.getCompressionList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 1));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.badezimmer.HelloRequest} returns this
+ */
+proto.badezimmer.HelloRequest.prototype.setCompressionList = function(value) {
+  return jspb.Message.setField(this, 1, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.badezimmer.HelloRequest} returns this
+ */
+proto.badezimmer.HelloRequest.prototype.addCompression = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 1, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.badezimmer.HelloRequest} returns this
+ */
+proto.badezimmer.HelloRequest.prototype.clearCompressionList = function() {
+  return this.setCompressionList([]);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.badezimmer.HelloResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.badezimmer.HelloResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.badezimmer.HelloResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.HelloResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+compression: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.binary.bytesource.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.badezimmer.HelloResponse}
+ */
+proto.badezimmer.HelloResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.badezimmer.HelloResponse;
+  return proto.badezimmer.HelloResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.badezimmer.HelloResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.badezimmer.HelloResponse}
+ */
+proto.badezimmer.HelloResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readStringRequireUtf8());
+      msg.setCompression(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.badezimmer.HelloResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.badezimmer.HelloResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.badezimmer.HelloResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.HelloResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getCompression();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string compression = 1;
+ * @return {string}
+ */
+proto.badezimmer.HelloResponse.prototype.getCompression = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.badezimmer.HelloResponse} returns this
+ */
+proto.badezimmer.HelloResponse.prototype.setCompression = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.badezimmer.ListServicesRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.badezimmer.ListServicesRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.badezimmer.ListServicesRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.ListServicesRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.binary.bytesource.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.badezimmer.ListServicesRequest}
+ */
+proto.badezimmer.ListServicesRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.badezimmer.ListServicesRequest;
+  return proto.badezimmer.ListServicesRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.badezimmer.ListServicesRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.badezimmer.ListServicesRequest}
+ */
+proto.badezimmer.ListServicesRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.badezimmer.ListServicesRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.badezimmer.ListServicesRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.badezimmer.ListServicesRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.ListServicesRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.badezimmer.ServiceTypeCount.prototype.toObject = function(opt_includeInstance) {
+  return proto.badezimmer.ServiceTypeCount.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.badezimmer.ServiceTypeCount} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.ServiceTypeCount.toObject = function(includeInstance, msg) {
+  var f, obj = {
+type: jspb.Message.getFieldWithDefault(msg, 1, ""),
+instances: jspb.Message.getFieldWithDefault(msg, 2, 0)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.binary.bytesource.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.badezimmer.ServiceTypeCount}
+ */
+proto.badezimmer.ServiceTypeCount.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.badezimmer.ServiceTypeCount;
+  return proto.badezimmer.ServiceTypeCount.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.badezimmer.ServiceTypeCount} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.badezimmer.ServiceTypeCount}
+ */
+proto.badezimmer.ServiceTypeCount.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readStringRequireUtf8());
+      msg.setType(value);
+      break;
+    case 2:
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setInstances(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.badezimmer.ServiceTypeCount.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.badezimmer.ServiceTypeCount.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.badezimmer.ServiceTypeCount} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.ServiceTypeCount.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getType();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getInstances();
+  if (f !== 0) {
+    writer.writeInt32(
+      2,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string type = 1;
+ * @return {string}
+ */
+proto.badezimmer.ServiceTypeCount.prototype.getType = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.badezimmer.ServiceTypeCount} returns this
+ */
+proto.badezimmer.ServiceTypeCount.prototype.setType = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional int32 instances = 2;
+ * @return {number}
+ */
+proto.badezimmer.ServiceTypeCount.prototype.getInstances = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.badezimmer.ServiceTypeCount} returns this
+ */
+proto.badezimmer.ServiceTypeCount.prototype.setInstances = function(value) {
+  return jspb.Message.setProto3IntField(this, 2, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.badezimmer.ListServicesResponse.repeatedFields_ = [1];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.badezimmer.ListServicesResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.badezimmer.ListServicesResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.badezimmer.ListServicesResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.ListServicesResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+servicesList: jspb.Message.toObjectList(msg.getServicesList(),
+    proto.badezimmer.ServiceTypeCount.toObject, includeInstance)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.binary.bytesource.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.badezimmer.ListServicesResponse}
+ */
+proto.badezimmer.ListServicesResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.badezimmer.ListServicesResponse;
+  return proto.badezimmer.ListServicesResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.badezimmer.ListServicesResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.badezimmer.ListServicesResponse}
+ */
+proto.badezimmer.ListServicesResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new proto.badezimmer.ServiceTypeCount;
+      reader.readMessage(value,proto.badezimmer.ServiceTypeCount.deserializeBinaryFromReader);
+      msg.addServices(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.badezimmer.ListServicesResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.badezimmer.ListServicesResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.badezimmer.ListServicesResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.ListServicesResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getServicesList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      1,
+      f,
+      proto.badezimmer.ServiceTypeCount.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * repeated ServiceTypeCount services = 1;
+ * @return {!Array<!proto.badezimmer.ServiceTypeCount>}
+ */
+proto.badezimmer.ListServicesResponse.prototype.getServicesList = function() {
+  return /** @type{!Array<!proto.badezimmer.ServiceTypeCount>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.badezimmer.ServiceTypeCount, 1));
+};
+
+
+/**
+ * @param {!Array<!proto.badezimmer.ServiceTypeCount>} value
+ * @return {!proto.badezimmer.ListServicesResponse} returns this
+*/
+proto.badezimmer.ListServicesResponse.prototype.setServicesList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 1, value);
+};
+
+
+/**
+ * @param {!proto.badezimmer.ServiceTypeCount=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.badezimmer.ServiceTypeCount}
+ */
+proto.badezimmer.ListServicesResponse.prototype.addServices = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 1, opt_value, proto.badezimmer.ServiceTypeCount, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.badezimmer.ListServicesResponse} returns this
+ */
+proto.badezimmer.ListServicesResponse.prototype.clearServicesList = function() {
+  return this.setServicesList([]);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.badezimmer.GetSeverityStatsRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.badezimmer.GetSeverityStatsRequest.toObject(opt_includeInstance, this);
 };
 
 
 /**
- * optional ListConnectedDevicesResponse list_devices_response = 3;
- * @return {?proto.badezimmer.ListConnectedDevicesResponse}
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.badezimmer.GetSeverityStatsRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.badezimmer.BadezimmerResponse.prototype.getListDevicesResponse = function() {
-  return /** @type{?proto.badezimmer.ListConnectedDevicesResponse} */ (
-    jspb.Message.getWrapperField(this, proto.badezimmer.ListConnectedDevicesResponse, 3));
-};
-
+proto.badezimmer.GetSeverityStatsRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+windowSeconds: jspb.Message.getFieldWithDefault(msg, 1, 0)
+  };
 
-/**
- * @param {?proto.badezimmer.ListConnectedDevicesResponse|undefined} value
- * @return {!proto.badezimmer.BadezimmerResponse} returns this
-*/
-proto.badezimmer.BadezimmerResponse.prototype.setListDevicesResponse = function(value) {
-  return jspb.Message.setOneofWrapperField(this, 3, proto.badezimmer.BadezimmerResponse.oneofGroups_[0], value);
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
 };
+}
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.badezimmer.BadezimmerResponse} returns this
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.binary.bytesource.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.badezimmer.GetSeverityStatsRequest}
  */
-proto.badezimmer.BadezimmerResponse.prototype.clearListDevicesResponse = function() {
-  return this.setListDevicesResponse(undefined);
+proto.badezimmer.GetSeverityStatsRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.badezimmer.GetSeverityStatsRequest;
+  return proto.badezimmer.GetSeverityStatsRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.badezimmer.GetSeverityStatsRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.badezimmer.GetSeverityStatsRequest}
  */
-proto.badezimmer.BadezimmerResponse.prototype.hasListDevicesResponse = function() {
-  return jspb.Message.getField(this, 3) != null;
+proto.badezimmer.GetSeverityStatsRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {number} */ (reader.readUint32());
+      msg.setWindowSeconds(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
 };
 
 
 /**
- * optional SendActuatorCommandResponse send_actuator_command_response = 4;
- * @return {?proto.badezimmer.SendActuatorCommandResponse}
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
  */
-proto.badezimmer.BadezimmerResponse.prototype.getSendActuatorCommandResponse = function() {
-  return /** @type{?proto.badezimmer.SendActuatorCommandResponse} */ (
-    jspb.Message.getWrapperField(this, proto.badezimmer.SendActuatorCommandResponse, 4));
+proto.badezimmer.GetSeverityStatsRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.badezimmer.GetSeverityStatsRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
 };
 
 
 /**
- * @param {?proto.badezimmer.SendActuatorCommandResponse|undefined} value
- * @return {!proto.badezimmer.BadezimmerResponse} returns this
-*/
-proto.badezimmer.BadezimmerResponse.prototype.setSendActuatorCommandResponse = function(value) {
-  return jspb.Message.setOneofWrapperField(this, 4, proto.badezimmer.BadezimmerResponse.oneofGroups_[0], value);
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.badezimmer.GetSeverityStatsRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.badezimmer.GetSeverityStatsRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getWindowSeconds();
+  if (f !== 0) {
+    writer.writeUint32(
+      1,
+      f
+    );
+  }
 };
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.badezimmer.BadezimmerResponse} returns this
+ * optional uint32 window_seconds = 1;
+ * @return {number}
  */
-proto.badezimmer.BadezimmerResponse.prototype.clearSendActuatorCommandResponse = function() {
-  return this.setSendActuatorCommandResponse(undefined);
+proto.badezimmer.GetSeverityStatsRequest.prototype.getWindowSeconds = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * @param {number} value
+ * @return {!proto.badezimmer.GetSeverityStatsRequest} returns this
  */
-proto.badezimmer.BadezimmerResponse.prototype.hasSendActuatorCommandResponse = function() {
-  return jspb.Message.getField(this, 4) != null;
+proto.badezimmer.GetSeverityStatsRequest.prototype.setWindowSeconds = function(value) {
+  return jspb.Message.setProto3IntField(this, 1, value);
 };
 
 
@@ -2316,8 +5401,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.badezimmer.SendActuatorCommandResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.badezimmer.SendActuatorCommandResponse.toObject(opt_includeInstance, this);
+proto.badezimmer.SeverityStatsResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.badezimmer.SeverityStatsResponse.toObject(opt_includeInstance, this);
 };
 
 
@@ -2326,13 +5411,16 @@ proto.badezimmer.SendActuatorCommandResponse.prototype.toObject = function(opt_i
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.badezimmer.SendActuatorCommandResponse} msg The msg instance to transform.
+ * @param {!proto.badezimmer.SeverityStatsResponse} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.badezimmer.SendActuatorCommandResponse.toObject = function(includeInstance, msg) {
+proto.badezimmer.SeverityStatsResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
-message: (f = jspb.Message.getField(msg, 2)) == null ? undefined : f
+min: jspb.Message.getFieldWithDefault(msg, 1, 0),
+max: jspb.Message.getFieldWithDefault(msg, 2, 0),
+average: jspb.Message.getFloatingPointFieldWithDefault(msg, 3, 0.0),
+count: jspb.Message.getFieldWithDefault(msg, 4, 0)
   };
 
   if (includeInstance) {
@@ -2346,32 +5434,44 @@ message: (f = jspb.Message.getField(msg, 2)) == null ? undefined : f
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.binary.bytesource.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.badezimmer.SendActuatorCommandResponse}
+ * @return {!proto.badezimmer.SeverityStatsResponse}
  */
-proto.badezimmer.SendActuatorCommandResponse.deserializeBinary = function(bytes) {
+proto.badezimmer.SeverityStatsResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.badezimmer.SendActuatorCommandResponse;
-  return proto.badezimmer.SendActuatorCommandResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.badezimmer.SeverityStatsResponse;
+  return proto.badezimmer.SeverityStatsResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.badezimmer.SendActuatorCommandResponse} msg The message object to deserialize into.
+ * @param {!proto.badezimmer.SeverityStatsResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.badezimmer.SendActuatorCommandResponse}
+ * @return {!proto.badezimmer.SeverityStatsResponse}
  */
-proto.badezimmer.SendActuatorCommandResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.badezimmer.SeverityStatsResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
     }
     var field = reader.getFieldNumber();
     switch (field) {
+    case 1:
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setMin(value);
+      break;
     case 2:
-      var value = /** @type {string} */ (reader.readStringRequireUtf8());
-      msg.setMessage(value);
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setMax(value);
+      break;
+    case 3:
+      var value = /** @type {number} */ (reader.readDouble());
+      msg.setAverage(value);
+      break;
+    case 4:
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setCount(value);
       break;
     default:
       reader.skipField();
@@ -2386,9 +5486,9 @@ proto.badezimmer.SendActuatorCommandResponse.deserializeBinaryFromReader = funct
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.badezimmer.SendActuatorCommandResponse.prototype.serializeBinary = function() {
+proto.badezimmer.SeverityStatsResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.badezimmer.SendActuatorCommandResponse.serializeBinaryToWriter(this, writer);
+  proto.badezimmer.SeverityStatsResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -2396,55 +5496,112 @@ proto.badezimmer.SendActuatorCommandResponse.prototype.serializeBinary = functio
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.badezimmer.SendActuatorCommandResponse} message
+ * @param {!proto.badezimmer.SeverityStatsResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.badezimmer.SendActuatorCommandResponse.serializeBinaryToWriter = function(message, writer) {
+proto.badezimmer.SeverityStatsResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = /** @type {string} */ (jspb.Message.getField(message, 2));
-  if (f != null) {
-    writer.writeString(
+  f = message.getMin();
+  if (f !== 0) {
+    writer.writeInt32(
+      1,
+      f
+    );
+  }
+  f = message.getMax();
+  if (f !== 0) {
+    writer.writeInt32(
       2,
       f
     );
   }
+  f = message.getAverage();
+  if (f !== 0.0) {
+    writer.writeDouble(
+      3,
+      f
+    );
+  }
+  f = message.getCount();
+  if (f !== 0) {
+    writer.writeInt32(
+      4,
+      f
+    );
+  }
 };
 
 
 /**
- * optional string message = 2;
- * @return {string}
+ * optional int32 min = 1;
+ * @return {number}
  */
-proto.badezimmer.SendActuatorCommandResponse.prototype.getMessage = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+proto.badezimmer.SeverityStatsResponse.prototype.getMin = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.badezimmer.SendActuatorCommandResponse} returns this
+ * @param {number} value
+ * @return {!proto.badezimmer.SeverityStatsResponse} returns this
  */
-proto.badezimmer.SendActuatorCommandResponse.prototype.setMessage = function(value) {
-  return jspb.Message.setField(this, 2, value);
+proto.badezimmer.SeverityStatsResponse.prototype.setMin = function(value) {
+  return jspb.Message.setProto3IntField(this, 1, value);
 };
 
 
 /**
- * Clears the field making it undefined.
- * @return {!proto.badezimmer.SendActuatorCommandResponse} returns this
+ * optional int32 max = 2;
+ * @return {number}
  */
-proto.badezimmer.SendActuatorCommandResponse.prototype.clearMessage = function() {
-  return jspb.Message.setField(this, 2, undefined);
+proto.badezimmer.SeverityStatsResponse.prototype.getMax = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * @param {number} value
+ * @return {!proto.badezimmer.SeverityStatsResponse} returns this
  */
-proto.badezimmer.SendActuatorCommandResponse.prototype.hasMessage = function() {
-  return jspb.Message.getField(this, 2) != null;
+proto.badezimmer.SeverityStatsResponse.prototype.setMax = function(value) {
+  return jspb.Message.setProto3IntField(this, 2, value);
+};
+
+
+/**
+ * optional double average = 3;
+ * @return {number}
+ */
+proto.badezimmer.SeverityStatsResponse.prototype.getAverage = function() {
+  return /** @type {number} */ (jspb.Message.getFloatingPointFieldWithDefault(this, 3, 0.0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.badezimmer.SeverityStatsResponse} returns this
+ */
+proto.badezimmer.SeverityStatsResponse.prototype.setAverage = function(value) {
+  return jspb.Message.setProto3FloatField(this, 3, value);
+};
+
+
+/**
+ * optional int32 count = 4;
+ * @return {number}
+ */
+proto.badezimmer.SeverityStatsResponse.prototype.getCount = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 4, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.badezimmer.SeverityStatsResponse} returns this
+ */
+proto.badezimmer.SeverityStatsResponse.prototype.setCount = function(value) {
+  return jspb.Message.setProto3IntField(this, 4, value);
 };
 
 
@@ -3139,7 +6296,7 @@ proto.badezimmer.MDNSQuestion.prototype.setType = function(value) {
  * @private {!Array<number>}
  * @const
  */
-proto.badezimmer.MDNSQueryRequest.repeatedFields_ = [1];
+proto.badezimmer.MDNSQueryRequest.repeatedFields_ = [1,2];
 
 
 
@@ -3173,7 +6330,9 @@ proto.badezimmer.MDNSQueryRequest.prototype.toObject = function(opt_includeInsta
 proto.badezimmer.MDNSQueryRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
 questionsList: jspb.Message.toObjectList(msg.getQuestionsList(),
-    proto.badezimmer.MDNSQuestion.toObject, includeInstance)
+    proto.badezimmer.MDNSQuestion.toObject, includeInstance),
+authorityRecordsList: jspb.Message.toObjectList(msg.getAuthorityRecordsList(),
+    proto.badezimmer.MDNSRecord.toObject, includeInstance)
   };
 
   if (includeInstance) {
@@ -3215,6 +6374,11 @@ proto.badezimmer.MDNSQueryRequest.deserializeBinaryFromReader = function(msg, re
       reader.readMessage(value,proto.badezimmer.MDNSQuestion.deserializeBinaryFromReader);
       msg.addQuestions(value);
       break;
+    case 2:
+      var value = new proto.badezimmer.MDNSRecord;
+      reader.readMessage(value,proto.badezimmer.MDNSRecord.deserializeBinaryFromReader);
+      msg.addAuthorityRecords(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -3252,6 +6416,14 @@ proto.badezimmer.MDNSQueryRequest.serializeBinaryToWriter = function(message, wr
       proto.badezimmer.MDNSQuestion.serializeBinaryToWriter
     );
   }
+  f = message.getAuthorityRecordsList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      2,
+      f,
+      proto.badezimmer.MDNSRecord.serializeBinaryToWriter
+    );
+  }
 };
 
 
@@ -3293,6 +6465,44 @@ proto.badezimmer.MDNSQueryRequest.prototype.clearQuestionsList = function() {
 };
 
 
+/**
+ * repeated MDNSRecord authority_records = 2;
+ * @return {!Array<!proto.badezimmer.MDNSRecord>}
+ */
+proto.badezimmer.MDNSQueryRequest.prototype.getAuthorityRecordsList = function() {
+  return /** @type{!Array<!proto.badezimmer.MDNSRecord>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.badezimmer.MDNSRecord, 2));
+};
+
+
+/**
+ * @param {!Array<!proto.badezimmer.MDNSRecord>} value
+ * @return {!proto.badezimmer.MDNSQueryRequest} returns this
+*/
+proto.badezimmer.MDNSQueryRequest.prototype.setAuthorityRecordsList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 2, value);
+};
+
+
+/**
+ * @param {!proto.badezimmer.MDNSRecord=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.badezimmer.MDNSRecord}
+ */
+proto.badezimmer.MDNSQueryRequest.prototype.addAuthorityRecords = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 2, opt_value, proto.badezimmer.MDNSRecord, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.badezimmer.MDNSQueryRequest} returns this
+ */
+proto.badezimmer.MDNSQueryRequest.prototype.clearAuthorityRecordsList = function() {
+  return this.setAuthorityRecordsList([]);
+};
+
+
 
 
 
@@ -4758,7 +7968,8 @@ proto.badezimmer.MDNS.toObject = function(includeInstance, msg) {
 transactionId: jspb.Message.getFieldWithDefault(msg, 1, 0),
 timestamp: (f = msg.getTimestamp()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
 queryRequest: (f = msg.getQueryRequest()) && proto.badezimmer.MDNSQueryRequest.toObject(includeInstance, f),
-queryResponse: (f = msg.getQueryResponse()) && proto.badezimmer.MDNSQueryResponse.toObject(includeInstance, f)
+queryResponse: (f = msg.getQueryResponse()) && proto.badezimmer.MDNSQueryResponse.toObject(includeInstance, f),
+instanceId: jspb.Message.getFieldWithDefault(msg, 5, "")
   };
 
   if (includeInstance) {
@@ -4814,6 +8025,10 @@ proto.badezimmer.MDNS.deserializeBinaryFromReader = function(msg, reader) {
       reader.readMessage(value,proto.badezimmer.MDNSQueryResponse.deserializeBinaryFromReader);
       msg.setQueryResponse(value);
       break;
+    case 5:
+      var value = /** @type {string} */ (reader.readStringRequireUtf8());
+      msg.setInstanceId(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -4874,6 +8089,13 @@ proto.badezimmer.MDNS.serializeBinaryToWriter = function(message, writer) {
       proto.badezimmer.MDNSQueryResponse.serializeBinaryToWriter
     );
   }
+  f = message.getInstanceId();
+  if (f.length > 0) {
+    writer.writeString(
+      5,
+      f
+    );
+  }
 };
 
 
@@ -5006,6 +8228,24 @@ proto.badezimmer.MDNS.prototype.hasQueryResponse = function() {
 };
 
 
+/**
+ * optional string instance_id = 5;
+ * @return {string}
+ */
+proto.badezimmer.MDNS.prototype.getInstanceId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 5, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.badezimmer.MDNS} returns this
+ */
+proto.badezimmer.MDNS.prototype.setInstanceId = function(value) {
+  return jspb.Message.setProto3StringField(this, 5, value);
+};
+
+
 /**
  * @enum {number}
  */
@@ -5033,7 +8273,8 @@ proto.badezimmer.DeviceCategory = {
   LIGHT_LAMP: 1,
   FART_DETECTOR: 2,
   TOILET: 3,
-  SINK: 4
+  SINK: 4,
+  WATER_LEAK: 5
 };
 
 /**
@@ -5053,7 +8294,9 @@ proto.badezimmer.ErrorCode = {
   DEVICE_NOT_FOUND: 1,
   INVALID_COMMAND: 2,
   DEVICE_OFFLINE: 3,
-  VALIDATION_ERROR: 4
+  VALIDATION_ERROR: 4,
+  UNAVAILABLE: 5,
+  UNAUTHENTICATED: 6
 };
 
 /**
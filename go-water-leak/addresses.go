@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"net"
+)
+
+// InterfaceAddrs is a network interface with its IPv4 addresses.
+type InterfaceAddrs struct {
+	Name  string
+	Index int
+	Flags net.Flags
+	IPs   []net.IP
+}
+
+// AddressProvider lists the interfaces and addresses the responder advertises
+// and joins the group on, so they can be controlled without touching the
+// host.
+type AddressProvider interface {
+	Interfaces() ([]InterfaceAddrs, error)
+}
+
+// SystemAddressProvider is the AddressProvider of the host interfaces.
+type SystemAddressProvider struct{}
+
+func (SystemAddressProvider) Interfaces() ([]InterfaceAddrs, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interfaces: %w", err)
+	}
+
+	result := make([]InterfaceAddrs, 0, len(ifaces))
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+
+		entry := InterfaceAddrs{Name: iface.Name, Index: iface.Index, Flags: iface.Flags}
+		for _, addr := range addrs {
+			var ip net.IP
+			switch v := addr.(type) {
+			case *net.IPNet:
+				ip = v.IP
+			case *net.IPAddr:
+				ip = v.IP
+			}
+			if ip4 := ip.To4(); ip4 != nil {
+				entry.IPs = append(entry.IPs, ip4)
+			}
+		}
+		result = append(result, entry)
+	}
+	return result, nil
+}
+
+// WithAddressProvider replaces the host interfaces, SystemAddressProvider by
+// default, used for multi-interface joins and the interface watch.
+func WithAddressProvider(provider AddressProvider) Option {
+	return func(m *BadezimmerMDNS) {
+		m.addressProvider = provider
+	}
+}
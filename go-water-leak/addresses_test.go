@@ -0,0 +1,119 @@
+package main
+
+import (
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// fakeAddressProvider is an AddressProvider whose interfaces the test sets.
+type fakeAddressProvider struct {
+	mu     sync.Mutex
+	ifaces []InterfaceAddrs
+}
+
+func newFakeAddressProvider(ifaces ...InterfaceAddrs) *fakeAddressProvider {
+	return &fakeAddressProvider{ifaces: ifaces}
+}
+
+func (p *fakeAddressProvider) Interfaces() ([]InterfaceAddrs, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]InterfaceAddrs(nil), p.ifaces...), nil
+}
+
+// set replaces the interfaces, as if the host network changed.
+func (p *fakeAddressProvider) set(ifaces ...InterfaceAddrs) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ifaces = ifaces
+}
+
+// fakeInterface is an up and running, multicast capable interface with ips.
+func fakeInterface(name string, index int, ips ...string) InterfaceAddrs {
+	iface := InterfaceAddrs{Name: name, Index: index, Flags: net.FlagUp | net.FlagRunning | net.FlagMulticast}
+	for _, ip := range ips {
+		iface.IPs = append(iface.IPs, net.ParseIP(ip).To4())
+	}
+	return iface
+}
+
+func TestLinkLocalOnlyAsLastResort(t *testing.T) {
+	provider := newFakeAddressProvider(
+		fakeInterface("lo", 1, "127.0.0.1"),
+		fakeInterface("eth0", 2, "169.254.10.20"),
+	)
+
+	w := NewWaterLeakDetector(8080, WithDeviceAddressProvider(provider))
+	if got := w.info.Addresses; len(got) != 0 {
+		t.Errorf("Addresses = %v, want the link-local address excluded", got)
+	}
+
+	w = NewWaterLeakDetector(8080, WithDeviceAddressProvider(provider), WithAddressFilter(AddressFilter{LastResort: true}))
+	if got, want := w.info.Addresses, []string{"169.254.10.20"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Addresses = %v, want %v as a last resort", got, want)
+	}
+}
+
+func TestFilterAddresses(t *testing.T) {
+	candidates := []string{"169.254.10.20", "100.64.0.5", "192.168.1.10"}
+	tests := []struct {
+		name       string
+		candidates []string
+		filter     AddressFilter
+		want       []string
+	}{
+		{"link-local dropped", candidates, AddressFilter{}, []string{"100.64.0.5", "192.168.1.10"}},
+		{"non-routable dropped", candidates, AddressFilter{ExcludeNonRoutable: true}, []string{"192.168.1.10"}},
+		{"last resort unused next to a better address", candidates, AddressFilter{ExcludeNonRoutable: true, LastResort: true}, []string{"192.168.1.10"}},
+		{"last resort", []string{"169.254.10.20", "100.64.0.5"}, AddressFilter{ExcludeNonRoutable: true, LastResort: true}, []string{"169.254.10.20", "100.64.0.5"}},
+		{"nothing left", []string{"169.254.10.20"}, AddressFilter{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterAddresses(tt.candidates, tt.filter); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterAddresses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalAddressesFromProvider(t *testing.T) {
+	provider := newFakeAddressProvider(
+		fakeInterface("lo", 1, "127.0.0.1"),
+		fakeInterface("docker0", 2, "172.17.0.1"),
+		fakeInterface("eth0", 3, "192.168.1.10", "10.0.0.5"),
+		// A bridge sharing the address of eth0
+		fakeInterface("br0", 4, "192.168.1.10"),
+	)
+
+	got := getLocalIPv4Addresses(provider, AddressFilter{})
+	if want := []string{"192.168.1.10", "10.0.0.5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("getLocalIPv4Addresses() = %v, want %v", got, want)
+	}
+}
+
+func TestMulticastInterfacesFromProvider(t *testing.T) {
+	down := fakeInterface("eth1", 3, "192.0.2.3")
+	down.Flags &^= net.FlagUp
+	noMulticast := fakeInterface("tun0", 4, "192.0.2.4")
+	noMulticast.Flags &^= net.FlagMulticast
+	loopback := fakeInterface("lo", 1, "127.0.0.1")
+	loopback.Flags |= net.FlagLoopback
+
+	provider := newFakeAddressProvider(
+		loopback,
+		fakeInterface("eth0", 2, "192.0.2.2", "192.0.2.20"),
+		down,
+		noMulticast,
+		fakeInterface("eth2", 5),
+	)
+	ifaces, err := multicastInterfaces(provider)
+	if err != nil {
+		t.Fatalf("multicastInterfaces: %v", err)
+	}
+	if len(ifaces) != 1 || ifaces[0].name != "eth0" || ifaces[0].index != 2 || !ifaces[0].ip.Equal(net.ParseIP("192.0.2.2")) {
+		t.Errorf("multicastInterfaces() = %+v, want eth0 by its first address", ifaces)
+	}
+}
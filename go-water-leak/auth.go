@@ -0,0 +1,49 @@
+package main
+
+import (
+	"crypto/subtle"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+)
+
+// AuthTokenHeader is the request header carrying the shared token set with
+// WithAuthToken.
+const AuthTokenHeader = "auth-token"
+
+// WithAuthToken requires requests that change the device, like actuator
+// commands, to carry token in their AuthTokenHeader header. Others get an
+// UNAUTHENTICATED error.
+func WithAuthToken(token string) DeviceOption {
+	return func(d *Device) {
+		d.authToken = token
+	}
+}
+
+// WithReadAuth also requires the token for read-only requests like history
+// and subscriptions. Ping and hello always stay open.
+func WithReadAuth(enabled bool) DeviceOption {
+	return func(d *Device) {
+		d.readAuth = enabled
+	}
+}
+
+// requiresAuth reports whether request must carry the token.
+func (d *Device) requiresAuth(request *badezimmer.BadezimmerRequest) bool {
+	if d.authToken == "" {
+		return false
+	}
+
+	switch request.GetRequest().(type) {
+	case *badezimmer.BadezimmerRequest_Ping, *badezimmer.BadezimmerRequest_Hello:
+		return false
+	case *badezimmer.BadezimmerRequest_SendActuatorCommand:
+		return true
+	}
+	return d.readAuth
+}
+
+// authenticated reports whether request carries the configured token.
+func (d *Device) authenticated(request *badezimmer.BadezimmerRequest) bool {
+	token := request.Headers[AuthTokenHeader]
+	return subtle.ConstantTimeCompare([]byte(token), []byte(d.authToken)) == 1
+}
@@ -239,6 +239,8 @@ const (
 	ErrorCode_INVALID_COMMAND  ErrorCode = 2
 	ErrorCode_DEVICE_OFFLINE   ErrorCode = 3
 	ErrorCode_VALIDATION_ERROR ErrorCode = 4
+	ErrorCode_UNAVAILABLE      ErrorCode = 5
+	ErrorCode_UNAUTHENTICATED  ErrorCode = 6
 )
 
 // Enum value maps for ErrorCode.
@@ -249,6 +251,8 @@ var (
 		2: "INVALID_COMMAND",
 		3: "DEVICE_OFFLINE",
 		4: "VALIDATION_ERROR",
+		5: "UNAVAILABLE",
+		6: "UNAUTHENTICATED",
 	}
 	ErrorCode_value = map[string]int32{
 		"UNKNOWN_ERROR":    0,
@@ -256,6 +260,8 @@ var (
 		"INVALID_COMMAND":  2,
 		"DEVICE_OFFLINE":   3,
 		"VALIDATION_ERROR": 4,
+		"UNAVAILABLE":      5,
+		"UNAUTHENTICATED":  6,
 	}
 )
 
@@ -699,7 +705,16 @@ type BadezimmerRequest struct {
 	//	*BadezimmerRequest_Empty
 	//	*BadezimmerRequest_ListDevices
 	//	*BadezimmerRequest_SendActuatorCommand
+	//	*BadezimmerRequest_GetHistory
+	//	*BadezimmerRequest_Subscribe
+	//	*BadezimmerRequest_Ping
+	//	*BadezimmerRequest_Hello
+	//	*BadezimmerRequest_ListServices
+	//	*BadezimmerRequest_GetSeverityStats
+	//	*BadezimmerRequest_GetStatus
 	Request       isBadezimmerRequest_Request `protobuf_oneof:"request"`
+	RequestId     string                      `protobuf:"bytes,16,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Headers       map[string]string           `protobuf:"bytes,17,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -768,56 +783,834 @@ func (x *BadezimmerRequest) GetSendActuatorCommand() *SendActuatorCommandRequest
 	return nil
 }
 
+func (x *BadezimmerRequest) GetGetHistory() *GetHistoryRequest {
+	if x != nil {
+		if x, ok := x.Request.(*BadezimmerRequest_GetHistory); ok {
+			return x.GetHistory
+		}
+	}
+	return nil
+}
+
+func (x *BadezimmerRequest) GetSubscribe() *SubscribeRequest {
+	if x != nil {
+		if x, ok := x.Request.(*BadezimmerRequest_Subscribe); ok {
+			return x.Subscribe
+		}
+	}
+	return nil
+}
+
+func (x *BadezimmerRequest) GetPing() *PingRequest {
+	if x != nil {
+		if x, ok := x.Request.(*BadezimmerRequest_Ping); ok {
+			return x.Ping
+		}
+	}
+	return nil
+}
+
+func (x *BadezimmerRequest) GetHello() *HelloRequest {
+	if x != nil {
+		if x, ok := x.Request.(*BadezimmerRequest_Hello); ok {
+			return x.Hello
+		}
+	}
+	return nil
+}
+
+func (x *BadezimmerRequest) GetListServices() *ListServicesRequest {
+	if x != nil {
+		if x, ok := x.Request.(*BadezimmerRequest_ListServices); ok {
+			return x.ListServices
+		}
+	}
+	return nil
+}
+
+func (x *BadezimmerRequest) GetGetSeverityStats() *GetSeverityStatsRequest {
+	if x != nil {
+		if x, ok := x.Request.(*BadezimmerRequest_GetSeverityStats); ok {
+			return x.GetSeverityStats
+		}
+	}
+	return nil
+}
+
+func (x *BadezimmerRequest) GetGetStatus() *GetStatusRequest {
+	if x != nil {
+		if x, ok := x.Request.(*BadezimmerRequest_GetStatus); ok {
+			return x.GetStatus
+		}
+	}
+	return nil
+}
+
+func (x *BadezimmerRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *BadezimmerRequest) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
 type isBadezimmerRequest_Request interface {
 	isBadezimmerRequest_Request()
 }
 
-type BadezimmerRequest_Empty struct {
-	Empty *emptypb.Empty `protobuf:"bytes,1,opt,name=empty,proto3,oneof"`
+type BadezimmerRequest_Empty struct {
+	Empty *emptypb.Empty `protobuf:"bytes,1,opt,name=empty,proto3,oneof"`
+}
+
+type BadezimmerRequest_ListDevices struct {
+	ListDevices *ListConnectedDevicesRequest `protobuf:"bytes,2,opt,name=list_devices,json=listDevices,proto3,oneof"`
+}
+
+type BadezimmerRequest_SendActuatorCommand struct {
+	SendActuatorCommand *SendActuatorCommandRequest `protobuf:"bytes,3,opt,name=send_actuator_command,json=sendActuatorCommand,proto3,oneof"`
+}
+
+type BadezimmerRequest_GetHistory struct {
+	GetHistory *GetHistoryRequest `protobuf:"bytes,4,opt,name=get_history,json=getHistory,proto3,oneof"`
+}
+
+type BadezimmerRequest_Subscribe struct {
+	Subscribe *SubscribeRequest `protobuf:"bytes,5,opt,name=subscribe,proto3,oneof"`
+}
+
+type BadezimmerRequest_Ping struct {
+	Ping *PingRequest `protobuf:"bytes,6,opt,name=ping,proto3,oneof"`
+}
+
+type BadezimmerRequest_Hello struct {
+	Hello *HelloRequest `protobuf:"bytes,7,opt,name=hello,proto3,oneof"`
+}
+
+type BadezimmerRequest_ListServices struct {
+	ListServices *ListServicesRequest `protobuf:"bytes,8,opt,name=list_services,json=listServices,proto3,oneof"`
+}
+
+type BadezimmerRequest_GetSeverityStats struct {
+	GetSeverityStats *GetSeverityStatsRequest `protobuf:"bytes,9,opt,name=get_severity_stats,json=getSeverityStats,proto3,oneof"`
+}
+
+type BadezimmerRequest_GetStatus struct {
+	GetStatus *GetStatusRequest `protobuf:"bytes,10,opt,name=get_status,json=getStatus,proto3,oneof"`
+}
+
+func (*BadezimmerRequest_Empty) isBadezimmerRequest_Request() {}
+
+func (*BadezimmerRequest_ListDevices) isBadezimmerRequest_Request() {}
+
+func (*BadezimmerRequest_SendActuatorCommand) isBadezimmerRequest_Request() {}
+
+func (*BadezimmerRequest_GetHistory) isBadezimmerRequest_Request() {}
+
+func (*BadezimmerRequest_Subscribe) isBadezimmerRequest_Request() {}
+
+func (*BadezimmerRequest_Ping) isBadezimmerRequest_Request() {}
+
+func (*BadezimmerRequest_Hello) isBadezimmerRequest_Request() {}
+
+func (*BadezimmerRequest_ListServices) isBadezimmerRequest_Request() {}
+
+func (*BadezimmerRequest_GetSeverityStats) isBadezimmerRequest_Request() {}
+
+func (*BadezimmerRequest_GetStatus) isBadezimmerRequest_Request() {}
+
+type BadezimmerResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Response:
+	//
+	//	*BadezimmerResponse_Empty
+	//	*BadezimmerResponse_Error
+	//	*BadezimmerResponse_ListDevicesResponse
+	//	*BadezimmerResponse_SendActuatorCommandResponse
+	//	*BadezimmerResponse_GetHistoryResponse
+	//	*BadezimmerResponse_LeakUpdate
+	//	*BadezimmerResponse_Pong
+	//	*BadezimmerResponse_HelloResponse
+	//	*BadezimmerResponse_ListServicesResponse
+	//	*BadezimmerResponse_SeverityStatsResponse
+	//	*BadezimmerResponse_StatusResponse
+	Response      isBadezimmerResponse_Response `protobuf_oneof:"response"`
+	RequestId     string                        `protobuf:"bytes,16,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Headers       map[string]string             `protobuf:"bytes,17,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BadezimmerResponse) Reset() {
+	*x = BadezimmerResponse{}
+	mi := &file_badezimmer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BadezimmerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BadezimmerResponse) ProtoMessage() {}
+
+func (x *BadezimmerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badezimmer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BadezimmerResponse.ProtoReflect.Descriptor instead.
+func (*BadezimmerResponse) Descriptor() ([]byte, []int) {
+	return file_badezimmer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BadezimmerResponse) GetResponse() isBadezimmerResponse_Response {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *BadezimmerResponse) GetEmpty() *emptypb.Empty {
+	if x != nil {
+		if x, ok := x.Response.(*BadezimmerResponse_Empty); ok {
+			return x.Empty
+		}
+	}
+	return nil
+}
+
+func (x *BadezimmerResponse) GetError() *ErrorDetails {
+	if x != nil {
+		if x, ok := x.Response.(*BadezimmerResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+func (x *BadezimmerResponse) GetListDevicesResponse() *ListConnectedDevicesResponse {
+	if x != nil {
+		if x, ok := x.Response.(*BadezimmerResponse_ListDevicesResponse); ok {
+			return x.ListDevicesResponse
+		}
+	}
+	return nil
+}
+
+func (x *BadezimmerResponse) GetSendActuatorCommandResponse() *SendActuatorCommandResponse {
+	if x != nil {
+		if x, ok := x.Response.(*BadezimmerResponse_SendActuatorCommandResponse); ok {
+			return x.SendActuatorCommandResponse
+		}
+	}
+	return nil
+}
+
+func (x *BadezimmerResponse) GetGetHistoryResponse() *GetHistoryResponse {
+	if x != nil {
+		if x, ok := x.Response.(*BadezimmerResponse_GetHistoryResponse); ok {
+			return x.GetHistoryResponse
+		}
+	}
+	return nil
+}
+
+func (x *BadezimmerResponse) GetLeakUpdate() *LeakSample {
+	if x != nil {
+		if x, ok := x.Response.(*BadezimmerResponse_LeakUpdate); ok {
+			return x.LeakUpdate
+		}
+	}
+	return nil
+}
+
+func (x *BadezimmerResponse) GetPong() *PongResponse {
+	if x != nil {
+		if x, ok := x.Response.(*BadezimmerResponse_Pong); ok {
+			return x.Pong
+		}
+	}
+	return nil
+}
+
+func (x *BadezimmerResponse) GetHelloResponse() *HelloResponse {
+	if x != nil {
+		if x, ok := x.Response.(*BadezimmerResponse_HelloResponse); ok {
+			return x.HelloResponse
+		}
+	}
+	return nil
+}
+
+func (x *BadezimmerResponse) GetListServicesResponse() *ListServicesResponse {
+	if x != nil {
+		if x, ok := x.Response.(*BadezimmerResponse_ListServicesResponse); ok {
+			return x.ListServicesResponse
+		}
+	}
+	return nil
+}
+
+func (x *BadezimmerResponse) GetSeverityStatsResponse() *SeverityStatsResponse {
+	if x != nil {
+		if x, ok := x.Response.(*BadezimmerResponse_SeverityStatsResponse); ok {
+			return x.SeverityStatsResponse
+		}
+	}
+	return nil
+}
+
+func (x *BadezimmerResponse) GetStatusResponse() *LeakSample {
+	if x != nil {
+		if x, ok := x.Response.(*BadezimmerResponse_StatusResponse); ok {
+			return x.StatusResponse
+		}
+	}
+	return nil
+}
+
+func (x *BadezimmerResponse) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *BadezimmerResponse) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+type isBadezimmerResponse_Response interface {
+	isBadezimmerResponse_Response()
+}
+
+type BadezimmerResponse_Empty struct {
+	Empty *emptypb.Empty `protobuf:"bytes,1,opt,name=empty,proto3,oneof"`
+}
+
+type BadezimmerResponse_Error struct {
+	Error *ErrorDetails `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+type BadezimmerResponse_ListDevicesResponse struct {
+	ListDevicesResponse *ListConnectedDevicesResponse `protobuf:"bytes,3,opt,name=list_devices_response,json=listDevicesResponse,proto3,oneof"`
+}
+
+type BadezimmerResponse_SendActuatorCommandResponse struct {
+	SendActuatorCommandResponse *SendActuatorCommandResponse `protobuf:"bytes,4,opt,name=send_actuator_command_response,json=sendActuatorCommandResponse,proto3,oneof"`
+}
+
+type BadezimmerResponse_GetHistoryResponse struct {
+	GetHistoryResponse *GetHistoryResponse `protobuf:"bytes,5,opt,name=get_history_response,json=getHistoryResponse,proto3,oneof"`
+}
+
+type BadezimmerResponse_LeakUpdate struct {
+	LeakUpdate *LeakSample `protobuf:"bytes,6,opt,name=leak_update,json=leakUpdate,proto3,oneof"`
+}
+
+type BadezimmerResponse_Pong struct {
+	Pong *PongResponse `protobuf:"bytes,7,opt,name=pong,proto3,oneof"`
+}
+
+type BadezimmerResponse_HelloResponse struct {
+	HelloResponse *HelloResponse `protobuf:"bytes,8,opt,name=hello_response,json=helloResponse,proto3,oneof"`
+}
+
+type BadezimmerResponse_ListServicesResponse struct {
+	ListServicesResponse *ListServicesResponse `protobuf:"bytes,9,opt,name=list_services_response,json=listServicesResponse,proto3,oneof"`
+}
+
+type BadezimmerResponse_SeverityStatsResponse struct {
+	SeverityStatsResponse *SeverityStatsResponse `protobuf:"bytes,10,opt,name=severity_stats_response,json=severityStatsResponse,proto3,oneof"`
+}
+
+type BadezimmerResponse_StatusResponse struct {
+	StatusResponse *LeakSample `protobuf:"bytes,11,opt,name=status_response,json=statusResponse,proto3,oneof"`
+}
+
+func (*BadezimmerResponse_Empty) isBadezimmerResponse_Response() {}
+
+func (*BadezimmerResponse_Error) isBadezimmerResponse_Response() {}
+
+func (*BadezimmerResponse_ListDevicesResponse) isBadezimmerResponse_Response() {}
+
+func (*BadezimmerResponse_SendActuatorCommandResponse) isBadezimmerResponse_Response() {}
+
+func (*BadezimmerResponse_GetHistoryResponse) isBadezimmerResponse_Response() {}
+
+func (*BadezimmerResponse_LeakUpdate) isBadezimmerResponse_Response() {}
+
+func (*BadezimmerResponse_Pong) isBadezimmerResponse_Response() {}
+
+func (*BadezimmerResponse_HelloResponse) isBadezimmerResponse_Response() {}
+
+func (*BadezimmerResponse_ListServicesResponse) isBadezimmerResponse_Response() {}
+
+func (*BadezimmerResponse_SeverityStatsResponse) isBadezimmerResponse_Response() {}
+
+func (*BadezimmerResponse_StatusResponse) isBadezimmerResponse_Response() {}
+
+type SendActuatorCommandResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       *string                `protobuf:"bytes,2,opt,name=message,proto3,oneof" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendActuatorCommandResponse) Reset() {
+	*x = SendActuatorCommandResponse{}
+	mi := &file_badezimmer_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendActuatorCommandResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendActuatorCommandResponse) ProtoMessage() {}
+
+func (x *SendActuatorCommandResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badezimmer_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendActuatorCommandResponse.ProtoReflect.Descriptor instead.
+func (*SendActuatorCommandResponse) Descriptor() ([]byte, []int) {
+	return file_badezimmer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SendActuatorCommandResponse) GetMessage() string {
+	if x != nil && x.Message != nil {
+		return *x.Message
+	}
+	return ""
+}
+
+type GetHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHistoryRequest) Reset() {
+	*x = GetHistoryRequest{}
+	mi := &file_badezimmer_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryRequest) ProtoMessage() {}
+
+func (x *GetHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badezimmer_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_badezimmer_proto_rawDescGZIP(), []int{8}
+}
+
+type GetStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatusRequest) Reset() {
+	*x = GetStatusRequest{}
+	mi := &file_badezimmer_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusRequest) ProtoMessage() {}
+
+func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badezimmer_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return file_badezimmer_proto_rawDescGZIP(), []int{9}
+}
+
+type LeakSample struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Severity      string                 `protobuf:"bytes,1,opt,name=severity,proto3" json:"severity,omitempty"`
+	Location      string                 `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeakSample) Reset() {
+	*x = LeakSample{}
+	mi := &file_badezimmer_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeakSample) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeakSample) ProtoMessage() {}
+
+func (x *LeakSample) ProtoReflect() protoreflect.Message {
+	mi := &file_badezimmer_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeakSample.ProtoReflect.Descriptor instead.
+func (*LeakSample) Descriptor() ([]byte, []int) {
+	return file_badezimmer_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *LeakSample) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *LeakSample) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *LeakSample) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type GetHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Samples       []*LeakSample          `protobuf:"bytes,1,rep,name=samples,proto3" json:"samples,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHistoryResponse) Reset() {
+	*x = GetHistoryResponse{}
+	mi := &file_badezimmer_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryResponse) ProtoMessage() {}
+
+func (x *GetHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badezimmer_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_badezimmer_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetHistoryResponse) GetSamples() []*LeakSample {
+	if x != nil {
+		return x.Samples
+	}
+	return nil
+}
+
+type SubscribeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_badezimmer_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badezimmer_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_badezimmer_proto_rawDescGZIP(), []int{12}
+}
+
+type PingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PingRequest) Reset() {
+	*x = PingRequest{}
+	mi := &file_badezimmer_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingRequest) ProtoMessage() {}
+
+func (x *PingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badezimmer_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingRequest.ProtoReflect.Descriptor instead.
+func (*PingRequest) Descriptor() ([]byte, []int) {
+	return file_badezimmer_proto_rawDescGZIP(), []int{13}
+}
+
+type PongResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServerTime    *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=server_time,json=serverTime,proto3" json:"server_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PongResponse) Reset() {
+	*x = PongResponse{}
+	mi := &file_badezimmer_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PongResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PongResponse) ProtoMessage() {}
+
+func (x *PongResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badezimmer_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PongResponse.ProtoReflect.Descriptor instead.
+func (*PongResponse) Descriptor() ([]byte, []int) {
+	return file_badezimmer_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PongResponse) GetServerTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ServerTime
+	}
+	return nil
+}
+
+type HelloRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Compression   []string               `protobuf:"bytes,1,rep,name=compression,proto3" json:"compression,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HelloRequest) Reset() {
+	*x = HelloRequest{}
+	mi := &file_badezimmer_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HelloRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HelloRequest) ProtoMessage() {}
+
+func (x *HelloRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badezimmer_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HelloRequest.ProtoReflect.Descriptor instead.
+func (*HelloRequest) Descriptor() ([]byte, []int) {
+	return file_badezimmer_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *HelloRequest) GetCompression() []string {
+	if x != nil {
+		return x.Compression
+	}
+	return nil
 }
 
-type BadezimmerRequest_ListDevices struct {
-	ListDevices *ListConnectedDevicesRequest `protobuf:"bytes,2,opt,name=list_devices,json=listDevices,proto3,oneof"`
+type HelloResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Compression   string                 `protobuf:"bytes,1,opt,name=compression,proto3" json:"compression,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-type BadezimmerRequest_SendActuatorCommand struct {
-	SendActuatorCommand *SendActuatorCommandRequest `protobuf:"bytes,3,opt,name=send_actuator_command,json=sendActuatorCommand,proto3,oneof"`
+func (x *HelloResponse) Reset() {
+	*x = HelloResponse{}
+	mi := &file_badezimmer_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (*BadezimmerRequest_Empty) isBadezimmerRequest_Request() {}
+func (x *HelloResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
 
-func (*BadezimmerRequest_ListDevices) isBadezimmerRequest_Request() {}
+func (*HelloResponse) ProtoMessage() {}
 
-func (*BadezimmerRequest_SendActuatorCommand) isBadezimmerRequest_Request() {}
+func (x *HelloResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badezimmer_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
 
-type BadezimmerResponse struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Types that are valid to be assigned to Response:
-	//
-	//	*BadezimmerResponse_Empty
-	//	*BadezimmerResponse_Error
-	//	*BadezimmerResponse_ListDevicesResponse
-	//	*BadezimmerResponse_SendActuatorCommandResponse
-	Response      isBadezimmerResponse_Response `protobuf_oneof:"response"`
+// Deprecated: Use HelloResponse.ProtoReflect.Descriptor instead.
+func (*HelloResponse) Descriptor() ([]byte, []int) {
+	return file_badezimmer_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *HelloResponse) GetCompression() string {
+	if x != nil {
+		return x.Compression
+	}
+	return ""
+}
+
+type ListServicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BadezimmerResponse) Reset() {
-	*x = BadezimmerResponse{}
-	mi := &file_badezimmer_proto_msgTypes[6]
+func (x *ListServicesRequest) Reset() {
+	*x = ListServicesRequest{}
+	mi := &file_badezimmer_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BadezimmerResponse) String() string {
+func (x *ListServicesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BadezimmerResponse) ProtoMessage() {}
+func (*ListServicesRequest) ProtoMessage() {}
 
-func (x *BadezimmerResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_badezimmer_proto_msgTypes[6]
+func (x *ListServicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badezimmer_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -828,104 +1621,176 @@ func (x *BadezimmerResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BadezimmerResponse.ProtoReflect.Descriptor instead.
-func (*BadezimmerResponse) Descriptor() ([]byte, []int) {
-	return file_badezimmer_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ListServicesRequest.ProtoReflect.Descriptor instead.
+func (*ListServicesRequest) Descriptor() ([]byte, []int) {
+	return file_badezimmer_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *BadezimmerResponse) GetResponse() isBadezimmerResponse_Response {
-	if x != nil {
-		return x.Response
-	}
-	return nil
+type ServiceTypeCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Instances     int32                  `protobuf:"varint,2,opt,name=instances,proto3" json:"instances,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BadezimmerResponse) GetEmpty() *emptypb.Empty {
+func (x *ServiceTypeCount) Reset() {
+	*x = ServiceTypeCount{}
+	mi := &file_badezimmer_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceTypeCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceTypeCount) ProtoMessage() {}
+
+func (x *ServiceTypeCount) ProtoReflect() protoreflect.Message {
+	mi := &file_badezimmer_proto_msgTypes[18]
 	if x != nil {
-		if x, ok := x.Response.(*BadezimmerResponse_Empty); ok {
-			return x.Empty
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *BadezimmerResponse) GetError() *ErrorDetails {
+// Deprecated: Use ServiceTypeCount.ProtoReflect.Descriptor instead.
+func (*ServiceTypeCount) Descriptor() ([]byte, []int) {
+	return file_badezimmer_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ServiceTypeCount) GetType() string {
 	if x != nil {
-		if x, ok := x.Response.(*BadezimmerResponse_Error); ok {
-			return x.Error
-		}
+		return x.Type
 	}
-	return nil
+	return ""
 }
 
-func (x *BadezimmerResponse) GetListDevicesResponse() *ListConnectedDevicesResponse {
+func (x *ServiceTypeCount) GetInstances() int32 {
 	if x != nil {
-		if x, ok := x.Response.(*BadezimmerResponse_ListDevicesResponse); ok {
-			return x.ListDevicesResponse
-		}
+		return x.Instances
 	}
-	return nil
+	return 0
 }
 
-func (x *BadezimmerResponse) GetSendActuatorCommandResponse() *SendActuatorCommandResponse {
+type ListServicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Services      []*ServiceTypeCount    `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServicesResponse) Reset() {
+	*x = ListServicesResponse{}
+	mi := &file_badezimmer_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServicesResponse) ProtoMessage() {}
+
+func (x *ListServicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badezimmer_proto_msgTypes[19]
 	if x != nil {
-		if x, ok := x.Response.(*BadezimmerResponse_SendActuatorCommandResponse); ok {
-			return x.SendActuatorCommandResponse
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-type isBadezimmerResponse_Response interface {
-	isBadezimmerResponse_Response()
+// Deprecated: Use ListServicesResponse.ProtoReflect.Descriptor instead.
+func (*ListServicesResponse) Descriptor() ([]byte, []int) {
+	return file_badezimmer_proto_rawDescGZIP(), []int{19}
 }
 
-type BadezimmerResponse_Empty struct {
-	Empty *emptypb.Empty `protobuf:"bytes,1,opt,name=empty,proto3,oneof"`
+func (x *ListServicesResponse) GetServices() []*ServiceTypeCount {
+	if x != nil {
+		return x.Services
+	}
+	return nil
 }
 
-type BadezimmerResponse_Error struct {
-	Error *ErrorDetails `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+type GetSeverityStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WindowSeconds uint32                 `protobuf:"varint,1,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-type BadezimmerResponse_ListDevicesResponse struct {
-	ListDevicesResponse *ListConnectedDevicesResponse `protobuf:"bytes,3,opt,name=list_devices_response,json=listDevicesResponse,proto3,oneof"`
+func (x *GetSeverityStatsRequest) Reset() {
+	*x = GetSeverityStatsRequest{}
+	mi := &file_badezimmer_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-type BadezimmerResponse_SendActuatorCommandResponse struct {
-	SendActuatorCommandResponse *SendActuatorCommandResponse `protobuf:"bytes,4,opt,name=send_actuator_command_response,json=sendActuatorCommandResponse,proto3,oneof"`
+func (x *GetSeverityStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BadezimmerResponse_Empty) isBadezimmerResponse_Response() {}
+func (*GetSeverityStatsRequest) ProtoMessage() {}
 
-func (*BadezimmerResponse_Error) isBadezimmerResponse_Response() {}
+func (x *GetSeverityStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badezimmer_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
 
-func (*BadezimmerResponse_ListDevicesResponse) isBadezimmerResponse_Response() {}
+// Deprecated: Use GetSeverityStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetSeverityStatsRequest) Descriptor() ([]byte, []int) {
+	return file_badezimmer_proto_rawDescGZIP(), []int{20}
+}
 
-func (*BadezimmerResponse_SendActuatorCommandResponse) isBadezimmerResponse_Response() {}
+func (x *GetSeverityStatsRequest) GetWindowSeconds() uint32 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
 
-type SendActuatorCommandResponse struct {
+type SeverityStatsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       *string                `protobuf:"bytes,2,opt,name=message,proto3,oneof" json:"message,omitempty"`
+	Min           int32                  `protobuf:"varint,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max           int32                  `protobuf:"varint,2,opt,name=max,proto3" json:"max,omitempty"`
+	Average       float64                `protobuf:"fixed64,3,opt,name=average,proto3" json:"average,omitempty"`
+	Count         int32                  `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SendActuatorCommandResponse) Reset() {
-	*x = SendActuatorCommandResponse{}
-	mi := &file_badezimmer_proto_msgTypes[7]
+func (x *SeverityStatsResponse) Reset() {
+	*x = SeverityStatsResponse{}
+	mi := &file_badezimmer_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendActuatorCommandResponse) String() string {
+func (x *SeverityStatsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendActuatorCommandResponse) ProtoMessage() {}
+func (*SeverityStatsResponse) ProtoMessage() {}
 
-func (x *SendActuatorCommandResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_badezimmer_proto_msgTypes[7]
+func (x *SeverityStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badezimmer_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -936,16 +1801,37 @@ func (x *SendActuatorCommandResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendActuatorCommandResponse.ProtoReflect.Descriptor instead.
-func (*SendActuatorCommandResponse) Descriptor() ([]byte, []int) {
-	return file_badezimmer_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use SeverityStatsResponse.ProtoReflect.Descriptor instead.
+func (*SeverityStatsResponse) Descriptor() ([]byte, []int) {
+	return file_badezimmer_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *SendActuatorCommandResponse) GetMessage() string {
-	if x != nil && x.Message != nil {
-		return *x.Message
+func (x *SeverityStatsResponse) GetMin() int32 {
+	if x != nil {
+		return x.Min
 	}
-	return ""
+	return 0
+}
+
+func (x *SeverityStatsResponse) GetMax() int32 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *SeverityStatsResponse) GetAverage() float64 {
+	if x != nil {
+		return x.Average
+	}
+	return 0
+}
+
+func (x *SeverityStatsResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
 }
 
 type Color struct {
@@ -957,7 +1843,7 @@ type Color struct {
 
 func (x *Color) Reset() {
 	*x = Color{}
-	mi := &file_badezimmer_proto_msgTypes[8]
+	mi := &file_badezimmer_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -969,7 +1855,7 @@ func (x *Color) String() string {
 func (*Color) ProtoMessage() {}
 
 func (x *Color) ProtoReflect() protoreflect.Message {
-	mi := &file_badezimmer_proto_msgTypes[8]
+	mi := &file_badezimmer_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -982,7 +1868,7 @@ func (x *Color) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Color.ProtoReflect.Descriptor instead.
 func (*Color) Descriptor() ([]byte, []int) {
-	return file_badezimmer_proto_rawDescGZIP(), []int{8}
+	return file_badezimmer_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *Color) GetValue() uint32 {
@@ -1003,7 +1889,7 @@ type LightLampActionRequest struct {
 
 func (x *LightLampActionRequest) Reset() {
 	*x = LightLampActionRequest{}
-	mi := &file_badezimmer_proto_msgTypes[9]
+	mi := &file_badezimmer_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1015,7 +1901,7 @@ func (x *LightLampActionRequest) String() string {
 func (*LightLampActionRequest) ProtoMessage() {}
 
 func (x *LightLampActionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_badezimmer_proto_msgTypes[9]
+	mi := &file_badezimmer_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1028,7 +1914,7 @@ func (x *LightLampActionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LightLampActionRequest.ProtoReflect.Descriptor instead.
 func (*LightLampActionRequest) Descriptor() ([]byte, []int) {
-	return file_badezimmer_proto_rawDescGZIP(), []int{9}
+	return file_badezimmer_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *LightLampActionRequest) GetTurnOn() bool {
@@ -1061,7 +1947,7 @@ type SinkActionRequest struct {
 
 func (x *SinkActionRequest) Reset() {
 	*x = SinkActionRequest{}
-	mi := &file_badezimmer_proto_msgTypes[10]
+	mi := &file_badezimmer_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1073,7 +1959,7 @@ func (x *SinkActionRequest) String() string {
 func (*SinkActionRequest) ProtoMessage() {}
 
 func (x *SinkActionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_badezimmer_proto_msgTypes[10]
+	mi := &file_badezimmer_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1086,7 +1972,7 @@ func (x *SinkActionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SinkActionRequest.ProtoReflect.Descriptor instead.
 func (*SinkActionRequest) Descriptor() ([]byte, []int) {
-	return file_badezimmer_proto_rawDescGZIP(), []int{10}
+	return file_badezimmer_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *SinkActionRequest) GetTurnOn() bool {
@@ -1106,7 +1992,7 @@ type MDNSQuestion struct {
 
 func (x *MDNSQuestion) Reset() {
 	*x = MDNSQuestion{}
-	mi := &file_badezimmer_proto_msgTypes[11]
+	mi := &file_badezimmer_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1118,7 +2004,7 @@ func (x *MDNSQuestion) String() string {
 func (*MDNSQuestion) ProtoMessage() {}
 
 func (x *MDNSQuestion) ProtoReflect() protoreflect.Message {
-	mi := &file_badezimmer_proto_msgTypes[11]
+	mi := &file_badezimmer_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1131,7 +2017,7 @@ func (x *MDNSQuestion) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MDNSQuestion.ProtoReflect.Descriptor instead.
 func (*MDNSQuestion) Descriptor() ([]byte, []int) {
-	return file_badezimmer_proto_rawDescGZIP(), []int{11}
+	return file_badezimmer_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *MDNSQuestion) GetName() string {
@@ -1149,15 +2035,16 @@ func (x *MDNSQuestion) GetType() MDNSType {
 }
 
 type MDNSQueryRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Questions     []*MDNSQuestion        `protobuf:"bytes,1,rep,name=questions,proto3" json:"questions,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Questions        []*MDNSQuestion        `protobuf:"bytes,1,rep,name=questions,proto3" json:"questions,omitempty"`
+	AuthorityRecords []*MDNSRecord          `protobuf:"bytes,2,rep,name=authority_records,json=authorityRecords,proto3" json:"authority_records,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *MDNSQueryRequest) Reset() {
 	*x = MDNSQueryRequest{}
-	mi := &file_badezimmer_proto_msgTypes[12]
+	mi := &file_badezimmer_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1169,7 +2056,7 @@ func (x *MDNSQueryRequest) String() string {
 func (*MDNSQueryRequest) ProtoMessage() {}
 
 func (x *MDNSQueryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_badezimmer_proto_msgTypes[12]
+	mi := &file_badezimmer_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1182,7 +2069,7 @@ func (x *MDNSQueryRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MDNSQueryRequest.ProtoReflect.Descriptor instead.
 func (*MDNSQueryRequest) Descriptor() ([]byte, []int) {
-	return file_badezimmer_proto_rawDescGZIP(), []int{12}
+	return file_badezimmer_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *MDNSQueryRequest) GetQuestions() []*MDNSQuestion {
@@ -1192,6 +2079,13 @@ func (x *MDNSQueryRequest) GetQuestions() []*MDNSQuestion {
 	return nil
 }
 
+func (x *MDNSQueryRequest) GetAuthorityRecords() []*MDNSRecord {
+	if x != nil {
+		return x.AuthorityRecords
+	}
+	return nil
+}
+
 type MDNSPointerRecord struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -1202,7 +2096,7 @@ type MDNSPointerRecord struct {
 
 func (x *MDNSPointerRecord) Reset() {
 	*x = MDNSPointerRecord{}
-	mi := &file_badezimmer_proto_msgTypes[13]
+	mi := &file_badezimmer_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1214,7 +2108,7 @@ func (x *MDNSPointerRecord) String() string {
 func (*MDNSPointerRecord) ProtoMessage() {}
 
 func (x *MDNSPointerRecord) ProtoReflect() protoreflect.Message {
-	mi := &file_badezimmer_proto_msgTypes[13]
+	mi := &file_badezimmer_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1227,7 +2121,7 @@ func (x *MDNSPointerRecord) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MDNSPointerRecord.ProtoReflect.Descriptor instead.
 func (*MDNSPointerRecord) Descriptor() ([]byte, []int) {
-	return file_badezimmer_proto_rawDescGZIP(), []int{13}
+	return file_badezimmer_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *MDNSPointerRecord) GetName() string {
@@ -1258,7 +2152,7 @@ type MDNSSRVRecord struct {
 
 func (x *MDNSSRVRecord) Reset() {
 	*x = MDNSSRVRecord{}
-	mi := &file_badezimmer_proto_msgTypes[14]
+	mi := &file_badezimmer_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1270,7 +2164,7 @@ func (x *MDNSSRVRecord) String() string {
 func (*MDNSSRVRecord) ProtoMessage() {}
 
 func (x *MDNSSRVRecord) ProtoReflect() protoreflect.Message {
-	mi := &file_badezimmer_proto_msgTypes[14]
+	mi := &file_badezimmer_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1283,7 +2177,7 @@ func (x *MDNSSRVRecord) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MDNSSRVRecord.ProtoReflect.Descriptor instead.
 func (*MDNSSRVRecord) Descriptor() ([]byte, []int) {
-	return file_badezimmer_proto_rawDescGZIP(), []int{14}
+	return file_badezimmer_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *MDNSSRVRecord) GetName() string {
@@ -1338,7 +2232,7 @@ type MDNSTextRecord struct {
 
 func (x *MDNSTextRecord) Reset() {
 	*x = MDNSTextRecord{}
-	mi := &file_badezimmer_proto_msgTypes[15]
+	mi := &file_badezimmer_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1350,7 +2244,7 @@ func (x *MDNSTextRecord) String() string {
 func (*MDNSTextRecord) ProtoMessage() {}
 
 func (x *MDNSTextRecord) ProtoReflect() protoreflect.Message {
-	mi := &file_badezimmer_proto_msgTypes[15]
+	mi := &file_badezimmer_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1363,7 +2257,7 @@ func (x *MDNSTextRecord) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MDNSTextRecord.ProtoReflect.Descriptor instead.
 func (*MDNSTextRecord) Descriptor() ([]byte, []int) {
-	return file_badezimmer_proto_rawDescGZIP(), []int{15}
+	return file_badezimmer_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *MDNSTextRecord) GetName() string {
@@ -1390,7 +2284,7 @@ type MDNSARecord struct {
 
 func (x *MDNSARecord) Reset() {
 	*x = MDNSARecord{}
-	mi := &file_badezimmer_proto_msgTypes[16]
+	mi := &file_badezimmer_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1402,7 +2296,7 @@ func (x *MDNSARecord) String() string {
 func (*MDNSARecord) ProtoMessage() {}
 
 func (x *MDNSARecord) ProtoReflect() protoreflect.Message {
-	mi := &file_badezimmer_proto_msgTypes[16]
+	mi := &file_badezimmer_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1415,7 +2309,7 @@ func (x *MDNSARecord) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MDNSARecord.ProtoReflect.Descriptor instead.
 func (*MDNSARecord) Descriptor() ([]byte, []int) {
-	return file_badezimmer_proto_rawDescGZIP(), []int{16}
+	return file_badezimmer_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *MDNSARecord) GetName() string {
@@ -1450,7 +2344,7 @@ type MDNSRecord struct {
 
 func (x *MDNSRecord) Reset() {
 	*x = MDNSRecord{}
-	mi := &file_badezimmer_proto_msgTypes[17]
+	mi := &file_badezimmer_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1462,7 +2356,7 @@ func (x *MDNSRecord) String() string {
 func (*MDNSRecord) ProtoMessage() {}
 
 func (x *MDNSRecord) ProtoReflect() protoreflect.Message {
-	mi := &file_badezimmer_proto_msgTypes[17]
+	mi := &file_badezimmer_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1475,7 +2369,7 @@ func (x *MDNSRecord) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MDNSRecord.ProtoReflect.Descriptor instead.
 func (*MDNSRecord) Descriptor() ([]byte, []int) {
-	return file_badezimmer_proto_rawDescGZIP(), []int{17}
+	return file_badezimmer_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *MDNSRecord) GetName() string {
@@ -1580,7 +2474,7 @@ type MDNSQueryResponse struct {
 
 func (x *MDNSQueryResponse) Reset() {
 	*x = MDNSQueryResponse{}
-	mi := &file_badezimmer_proto_msgTypes[18]
+	mi := &file_badezimmer_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1592,7 +2486,7 @@ func (x *MDNSQueryResponse) String() string {
 func (*MDNSQueryResponse) ProtoMessage() {}
 
 func (x *MDNSQueryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_badezimmer_proto_msgTypes[18]
+	mi := &file_badezimmer_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1605,7 +2499,7 @@ func (x *MDNSQueryResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MDNSQueryResponse.ProtoReflect.Descriptor instead.
 func (*MDNSQueryResponse) Descriptor() ([]byte, []int) {
-	return file_badezimmer_proto_rawDescGZIP(), []int{18}
+	return file_badezimmer_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *MDNSQueryResponse) GetAnswers() []*MDNSRecord {
@@ -1631,13 +2525,14 @@ type MDNS struct {
 	//	*MDNS_QueryRequest
 	//	*MDNS_QueryResponse
 	Data          isMDNS_Data `protobuf_oneof:"data"`
+	InstanceId    string      `protobuf:"bytes,5,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *MDNS) Reset() {
 	*x = MDNS{}
-	mi := &file_badezimmer_proto_msgTypes[19]
+	mi := &file_badezimmer_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1649,7 +2544,7 @@ func (x *MDNS) String() string {
 func (*MDNS) ProtoMessage() {}
 
 func (x *MDNS) ProtoReflect() protoreflect.Message {
-	mi := &file_badezimmer_proto_msgTypes[19]
+	mi := &file_badezimmer_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1662,7 +2557,7 @@ func (x *MDNS) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MDNS.ProtoReflect.Descriptor instead.
 func (*MDNS) Descriptor() ([]byte, []int) {
-	return file_badezimmer_proto_rawDescGZIP(), []int{19}
+	return file_badezimmer_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *MDNS) GetTransactionId() uint32 {
@@ -1704,6 +2599,13 @@ func (x *MDNS) GetQueryResponse() *MDNSQueryResponse {
 	return nil
 }
 
+func (x *MDNS) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
 type isMDNS_Data interface {
 	isMDNS_Data()
 }
@@ -1763,23 +2665,85 @@ const file_badezimmer_proto_rawDesc = "" +
 	"\bmetadata\x18\x03 \x03(\v2&.badezimmer.ErrorDetails.MetadataEntryR\bmetadata\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xfa\x01\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xd8\x06\n" +
 	"\x11BadezimmerRequest\x12.\n" +
 	"\x05empty\x18\x01 \x01(\v2\x16.google.protobuf.EmptyH\x00R\x05empty\x12L\n" +
 	"\flist_devices\x18\x02 \x01(\v2'.badezimmer.ListConnectedDevicesRequestH\x00R\vlistDevices\x12\\\n" +
-	"\x15send_actuator_command\x18\x03 \x01(\v2&.badezimmer.SendActuatorCommandRequestH\x00R\x13sendActuatorCommandB\t\n" +
-	"\arequest\"\xd2\x02\n" +
+	"\x15send_actuator_command\x18\x03 \x01(\v2&.badezimmer.SendActuatorCommandRequestH\x00R\x13sendActuatorCommand\x12@\n" +
+	"\vget_history\x18\x04 \x01(\v2\x1d.badezimmer.GetHistoryRequestH\x00R\n" +
+	"getHistory\x12<\n" +
+	"\tsubscribe\x18\x05 \x01(\v2\x1c.badezimmer.SubscribeRequestH\x00R\tsubscribe\x12-\n" +
+	"\x04ping\x18\x06 \x01(\v2\x17.badezimmer.PingRequestH\x00R\x04ping\x120\n" +
+	"\x05hello\x18\a \x01(\v2\x18.badezimmer.HelloRequestH\x00R\x05hello\x12F\n" +
+	"\rlist_services\x18\b \x01(\v2\x1f.badezimmer.ListServicesRequestH\x00R\flistServices\x12S\n" +
+	"\x12get_severity_stats\x18\t \x01(\v2#.badezimmer.GetSeverityStatsRequestH\x00R\x10getSeverityStats\x12=\n" +
+	"\n" +
+	"get_status\x18\n" +
+	" \x01(\v2\x1c.badezimmer.GetStatusRequestH\x00R\tgetStatus\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x10 \x01(\tR\trequestId\x12D\n" +
+	"\aheaders\x18\x11 \x03(\v2*.badezimmer.BadezimmerRequest.HeadersEntryR\aheaders\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\t\n" +
+	"\arequest\"\xf1\a\n" +
 	"\x12BadezimmerResponse\x12.\n" +
 	"\x05empty\x18\x01 \x01(\v2\x16.google.protobuf.EmptyH\x00R\x05empty\x120\n" +
 	"\x05error\x18\x02 \x01(\v2\x18.badezimmer.ErrorDetailsH\x00R\x05error\x12^\n" +
 	"\x15list_devices_response\x18\x03 \x01(\v2(.badezimmer.ListConnectedDevicesResponseH\x00R\x13listDevicesResponse\x12n\n" +
-	"\x1esend_actuator_command_response\x18\x04 \x01(\v2'.badezimmer.SendActuatorCommandResponseH\x00R\x1bsendActuatorCommandResponseB\n" +
+	"\x1esend_actuator_command_response\x18\x04 \x01(\v2'.badezimmer.SendActuatorCommandResponseH\x00R\x1bsendActuatorCommandResponse\x12R\n" +
+	"\x14get_history_response\x18\x05 \x01(\v2\x1e.badezimmer.GetHistoryResponseH\x00R\x12getHistoryResponse\x129\n" +
+	"\vleak_update\x18\x06 \x01(\v2\x16.badezimmer.LeakSampleH\x00R\n" +
+	"leakUpdate\x12.\n" +
+	"\x04pong\x18\a \x01(\v2\x18.badezimmer.PongResponseH\x00R\x04pong\x12B\n" +
+	"\x0ehello_response\x18\b \x01(\v2\x19.badezimmer.HelloResponseH\x00R\rhelloResponse\x12X\n" +
+	"\x16list_services_response\x18\t \x01(\v2 .badezimmer.ListServicesResponseH\x00R\x14listServicesResponse\x12[\n" +
+	"\x17severity_stats_response\x18\n" +
+	" \x01(\v2!.badezimmer.SeverityStatsResponseH\x00R\x15severityStatsResponse\x12A\n" +
+	"\x0fstatus_response\x18\v \x01(\v2\x16.badezimmer.LeakSampleH\x00R\x0estatusResponse\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x10 \x01(\tR\trequestId\x12E\n" +
+	"\aheaders\x18\x11 \x03(\v2+.badezimmer.BadezimmerResponse.HeadersEntryR\aheaders\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\n" +
 	"\n" +
 	"\bresponse\"H\n" +
 	"\x1bSendActuatorCommandResponse\x12\x1d\n" +
 	"\amessage\x18\x02 \x01(\tH\x00R\amessage\x88\x01\x01B\n" +
 	"\n" +
-	"\b_message\"\x1d\n" +
+	"\b_message\"\x13\n" +
+	"\x11GetHistoryRequest\"\x12\n" +
+	"\x10GetStatusRequest\"~\n" +
+	"\n" +
+	"LeakSample\x12\x1a\n" +
+	"\bseverity\x18\x01 \x01(\tR\bseverity\x12\x1a\n" +
+	"\blocation\x18\x02 \x01(\tR\blocation\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"F\n" +
+	"\x12GetHistoryResponse\x120\n" +
+	"\asamples\x18\x01 \x03(\v2\x16.badezimmer.LeakSampleR\asamples\"\x12\n" +
+	"\x10SubscribeRequest\"\r\n" +
+	"\vPingRequest\"K\n" +
+	"\fPongResponse\x12;\n" +
+	"\vserver_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"serverTime\"0\n" +
+	"\fHelloRequest\x12 \n" +
+	"\vcompression\x18\x01 \x03(\tR\vcompression\"1\n" +
+	"\rHelloResponse\x12 \n" +
+	"\vcompression\x18\x01 \x01(\tR\vcompression\"\x15\n" +
+	"\x13ListServicesRequest\"D\n" +
+	"\x10ServiceTypeCount\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1c\n" +
+	"\tinstances\x18\x02 \x01(\x05R\tinstances\"P\n" +
+	"\x14ListServicesResponse\x128\n" +
+	"\bservices\x18\x01 \x03(\v2\x1c.badezimmer.ServiceTypeCountR\bservices\"@\n" +
+	"\x17GetSeverityStatsRequest\x12%\n" +
+	"\x0ewindow_seconds\x18\x01 \x01(\rR\rwindowSeconds\"k\n" +
+	"\x15SeverityStatsResponse\x12\x10\n" +
+	"\x03min\x18\x01 \x01(\x05R\x03min\x12\x10\n" +
+	"\x03max\x18\x02 \x01(\x05R\x03max\x12\x18\n" +
+	"\aaverage\x18\x03 \x01(\x01R\aaverage\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x05R\x05count\"\x1d\n" +
 	"\x05Color\x12\x14\n" +
 	"\x05value\x18\x01 \x01(\aR\x05value\"\xae\x01\n" +
 	"\x16LightLampActionRequest\x12\x1c\n" +
@@ -1798,9 +2762,10 @@ const file_badezimmer_proto_rawDesc = "" +
 	"\b_turn_on\"L\n" +
 	"\fMDNSQuestion\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12(\n" +
-	"\x04type\x18\x02 \x01(\x0e2\x14.badezimmer.MDNSTypeR\x04type\"J\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x14.badezimmer.MDNSTypeR\x04type\"\x8f\x01\n" +
 	"\x10MDNSQueryRequest\x126\n" +
-	"\tquestions\x18\x01 \x03(\v2\x18.badezimmer.MDNSQuestionR\tquestions\"H\n" +
+	"\tquestions\x18\x01 \x03(\v2\x18.badezimmer.MDNSQuestionR\tquestions\x12C\n" +
+	"\x11authority_records\x18\x02 \x03(\v2\x16.badezimmer.MDNSRecordR\x10authorityRecords\"H\n" +
 	"\x11MDNSPointerRecord\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1f\n" +
 	"\vdomain_name\x18\x02 \x01(\tR\n" +
@@ -1837,12 +2802,14 @@ const file_badezimmer_proto_rawDesc = "" +
 	"\x06record\"\x8c\x01\n" +
 	"\x11MDNSQueryResponse\x120\n" +
 	"\aanswers\x18\x01 \x03(\v2\x16.badezimmer.MDNSRecordR\aanswers\x12E\n" +
-	"\x12additional_records\x18\x02 \x03(\v2\x16.badezimmer.MDNSRecordR\x11additionalRecords\"\xfc\x01\n" +
+	"\x12additional_records\x18\x02 \x03(\v2\x16.badezimmer.MDNSRecordR\x11additionalRecords\"\x9d\x02\n" +
 	"\x04MDNS\x12%\n" +
 	"\x0etransaction_id\x18\x01 \x01(\aR\rtransactionId\x128\n" +
 	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12C\n" +
 	"\rquery_request\x18\x03 \x01(\v2\x1c.badezimmer.MDNSQueryRequestH\x00R\fqueryRequest\x12F\n" +
-	"\x0equery_response\x18\x04 \x01(\v2\x1d.badezimmer.MDNSQueryResponseH\x00R\rqueryResponseB\x06\n" +
+	"\x0equery_response\x18\x04 \x01(\v2\x1d.badezimmer.MDNSQueryResponseH\x00R\rqueryResponse\x12\x1f\n" +
+	"\vinstance_id\x18\x05 \x01(\tR\n" +
+	"instanceIdB\x06\n" +
 	"\x04data*B\n" +
 	"\n" +
 	"DeviceKind\x12\x10\n" +
@@ -1867,13 +2834,15 @@ const file_badezimmer_proto_rawDesc = "" +
 	"\x11TransportProtocol\x12\x14\n" +
 	"\x10UNKNOWN_PROTOCOL\x10\x00\x12\x10\n" +
 	"\fTCP_PROTOCOL\x10\x01\x12\x10\n" +
-	"\fUDP_PROTOCOL\x10\x02*s\n" +
+	"\fUDP_PROTOCOL\x10\x02*\x99\x01\n" +
 	"\tErrorCode\x12\x11\n" +
 	"\rUNKNOWN_ERROR\x10\x00\x12\x14\n" +
 	"\x10DEVICE_NOT_FOUND\x10\x01\x12\x13\n" +
 	"\x0fINVALID_COMMAND\x10\x02\x12\x12\n" +
 	"\x0eDEVICE_OFFLINE\x10\x03\x12\x14\n" +
-	"\x10VALIDATION_ERROR\x10\x04*@\n" +
+	"\x10VALIDATION_ERROR\x10\x04\x12\x0f\n" +
+	"\vUNAVAILABLE\x10\x05\x12\x13\n" +
+	"\x0fUNAUTHENTICATED\x10\x06*@\n" +
 	"\bMDNSType\x12\n" +
 	"\n" +
 	"\x06MDNS_A\x10\x00\x12\f\n" +
@@ -1897,7 +2866,7 @@ func file_badezimmer_proto_rawDescGZIP() []byte {
 }
 
 var file_badezimmer_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
-var file_badezimmer_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_badezimmer_proto_msgTypes = make([]protoimpl.MessageInfo, 39)
 var file_badezimmer_proto_goTypes = []any{
 	(DeviceKind)(0),                      // 0: badezimmer.DeviceKind
 	(DeviceStatus)(0),                    // 1: badezimmer.DeviceStatus
@@ -1913,66 +2882,103 @@ var file_badezimmer_proto_goTypes = []any{
 	(*BadezimmerRequest)(nil),            // 11: badezimmer.BadezimmerRequest
 	(*BadezimmerResponse)(nil),           // 12: badezimmer.BadezimmerResponse
 	(*SendActuatorCommandResponse)(nil),  // 13: badezimmer.SendActuatorCommandResponse
-	(*Color)(nil),                        // 14: badezimmer.Color
-	(*LightLampActionRequest)(nil),       // 15: badezimmer.LightLampActionRequest
-	(*SinkActionRequest)(nil),            // 16: badezimmer.SinkActionRequest
-	(*MDNSQuestion)(nil),                 // 17: badezimmer.MDNSQuestion
-	(*MDNSQueryRequest)(nil),             // 18: badezimmer.MDNSQueryRequest
-	(*MDNSPointerRecord)(nil),            // 19: badezimmer.MDNSPointerRecord
-	(*MDNSSRVRecord)(nil),                // 20: badezimmer.MDNSSRVRecord
-	(*MDNSTextRecord)(nil),               // 21: badezimmer.MDNSTextRecord
-	(*MDNSARecord)(nil),                  // 22: badezimmer.MDNSARecord
-	(*MDNSRecord)(nil),                   // 23: badezimmer.MDNSRecord
-	(*MDNSQueryResponse)(nil),            // 24: badezimmer.MDNSQueryResponse
-	(*MDNS)(nil),                         // 25: badezimmer.MDNS
-	nil,                                  // 26: badezimmer.ConnectedDevice.PropertiesEntry
-	nil,                                  // 27: badezimmer.ErrorDetails.MetadataEntry
-	nil,                                  // 28: badezimmer.MDNSTextRecord.EntriesEntry
-	(*emptypb.Empty)(nil),                // 29: google.protobuf.Empty
-	(*timestamppb.Timestamp)(nil),        // 30: google.protobuf.Timestamp
+	(*GetHistoryRequest)(nil),            // 14: badezimmer.GetHistoryRequest
+	(*GetStatusRequest)(nil),             // 15: badezimmer.GetStatusRequest
+	(*LeakSample)(nil),                   // 16: badezimmer.LeakSample
+	(*GetHistoryResponse)(nil),           // 17: badezimmer.GetHistoryResponse
+	(*SubscribeRequest)(nil),             // 18: badezimmer.SubscribeRequest
+	(*PingRequest)(nil),                  // 19: badezimmer.PingRequest
+	(*PongResponse)(nil),                 // 20: badezimmer.PongResponse
+	(*HelloRequest)(nil),                 // 21: badezimmer.HelloRequest
+	(*HelloResponse)(nil),                // 22: badezimmer.HelloResponse
+	(*ListServicesRequest)(nil),          // 23: badezimmer.ListServicesRequest
+	(*ServiceTypeCount)(nil),             // 24: badezimmer.ServiceTypeCount
+	(*ListServicesResponse)(nil),         // 25: badezimmer.ListServicesResponse
+	(*GetSeverityStatsRequest)(nil),      // 26: badezimmer.GetSeverityStatsRequest
+	(*SeverityStatsResponse)(nil),        // 27: badezimmer.SeverityStatsResponse
+	(*Color)(nil),                        // 28: badezimmer.Color
+	(*LightLampActionRequest)(nil),       // 29: badezimmer.LightLampActionRequest
+	(*SinkActionRequest)(nil),            // 30: badezimmer.SinkActionRequest
+	(*MDNSQuestion)(nil),                 // 31: badezimmer.MDNSQuestion
+	(*MDNSQueryRequest)(nil),             // 32: badezimmer.MDNSQueryRequest
+	(*MDNSPointerRecord)(nil),            // 33: badezimmer.MDNSPointerRecord
+	(*MDNSSRVRecord)(nil),                // 34: badezimmer.MDNSSRVRecord
+	(*MDNSTextRecord)(nil),               // 35: badezimmer.MDNSTextRecord
+	(*MDNSARecord)(nil),                  // 36: badezimmer.MDNSARecord
+	(*MDNSRecord)(nil),                   // 37: badezimmer.MDNSRecord
+	(*MDNSQueryResponse)(nil),            // 38: badezimmer.MDNSQueryResponse
+	(*MDNS)(nil),                         // 39: badezimmer.MDNS
+	nil,                                  // 40: badezimmer.ConnectedDevice.PropertiesEntry
+	nil,                                  // 41: badezimmer.ErrorDetails.MetadataEntry
+	nil,                                  // 42: badezimmer.BadezimmerRequest.HeadersEntry
+	nil,                                  // 43: badezimmer.BadezimmerResponse.HeadersEntry
+	nil,                                  // 44: badezimmer.MDNSTextRecord.EntriesEntry
+	(*emptypb.Empty)(nil),                // 45: google.protobuf.Empty
+	(*timestamppb.Timestamp)(nil),        // 46: google.protobuf.Timestamp
 }
 var file_badezimmer_proto_depIdxs = []int32{
 	0,  // 0: badezimmer.ConnectedDevice.kind:type_name -> badezimmer.DeviceKind
 	1,  // 1: badezimmer.ConnectedDevice.status:type_name -> badezimmer.DeviceStatus
-	26, // 2: badezimmer.ConnectedDevice.properties:type_name -> badezimmer.ConnectedDevice.PropertiesEntry
+	40, // 2: badezimmer.ConnectedDevice.properties:type_name -> badezimmer.ConnectedDevice.PropertiesEntry
 	2,  // 3: badezimmer.ConnectedDevice.category:type_name -> badezimmer.DeviceCategory
 	3,  // 4: badezimmer.ConnectedDevice.transport_protocol:type_name -> badezimmer.TransportProtocol
 	0,  // 5: badezimmer.ListConnectedDevicesRequest.filter_kind:type_name -> badezimmer.DeviceKind
 	6,  // 6: badezimmer.ListConnectedDevicesResponse.devices:type_name -> badezimmer.ConnectedDevice
-	15, // 7: badezimmer.SendActuatorCommandRequest.light_action:type_name -> badezimmer.LightLampActionRequest
-	16, // 8: badezimmer.SendActuatorCommandRequest.sink_action:type_name -> badezimmer.SinkActionRequest
+	29, // 7: badezimmer.SendActuatorCommandRequest.light_action:type_name -> badezimmer.LightLampActionRequest
+	30, // 8: badezimmer.SendActuatorCommandRequest.sink_action:type_name -> badezimmer.SinkActionRequest
 	4,  // 9: badezimmer.ErrorDetails.code:type_name -> badezimmer.ErrorCode
-	27, // 10: badezimmer.ErrorDetails.metadata:type_name -> badezimmer.ErrorDetails.MetadataEntry
-	29, // 11: badezimmer.BadezimmerRequest.empty:type_name -> google.protobuf.Empty
+	41, // 10: badezimmer.ErrorDetails.metadata:type_name -> badezimmer.ErrorDetails.MetadataEntry
+	45, // 11: badezimmer.BadezimmerRequest.empty:type_name -> google.protobuf.Empty
 	7,  // 12: badezimmer.BadezimmerRequest.list_devices:type_name -> badezimmer.ListConnectedDevicesRequest
 	9,  // 13: badezimmer.BadezimmerRequest.send_actuator_command:type_name -> badezimmer.SendActuatorCommandRequest
-	29, // 14: badezimmer.BadezimmerResponse.empty:type_name -> google.protobuf.Empty
-	10, // 15: badezimmer.BadezimmerResponse.error:type_name -> badezimmer.ErrorDetails
-	8,  // 16: badezimmer.BadezimmerResponse.list_devices_response:type_name -> badezimmer.ListConnectedDevicesResponse
-	13, // 17: badezimmer.BadezimmerResponse.send_actuator_command_response:type_name -> badezimmer.SendActuatorCommandResponse
-	14, // 18: badezimmer.LightLampActionRequest.color:type_name -> badezimmer.Color
-	5,  // 19: badezimmer.MDNSQuestion.type:type_name -> badezimmer.MDNSType
-	17, // 20: badezimmer.MDNSQueryRequest.questions:type_name -> badezimmer.MDNSQuestion
-	3,  // 21: badezimmer.MDNSSRVRecord.protocol:type_name -> badezimmer.TransportProtocol
-	28, // 22: badezimmer.MDNSTextRecord.entries:type_name -> badezimmer.MDNSTextRecord.EntriesEntry
-	19, // 23: badezimmer.MDNSRecord.ptr_record:type_name -> badezimmer.MDNSPointerRecord
-	20, // 24: badezimmer.MDNSRecord.srv_record:type_name -> badezimmer.MDNSSRVRecord
-	21, // 25: badezimmer.MDNSRecord.txt_record:type_name -> badezimmer.MDNSTextRecord
-	22, // 26: badezimmer.MDNSRecord.a_record:type_name -> badezimmer.MDNSARecord
-	23, // 27: badezimmer.MDNSQueryResponse.answers:type_name -> badezimmer.MDNSRecord
-	23, // 28: badezimmer.MDNSQueryResponse.additional_records:type_name -> badezimmer.MDNSRecord
-	30, // 29: badezimmer.MDNS.timestamp:type_name -> google.protobuf.Timestamp
-	18, // 30: badezimmer.MDNS.query_request:type_name -> badezimmer.MDNSQueryRequest
-	24, // 31: badezimmer.MDNS.query_response:type_name -> badezimmer.MDNSQueryResponse
-	7,  // 32: badezimmer.BadezimmerService.ListConnectedDevices:input_type -> badezimmer.ListConnectedDevicesRequest
-	9,  // 33: badezimmer.BadezimmerService.SendActuatorCommand:input_type -> badezimmer.SendActuatorCommandRequest
-	8,  // 34: badezimmer.BadezimmerService.ListConnectedDevices:output_type -> badezimmer.ListConnectedDevicesResponse
-	13, // 35: badezimmer.BadezimmerService.SendActuatorCommand:output_type -> badezimmer.SendActuatorCommandResponse
-	34, // [34:36] is the sub-list for method output_type
-	32, // [32:34] is the sub-list for method input_type
-	32, // [32:32] is the sub-list for extension type_name
-	32, // [32:32] is the sub-list for extension extendee
-	0,  // [0:32] is the sub-list for field type_name
+	14, // 14: badezimmer.BadezimmerRequest.get_history:type_name -> badezimmer.GetHistoryRequest
+	18, // 15: badezimmer.BadezimmerRequest.subscribe:type_name -> badezimmer.SubscribeRequest
+	19, // 16: badezimmer.BadezimmerRequest.ping:type_name -> badezimmer.PingRequest
+	21, // 17: badezimmer.BadezimmerRequest.hello:type_name -> badezimmer.HelloRequest
+	23, // 18: badezimmer.BadezimmerRequest.list_services:type_name -> badezimmer.ListServicesRequest
+	26, // 19: badezimmer.BadezimmerRequest.get_severity_stats:type_name -> badezimmer.GetSeverityStatsRequest
+	15, // 20: badezimmer.BadezimmerRequest.get_status:type_name -> badezimmer.GetStatusRequest
+	42, // 21: badezimmer.BadezimmerRequest.headers:type_name -> badezimmer.BadezimmerRequest.HeadersEntry
+	45, // 22: badezimmer.BadezimmerResponse.empty:type_name -> google.protobuf.Empty
+	10, // 23: badezimmer.BadezimmerResponse.error:type_name -> badezimmer.ErrorDetails
+	8,  // 24: badezimmer.BadezimmerResponse.list_devices_response:type_name -> badezimmer.ListConnectedDevicesResponse
+	13, // 25: badezimmer.BadezimmerResponse.send_actuator_command_response:type_name -> badezimmer.SendActuatorCommandResponse
+	17, // 26: badezimmer.BadezimmerResponse.get_history_response:type_name -> badezimmer.GetHistoryResponse
+	16, // 27: badezimmer.BadezimmerResponse.leak_update:type_name -> badezimmer.LeakSample
+	20, // 28: badezimmer.BadezimmerResponse.pong:type_name -> badezimmer.PongResponse
+	22, // 29: badezimmer.BadezimmerResponse.hello_response:type_name -> badezimmer.HelloResponse
+	25, // 30: badezimmer.BadezimmerResponse.list_services_response:type_name -> badezimmer.ListServicesResponse
+	27, // 31: badezimmer.BadezimmerResponse.severity_stats_response:type_name -> badezimmer.SeverityStatsResponse
+	16, // 32: badezimmer.BadezimmerResponse.status_response:type_name -> badezimmer.LeakSample
+	43, // 33: badezimmer.BadezimmerResponse.headers:type_name -> badezimmer.BadezimmerResponse.HeadersEntry
+	46, // 34: badezimmer.LeakSample.timestamp:type_name -> google.protobuf.Timestamp
+	16, // 35: badezimmer.GetHistoryResponse.samples:type_name -> badezimmer.LeakSample
+	46, // 36: badezimmer.PongResponse.server_time:type_name -> google.protobuf.Timestamp
+	24, // 37: badezimmer.ListServicesResponse.services:type_name -> badezimmer.ServiceTypeCount
+	28, // 38: badezimmer.LightLampActionRequest.color:type_name -> badezimmer.Color
+	5,  // 39: badezimmer.MDNSQuestion.type:type_name -> badezimmer.MDNSType
+	31, // 40: badezimmer.MDNSQueryRequest.questions:type_name -> badezimmer.MDNSQuestion
+	37, // 41: badezimmer.MDNSQueryRequest.authority_records:type_name -> badezimmer.MDNSRecord
+	3,  // 42: badezimmer.MDNSSRVRecord.protocol:type_name -> badezimmer.TransportProtocol
+	44, // 43: badezimmer.MDNSTextRecord.entries:type_name -> badezimmer.MDNSTextRecord.EntriesEntry
+	33, // 44: badezimmer.MDNSRecord.ptr_record:type_name -> badezimmer.MDNSPointerRecord
+	34, // 45: badezimmer.MDNSRecord.srv_record:type_name -> badezimmer.MDNSSRVRecord
+	35, // 46: badezimmer.MDNSRecord.txt_record:type_name -> badezimmer.MDNSTextRecord
+	36, // 47: badezimmer.MDNSRecord.a_record:type_name -> badezimmer.MDNSARecord
+	37, // 48: badezimmer.MDNSQueryResponse.answers:type_name -> badezimmer.MDNSRecord
+	37, // 49: badezimmer.MDNSQueryResponse.additional_records:type_name -> badezimmer.MDNSRecord
+	46, // 50: badezimmer.MDNS.timestamp:type_name -> google.protobuf.Timestamp
+	32, // 51: badezimmer.MDNS.query_request:type_name -> badezimmer.MDNSQueryRequest
+	38, // 52: badezimmer.MDNS.query_response:type_name -> badezimmer.MDNSQueryResponse
+	7,  // 53: badezimmer.BadezimmerService.ListConnectedDevices:input_type -> badezimmer.ListConnectedDevicesRequest
+	9,  // 54: badezimmer.BadezimmerService.SendActuatorCommand:input_type -> badezimmer.SendActuatorCommandRequest
+	8,  // 55: badezimmer.BadezimmerService.ListConnectedDevices:output_type -> badezimmer.ListConnectedDevicesResponse
+	13, // 56: badezimmer.BadezimmerService.SendActuatorCommand:output_type -> badezimmer.SendActuatorCommandResponse
+	55, // [55:57] is the sub-list for method output_type
+	53, // [53:55] is the sub-list for method input_type
+	53, // [53:53] is the sub-list for extension type_name
+	53, // [53:53] is the sub-list for extension extendee
+	0,  // [0:53] is the sub-list for field type_name
 }
 
 func init() { file_badezimmer_proto_init() }
@@ -1989,23 +2995,37 @@ func file_badezimmer_proto_init() {
 		(*BadezimmerRequest_Empty)(nil),
 		(*BadezimmerRequest_ListDevices)(nil),
 		(*BadezimmerRequest_SendActuatorCommand)(nil),
+		(*BadezimmerRequest_GetHistory)(nil),
+		(*BadezimmerRequest_Subscribe)(nil),
+		(*BadezimmerRequest_Ping)(nil),
+		(*BadezimmerRequest_Hello)(nil),
+		(*BadezimmerRequest_ListServices)(nil),
+		(*BadezimmerRequest_GetSeverityStats)(nil),
+		(*BadezimmerRequest_GetStatus)(nil),
 	}
 	file_badezimmer_proto_msgTypes[6].OneofWrappers = []any{
 		(*BadezimmerResponse_Empty)(nil),
 		(*BadezimmerResponse_Error)(nil),
 		(*BadezimmerResponse_ListDevicesResponse)(nil),
 		(*BadezimmerResponse_SendActuatorCommandResponse)(nil),
+		(*BadezimmerResponse_GetHistoryResponse)(nil),
+		(*BadezimmerResponse_LeakUpdate)(nil),
+		(*BadezimmerResponse_Pong)(nil),
+		(*BadezimmerResponse_HelloResponse)(nil),
+		(*BadezimmerResponse_ListServicesResponse)(nil),
+		(*BadezimmerResponse_SeverityStatsResponse)(nil),
+		(*BadezimmerResponse_StatusResponse)(nil),
 	}
 	file_badezimmer_proto_msgTypes[7].OneofWrappers = []any{}
-	file_badezimmer_proto_msgTypes[9].OneofWrappers = []any{}
-	file_badezimmer_proto_msgTypes[10].OneofWrappers = []any{}
-	file_badezimmer_proto_msgTypes[17].OneofWrappers = []any{
+	file_badezimmer_proto_msgTypes[23].OneofWrappers = []any{}
+	file_badezimmer_proto_msgTypes[24].OneofWrappers = []any{}
+	file_badezimmer_proto_msgTypes[31].OneofWrappers = []any{
 		(*MDNSRecord_PtrRecord)(nil),
 		(*MDNSRecord_SrvRecord)(nil),
 		(*MDNSRecord_TxtRecord)(nil),
 		(*MDNSRecord_ARecord)(nil),
 	}
-	file_badezimmer_proto_msgTypes[19].OneofWrappers = []any{
+	file_badezimmer_proto_msgTypes[33].OneofWrappers = []any{
 		(*MDNS_QueryRequest)(nil),
 		(*MDNS_QueryResponse)(nil),
 	}
@@ -2015,7 +3035,7 @@ func file_badezimmer_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_badezimmer_proto_rawDesc), len(file_badezimmer_proto_rawDesc)),
 			NumEnums:      6,
-			NumMessages:   23,
+			NumMessages:   39,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
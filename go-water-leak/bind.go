@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// IP_MULTICAST_ALL for Linux, missing from the syscall package
+const ipMulticastAll = 49
+
+// WithMulticastBindAddress binds the multicast socket to addr instead of
+// 0.0.0.0. A local unicast address also picks the interface owning it to
+// join the group and send on. Linux only delivers multicast to sockets bound
+// to the wildcard or the group address though, so bound to a unicast address
+// only unicast queries arrive. Binding to the group address keeps multicast,
+// limited to the joins of this socket.
+func WithMulticastBindAddress(addr string) Option {
+	return func(m *BadezimmerMDNS) {
+		m.bindAddress = addr
+	}
+}
+
+// resolveBindAddress parses the bind address, and finds the interface
+// owning it when it is a unicast one.
+func (m *BadezimmerMDNS) resolveBindAddress() error {
+	ip := net.ParseIP(m.bindAddress).To4()
+	if ip == nil {
+		return fmt.Errorf("invalid IPv4 bind address %q", m.bindAddress)
+	}
+	m.bindIP = ip
+
+	switch {
+	case ip.IsUnspecified():
+		return nil
+	case ip.IsMulticast():
+		if !ip.Equal(net.ParseIP(m.groupIP)) {
+			return fmt.Errorf("bind address %s is not the multicast group %s", ip, m.groupIP)
+		}
+		return nil
+	}
+
+	ifaces, err := m.addressProvider.Interfaces()
+	if err != nil {
+		return err
+	}
+	for _, iface := range ifaces {
+		for _, ifaceIP := range iface.IPs {
+			if ifaceIP.Equal(ip) {
+				m.bindInterface = &multicastInterface{name: iface.Name, index: iface.Index, ip: ip}
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("bind address %s is not a local address", ip)
+}
+
+// setBindOptions sets the options a specific bind address needs on fd: the
+// sending interface for a unicast one, and only our own joins for the group.
+func (m *BadezimmerMDNS) setBindOptions(fd int) error {
+	if m.bindInterface != nil {
+		mreq := &syscall.IPMreqn{Ifindex: int32(m.bindInterface.index)}
+		copy(mreq.Address[:], m.bindInterface.ip.To4())
+		return syscall.SetsockoptIPMreqn(fd, syscall.IPPROTO_IP, syscall.IP_MULTICAST_IF, mreq)
+	}
+	if m.bindIP.IsMulticast() {
+		return syscall.SetsockoptInt(fd, syscall.IPPROTO_IP, ipMulticastAll, 0)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// Consecutive send failures before the breaker opens
+	BreakerFailureThreshold = 5
+	// How often an open breaker lets a re-announce through as a probe
+	BreakerProbeInterval = 30 * time.Second
+)
+
+var ErrBreakerOpen = errors.New("send circuit breaker is open")
+
+// sendBreaker stops the responder from hammering a socket that keeps failing,
+// e.g. while the interface is down. Once open, sends are rejected until the
+// probe loop half-opens it and a send succeeds.
+type sendBreaker struct {
+	mu       sync.Mutex
+	failures int
+	open     bool
+	halfOpen bool
+}
+
+func (b *sendBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.open || b.halfOpen
+}
+
+// success records a successful send and reports whether it closed the breaker.
+func (b *sendBreaker) success() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasOpen := b.open
+	b.failures = 0
+	b.open = false
+	b.halfOpen = false
+	return wasOpen
+}
+
+// failure records a failed send and reports whether it opened the breaker.
+func (b *sendBreaker) failure() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.halfOpen = false
+	if !b.open && b.failures >= BreakerFailureThreshold {
+		b.open = true
+		return true
+	}
+	return false
+}
+
+// tryHalfOpen lets the next sends through if the breaker is open.
+func (b *sendBreaker) tryHalfOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return false
+	}
+	b.halfOpen = true
+	return true
+}
+
+func (b *sendBreaker) isOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.open
+}
+
+func (m *BadezimmerMDNS) breakerLoop() {
+	defer m.wg.Done()
+
+	ticker := m.clock.NewTicker(BreakerProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C():
+			if !m.breaker.tryHalfOpen() {
+				continue
+			}
+			log.Printf("Probing send circuit breaker")
+			m.announceAll()
+		}
+	}
+}
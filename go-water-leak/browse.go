@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+	"time"
+)
+
+// DefaultBrowseWindow is how long browse listens for answers by default.
+const DefaultBrowseWindow = 3 * time.Second
+
+// runBrowse implements `badezimmer browse [-window 3s] [service type]`. It
+// queries the network, prints what answered and exits when the window ends
+// or on Ctrl-C. opts configure the MDNS instance it browses with.
+func runBrowse(args []string, out io.Writer, opts ...Option) error {
+	flags := flag.NewFlagSet("browse", flag.ContinueOnError)
+	window := flags.Duration("window", DefaultBrowseWindow, "how long to wait for answers")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	serviceType := ServiceDiscoveryType
+	if flags.NArg() > 0 {
+		serviceType = flags.Arg(0)
+	}
+
+	mdns := NewBadezimmerMDNS(opts...)
+	if err := mdns.Start(); err != nil {
+		return fmt.Errorf("failed to start MDNS: %w", err)
+	}
+	defer mdns.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *window)
+	defer cancel()
+
+	services, err := mdns.DiscoverServices(ctx, serviceType)
+	if err != nil {
+		return err
+	}
+
+	printServices(out, services)
+	return nil
+}
+
+func printServices(out io.Writer, services []*MDNSServiceInfo) {
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tADDRESS\tSEVERITY\tLOCATION")
+	for _, info := range services {
+		address := "-"
+		if len(info.Addresses) > 0 {
+			address = net.JoinHostPort(info.Addresses[0], strconv.Itoa(int(info.Port)))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.Name, address, propertyOrDash(info, "severity"), propertyOrDash(info, "location"))
+	}
+	tw.Flush()
+}
+
+func propertyOrDash(info *MDNSServiceInfo, key string) string {
+	if value, ok := info.Properties[key]; ok && value != "" {
+		return value
+	}
+	return "-"
+}
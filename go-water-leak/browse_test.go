@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBrowsePrintsServices(t *testing.T) {
+	ip, port := testGroup()
+	responder := NewBadezimmerMDNS(WithRandomSeed(1), WithMulticastGroup(ip, port))
+	if err := responder.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { responder.Close() })
+	info := testService("Kitchen")
+	info.Properties["location"] = "bathroom"
+	addService(responder, info)
+
+	var out bytes.Buffer
+	err := runBrowse([]string{"-window", "500ms", "_waterleak._tcp.local."}, &out,
+		WithRandomSeed(2), WithMulticastGroup(ip, port))
+	if err != nil {
+		t.Fatalf("runBrowse: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want a header and Kitchen:\n%s", len(lines), out.String())
+	}
+	if got := strings.Fields(lines[0]); strings.Join(got, " ") != "NAME ADDRESS SEVERITY LOCATION" {
+		t.Errorf("header = %q", lines[0])
+	}
+	if got := strings.Fields(lines[1]); strings.Join(got, " ") != "Kitchen 192.0.2.2:8080 3 bathroom" {
+		t.Errorf("row = %q", lines[1])
+	}
+}
+
+func TestBrowseRejectsUnknownFlags(t *testing.T) {
+	var out bytes.Buffer
+	if err := runBrowse([]string{"-bogus"}, &out); err == nil {
+		t.Error("runBrowse accepted an unknown flag")
+	}
+}
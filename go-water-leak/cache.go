@@ -0,0 +1,196 @@
+package main
+
+import (
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+)
+
+// MaxCachedServices bounds the cache of discovered services, so a busy or
+// hostile network cannot grow it without limit.
+const MaxCachedServices = 1024
+
+type cachedService struct {
+	info       *MDNSServiceInfo
+	receivedAt time.Time
+}
+
+func (c *cachedService) expired(now time.Time) bool {
+	return now.After(c.info.ExpiresAt)
+}
+
+// serviceCache holds the services announced by other responders, keyed by
+// domain name. Once it holds limit services, the oldest are evicted first.
+type serviceCache struct {
+	mu       sync.RWMutex
+	services map[string]*cachedService
+	limit    int
+}
+
+func newServiceCache() *serviceCache {
+	return &serviceCache{services: make(map[string]*cachedService), limit: MaxCachedServices}
+}
+
+// upsert caches info, setting its ExpiresAt from the TTL, and reports
+// whether it is a service not cached before, or whose entry had expired.
+func (c *serviceCache) upsert(info *MDNSServiceInfo, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info.ExpiresAt = now.Add(time.Duration(info.TTL) * time.Second)
+
+	domainName := generateDomainName(info.Type, info.Name)
+	previous, ok := c.services[domainName]
+	if !ok {
+		c.makeRoom(now)
+	}
+	c.services[domainName] = &cachedService{info: info, receivedAt: now}
+	return !ok || previous.expired(now)
+}
+
+// makeRoom drops the expired services and, while the cache is still full,
+// the one received longest ago. The caller holds c.mu.
+func (c *serviceCache) makeRoom(now time.Time) {
+	for domainName, entry := range c.services {
+		if entry.expired(now) {
+			delete(c.services, domainName)
+		}
+	}
+
+	for len(c.services) >= c.limit {
+		var oldestName string
+		var oldest time.Time
+		for domainName, entry := range c.services {
+			if oldestName == "" || entry.receivedAt.Before(oldest) {
+				oldestName, oldest = domainName, entry.receivedAt
+			}
+		}
+		delete(c.services, oldestName)
+	}
+}
+
+func (c *serviceCache) remove(domainName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.services, domainName)
+}
+
+// contains reports whether an unexpired service is cached under domainName.
+func (c *serviceCache) contains(domainName string, now time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.services[domainName]
+	return ok && !entry.expired(now)
+}
+
+// get returns the unexpired service cached under domainName, or nil.
+func (c *serviceCache) get(domainName string, now time.Time) *MDNSServiceInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.services[domainName]
+	if !ok || entry.expired(now) {
+		return nil
+	}
+	return entry.info.Clone()
+}
+
+// ofType returns the unexpired services of serviceType, or every unexpired
+// service for the meta-query type.
+func (c *serviceCache) ofType(serviceType string, now time.Time) []*MDNSServiceInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	serviceType = normalizeServiceType(serviceType)
+	all := serviceType == normalizeServiceType(ServiceDiscoveryType)
+
+	var services []*MDNSServiceInfo
+	for _, entry := range c.services {
+		if entry.expired(now) {
+			continue
+		}
+		if all || normalizeServiceType(entry.info.Type) == serviceType {
+			services = append(services, entry.info.Clone())
+		}
+	}
+	return services
+}
+
+func (c *serviceCache) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.services = make(map[string]*cachedService)
+}
+
+// stats returns the number of entries and the oldest and newest receive
+// times, zero when the cache is empty.
+func (c *serviceCache) stats() (entries int, oldest, newest time.Time) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, entry := range c.services {
+		if oldest.IsZero() || entry.receivedAt.Before(oldest) {
+			oldest = entry.receivedAt
+		}
+		if entry.receivedAt.After(newest) {
+			newest = entry.receivedAt
+		}
+	}
+	return len(c.services), oldest, newest
+}
+
+// FlushCache forgets every discovered service, e.g. after a topology change.
+func (m *BadezimmerMDNS) FlushCache() {
+	m.cache.flush()
+	log.Printf("Flushed discovered services cache")
+}
+
+// CacheStats returns the number of cached services and when the oldest and
+// newest of them were received. Both times are zero for an empty cache.
+func (m *BadezimmerMDNS) CacheStats() (entries int, oldest, newest time.Time) {
+	return m.cache.stats()
+}
+
+// responseToInfos rebuilds every service announced by the PTR answers of a
+// response, using the remaining records to resolve each of them.
+func responseToInfos(response *badezimmer.MDNSQueryResponse) []*MDNSServiceInfo {
+	var ptrs, others []*badezimmer.MDNSRecord
+	for _, record := range append(append([]*badezimmer.MDNSRecord(nil), response.Answers...), response.AdditionalRecords...) {
+		if record.GetPtrRecord() != nil {
+			ptrs = append(ptrs, record)
+		} else {
+			others = append(others, record)
+		}
+	}
+
+	infos := make([]*MDNSServiceInfo, 0, len(ptrs))
+	for _, ptr := range ptrs {
+		if info := recordsToInfo(append([]*badezimmer.MDNSRecord{ptr}, others...)); info != nil {
+			infos = append(infos, info)
+		}
+	}
+	return infos
+}
+
+// handleResponse caches the announced services, dropping those announced
+// with a zero TTL (goodbyes).
+func (m *BadezimmerMDNS) handleResponse(response *badezimmer.MDNSQueryResponse, addr *net.UDPAddr) {
+	now := m.clock.Now()
+	for _, info := range responseToInfos(response) {
+		if info.TTL == 0 {
+			log.Printf("Received goodbye from %s for service %s", addr.IP, info.Name)
+			m.cache.remove(generateDomainName(info.Type, info.Name))
+			continue
+		}
+
+		log.Printf("Received query response from %s for service %s (%v:%d)", addr.IP, info.Name, info.Addresses, info.Port)
+		if m.cache.upsert(info, now) {
+			m.peerDiscovered(info.Type)
+		}
+		m.discoveries.publish(info)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+)
+
+// announce hands m the announcement of info as if a peer had sent it.
+func announce(m *BadezimmerMDNS, info *MDNSServiceInfo) {
+	records := infoToRecords(info)
+	m.handleResponse(&badezimmer.MDNSQueryResponse{Answers: records[:1], AdditionalRecords: records[1:]},
+		&net.UDPAddr{IP: net.IPv4(192, 0, 2, 100), Port: 5353})
+}
+
+func TestCacheStatsAndFlush(t *testing.T) {
+	m := NewBadezimmerMDNS()
+	before := time.Now()
+
+	announce(m, testService("Kitchen"))
+	time.Sleep(10 * time.Millisecond)
+	announce(m, testService("Bathroom"))
+
+	entries, oldest, newest := m.CacheStats()
+	if entries != 2 {
+		t.Errorf("entries = %d, want 2", entries)
+	}
+	if oldest.Before(before) || !newest.After(oldest) {
+		t.Errorf("oldest %v and newest %v, want both after %v and apart", oldest, newest, before)
+	}
+
+	m.FlushCache()
+	entries, oldest, newest = m.CacheStats()
+	if entries != 0 || !oldest.IsZero() || !newest.IsZero() {
+		t.Errorf("after flush: %d entries, oldest %v, newest %v", entries, oldest, newest)
+	}
+	if m.cache.contains(generateDomainName("_waterleak._tcp.local.", "Kitchen"), time.Now()) {
+		t.Error("service still cached after flush")
+	}
+}
+
+func TestCacheExpiresAndForgetsGoodbyes(t *testing.T) {
+	m := NewBadezimmerMDNS()
+	kitchen, bathroom := testService("Kitchen"), testService("Bathroom")
+	kitchen.TTL = 60
+
+	announce(m, kitchen)
+	announce(m, bathroom)
+	kitchenName := generateDomainName(kitchen.Type, kitchen.Name)
+	bathroomName := generateDomainName(bathroom.Type, bathroom.Name)
+	if !m.cache.contains(kitchenName, time.Now()) || !m.cache.contains(bathroomName, time.Now()) {
+		t.Fatal("announced services not cached")
+	}
+
+	if m.cache.contains(kitchenName, time.Now().Add(61*time.Second)) {
+		t.Error("service cached past its TTL")
+	}
+
+	bathroom.TTL = 0
+	announce(m, bathroom)
+	if m.cache.contains(bathroomName, time.Now()) {
+		t.Error("service cached after its goodbye")
+	}
+}
+
+func TestCachedServiceRemainingTTL(t *testing.T) {
+	clock := newFakeClock()
+	m := NewBadezimmerMDNS(WithClock(clock))
+	kitchen := testService("Kitchen")
+	kitchen.TTL = 120
+	domainName := generateDomainName(kitchen.Type, kitchen.Name)
+
+	announce(m, kitchen)
+	clock.Advance(30 * time.Second)
+	cached := m.cache.get(domainName, clock.Now())
+	if got := cached.RemainingTTL(clock.Now()); got != 90*time.Second {
+		t.Errorf("RemainingTTL = %v after 30s, want 90s", got)
+	}
+	if want := clock.Now().Add(90 * time.Second); !cached.ExpiresAt.Equal(want) {
+		t.Errorf("ExpiresAt = %v, want %v", cached.ExpiresAt, want)
+	}
+
+	// Another announcement starts the TTL over
+	announce(m, kitchen)
+	clock.Advance(10 * time.Second)
+	if got := m.cache.get(domainName, clock.Now()).RemainingTTL(clock.Now()); got != 110*time.Second {
+		t.Errorf("RemainingTTL = %v after the refresh, want 110s", got)
+	}
+	if got := cached.RemainingTTL(clock.Now().Add(time.Hour)); got != 0 {
+		t.Errorf("RemainingTTL = %v once expired, want 0", got)
+	}
+}
+
+func TestCacheEvictsOldestWhenFull(t *testing.T) {
+	c := newServiceCache()
+	c.limit = 2
+	now := time.Now()
+
+	c.upsert(testService("Kitchen"), now)
+	c.upsert(testService("Bathroom"), now.Add(time.Second))
+	c.upsert(testService("Hallway"), now.Add(2*time.Second))
+
+	later := now.Add(3 * time.Second)
+	if c.contains(generateDomainName("_waterleak._tcp.local.", "Kitchen"), later) {
+		t.Error("oldest service not evicted")
+	}
+	for _, name := range []string{"Bathroom", "Hallway"} {
+		if !c.contains(generateDomainName("_waterleak._tcp.local.", name), later) {
+			t.Errorf("%s evicted, want only the oldest gone", name)
+		}
+	}
+}
+
+func TestCachePrunesExpiredOnUpsert(t *testing.T) {
+	c := newServiceCache()
+	now := time.Now()
+	kitchen := testService("Kitchen")
+	kitchen.TTL = 60
+	c.upsert(kitchen, now)
+
+	c.upsert(testService("Bathroom"), now.Add(61*time.Second))
+	if entries, _, _ := c.stats(); entries != 1 {
+		t.Errorf("%d entries, want the expired one pruned", entries)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+	"google.golang.org/protobuf/proto"
+)
+
+// How long FetchStatus waits for a service it has not seen yet to answer
+const ResolveTimeout = 3 * time.Second
+
+var (
+	ErrResolveFailed = errors.New("failed to resolve service")
+	ErrDialFailed    = errors.New("failed to dial service")
+)
+
+// FetchStatus resolves a device over mDNS, asks it for its status over the
+// TCP control protocol and returns its latest leak severity and location.
+// Resolve and dial failures wrap ErrResolveFailed and ErrDialFailed. Headers
+// attached to ctx with ContextWithHeaders are sent along. Start must have
+// been called.
+func (m *BadezimmerMDNS) FetchStatus(ctx context.Context, instanceName, serviceType string) (severity, location string, err error) {
+	info, err := m.resolve(ctx, generateDomainName(serviceType, instanceName), serviceType)
+	if err != nil {
+		return "", "", err
+	}
+	if len(info.Addresses) == 0 {
+		return "", "", fmt.Errorf("%w: %s has no address", ErrResolveFailed, info.Name)
+	}
+
+	address := net.JoinHostPort(info.Addresses[0], strconv.Itoa(int(info.Port)))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %s: %v", ErrDialFailed, address, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if err := sayHello(conn); err != nil {
+		return "", "", err
+	}
+
+	request := &badezimmer.BadezimmerRequest{
+		Request:   &badezimmer.BadezimmerRequest_GetStatus{GetStatus: &badezimmer.GetStatusRequest{}},
+		RequestId: newRequestID(),
+		Headers:   HeadersFromContext(ctx),
+	}
+	response, err := roundTrip(conn, request)
+	if err != nil {
+		return "", "", err
+	}
+
+	if details := response.GetError(); details != nil {
+		return "", "", fmt.Errorf("%s answered %s: %s", info.Name, details.Code, details.Message)
+	}
+	status := response.GetStatusResponse()
+	if status == nil {
+		return "", "", fmt.Errorf("%s did not answer with a status", info.Name)
+	}
+	return status.Severity, status.Location, nil
+}
+
+// resolve returns the cached service for domainName, querying the network
+// and waiting up to ResolveTimeout if it is not cached yet.
+func (m *BadezimmerMDNS) resolve(ctx context.Context, domainName, serviceType string) (*MDNSServiceInfo, error) {
+	if info := m.cache.get(domainName, m.clock.Now()); info != nil {
+		return info, nil
+	}
+
+	if err := m.sendQuery(normalizeServiceType(serviceType)); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrResolveFailed, domainName, err)
+	}
+
+	timeout := m.clock.After(ResolveTimeout)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %s: %v", ErrResolveFailed, domainName, ctx.Err())
+		case <-timeout:
+			return nil, fmt.Errorf("%w: %s did not answer", ErrResolveFailed, domainName)
+		case <-m.clock.After(50 * time.Millisecond):
+		}
+
+		if info := m.cache.get(domainName, m.clock.Now()); info != nil {
+			return info, nil
+		}
+	}
+}
+
+// sayHello offers gzip to the device, which then compresses the responses
+// large enough to be worth it.
+func sayHello(conn net.Conn) error {
+	request := &badezimmer.BadezimmerRequest{
+		Request:   &badezimmer.BadezimmerRequest_Hello{Hello: &badezimmer.HelloRequest{Compression: []string{CompressionGzip}}},
+		RequestId: newRequestID(),
+	}
+	response, err := roundTrip(conn, request)
+	if err != nil {
+		return err
+	}
+	if response.GetHelloResponse() == nil {
+		return fmt.Errorf("unexpected answer to hello: %T", response.GetResponse())
+	}
+	return nil
+}
+
+// roundTrip sends a length-prefixed request and reads the response, which may
+// be compressed.
+func roundTrip(conn net.Conn, request *badezimmer.BadezimmerRequest) (*badezimmer.BadezimmerResponse, error) {
+	requestBytes, err := proto.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	frame := make([]byte, 4, 4+len(requestBytes))
+	binary.BigEndian.PutUint32(frame, uint32(len(requestBytes)))
+	if _, err := conn.Write(append(frame, requestBytes...)); err != nil {
+		return nil, fmt.Errorf("failed to write request: %w", err)
+	}
+
+	responseBytes, err := readFrameCompressed(conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	response := &badezimmer.BadezimmerResponse{}
+	if err := unmarshalOptions.Unmarshal(responseBytes, response); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedContent, err)
+	}
+	return response, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestFetchStatus(t *testing.T) {
+	clock := newFakeClock()
+	w := newTestDetector(t, clock)
+	m, _ := newCapturedResponder(t)
+
+	info := w.snapshotInfo()
+	info.Addresses = []string{"127.0.0.1"}
+	announce(m, info)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	severity, location, err := m.FetchStatus(ctx, info.Name, info.Type)
+	if err != nil {
+		t.Fatalf("FetchStatus: %v", err)
+	}
+	sample := latestSample(w)
+	if severity != strconv.Itoa(sample.Severity) || location != sample.Location {
+		t.Errorf("got severity %s at %s, want %d at %s", severity, location, sample.Severity, sample.Location)
+	}
+}
+
+func TestFetchStatusResolveFailure(t *testing.T) {
+	m, capture := newCapturedResponder(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, _, err := m.FetchStatus(ctx, "Nowhere", "_waterleak._tcp.local.")
+	if !errors.Is(err, ErrResolveFailed) {
+		t.Errorf("FetchStatus() = %v, want ErrResolveFailed", err)
+	}
+
+	// The unknown service was queried for before giving up
+	if query := capture.next(t).GetQueryRequest(); query == nil {
+		t.Error("no query sent for the unknown service")
+	}
+}
+
+func TestFetchStatusDialFailure(t *testing.T) {
+	m, _ := newCapturedResponder(t)
+	port, err := getRandomAvailableTCPPort()
+	if err != nil {
+		t.Fatalf("failed to pick a port: %v", err)
+	}
+
+	// Nothing listens on the advertised port
+	info := testService("Kitchen")
+	info.Addresses = []string{"127.0.0.1"}
+	info.Port = port
+	announce(m, info)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, _, err := m.FetchStatus(ctx, "Kitchen", "_waterleak._tcp.local."); !errors.Is(err, ErrDialFailed) {
+		t.Errorf("FetchStatus() = %v, want ErrDialFailed", err)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock whose time only moves when advanced, firing the
+// timers and tickers due on the way.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock  *fakeClock
+	at     time.Time
+	period time.Duration // zero for a one-shot timer from After
+	ch     chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.timers = append(c.timers, &fakeTimer{clock: c, at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+func (c *fakeClock) NewTicker(d time.Duration) Ticker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTimer{clock: c, at: c.now.Add(d), period: d, ch: make(chan time.Time, 1)}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.clock.removeLocked(t)
+}
+
+func (c *fakeClock) removeLocked(t *fakeTimer) {
+	for i, other := range c.timers {
+		if other == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Advance moves the time forward by d. Like a real ticker, a ticker whose
+// previous tick was not received yet drops the new one.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	end := c.now.Add(d)
+	for {
+		var due *fakeTimer
+		for _, t := range c.timers {
+			if !t.at.After(end) && (due == nil || t.at.Before(due.at)) {
+				due = t
+			}
+		}
+		if due == nil {
+			break
+		}
+
+		c.now = due.at
+		select {
+		case due.ch <- c.now:
+		default:
+		}
+		if due.period > 0 {
+			due.at = due.at.Add(due.period)
+		} else {
+			c.removeLocked(due)
+		}
+	}
+	c.now = end
+}
+
+// pending returns the number of timers and tickers waiting.
+func (c *fakeClock) pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// waitForPending waits until at least n timers or tickers are waiting, so
+// the goroutines that create them are known to be ready before time moves.
+func (c *fakeClock) waitForPending(t *testing.T, n int) {
+	t.Helper()
+	waitFor(t, func() bool { return c.pending() >= n })
+}
+
+// waitFor polls cond until it holds, failing the test after a few seconds.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// whileAdvancing runs fn while moving clock forward in small steps, for code
+// that polls or sleeps on the clock until it is done.
+func whileAdvancing(clock *fakeClock, fn func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-time.After(time.Millisecond):
+			clock.Advance(10 * time.Millisecond)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"log"
+	"time"
+)
+
+// DefaultAnnounceDebounce is how long property changes must settle before
+// they are announced, so a burst of them goes out as one announcement.
+const DefaultAnnounceDebounce = 200 * time.Millisecond
+
+// WithAnnounceDebounce sets how long property changes must settle before the
+// combined announcement is sent. Zero announces every change right away.
+func WithAnnounceDebounce(debounce time.Duration) DeviceOption {
+	return func(d *Device) {
+		d.announceDebounce = debounce
+	}
+}
+
+// announceProperties announces the current properties, after the debounce
+// window when one is set. Changes made within the window restart it and go
+// out with the same announcement.
+func (d *Device) announceProperties() error {
+	// Properties set before Start go out with the registration
+	if d.mdnsDisabled || !d.started.Load() {
+		return nil
+	}
+	if d.announceDebounce <= 0 {
+		return d.mdns.UpdateService(d.snapshotInfo())
+	}
+
+	d.announceMu.Lock()
+	d.announceGen++
+	pending := d.announcePending
+	d.announcePending = true
+	d.announceMu.Unlock()
+
+	if !pending {
+		go d.debouncedAnnounce()
+	}
+	return nil
+}
+
+// debouncedAnnounce waits until no change was made for a whole debounce
+// window, then announces the properties.
+func (d *Device) debouncedAnnounce() {
+	for {
+		d.announceMu.Lock()
+		gen := d.announceGen
+		d.announceMu.Unlock()
+
+		select {
+		case <-d.ctx.Done():
+			d.announceMu.Lock()
+			d.announcePending = false
+			d.announceMu.Unlock()
+			return
+		case <-d.clock.After(d.announceDebounce):
+		}
+
+		d.announceMu.Lock()
+		settled := d.announceGen == gen
+		if settled {
+			d.announcePending = false
+		}
+		d.announceMu.Unlock()
+
+		if settled {
+			break
+		}
+	}
+
+	if err := d.mdns.UpdateService(d.snapshotInfo()); err != nil {
+		log.Printf("Error announcing property changes: %v", err)
+	}
+}
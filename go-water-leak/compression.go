@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	CompressionNone = ""
+	CompressionGzip = "gzip"
+
+	// Once a client negotiated compression, responses of at least this
+	// many bytes are sent compressed
+	CompressionThreshold = 1024
+
+	// Set in the length prefix of a compressed frame. Frames are capped
+	// far below 2 GiB, so the top bit is never part of a length.
+	compressedFrameFlag = 1 << 31
+)
+
+// negotiateCompression picks the first codec offered by the client that we
+// support, in the client's order of preference.
+func negotiateCompression(offered []string) string {
+	for _, codec := range offered {
+		if codec == CompressionGzip {
+			return codec
+		}
+	}
+	return CompressionNone
+}
+
+type compressionKey struct{}
+
+// withCompression attaches the codec negotiated on the connection to ctx, so
+// handlers that take over the connection keep compressing their responses.
+func withCompression(ctx context.Context, codec string) context.Context {
+	return context.WithValue(ctx, compressionKey{}, codec)
+}
+
+func compressionFromContext(ctx context.Context) string {
+	codec, _ := ctx.Value(compressionKey{}).(string)
+	return codec
+}
+
+func helloResponse(compression string) *badezimmer.BadezimmerResponse {
+	return &badezimmer.BadezimmerResponse{
+		Response: &badezimmer.BadezimmerResponse_HelloResponse{
+			HelloResponse: &badezimmer.HelloResponse{
+				Compression: compression,
+			},
+		},
+	}
+}
+
+// writeResponseCompressed sends a response like writeResponse, compressed
+// with codec when it is large enough. Compressed frames carry
+// compressedFrameFlag in their length prefix.
+func writeResponseCompressed(conn net.Conn, response *badezimmer.BadezimmerResponse, codec string) error {
+	if codec == CompressionNone {
+		return writeResponse(conn, response)
+	}
+
+	responseBytes, err := proto.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	prefix := uint32(len(responseBytes))
+	if len(responseBytes) >= CompressionThreshold {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(responseBytes); err != nil {
+			return fmt.Errorf("failed to compress response: %w", err)
+		}
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("failed to compress response: %w", err)
+		}
+
+		responseBytes = buf.Bytes()
+		prefix = uint32(len(responseBytes)) | compressedFrameFlag
+	}
+
+	responseLengthBuf := make([]byte, 4)
+	binary.BigEndian.PutUint32(responseLengthBuf, prefix)
+
+	if _, err := conn.Write(responseLengthBuf); err != nil {
+		return fmt.Errorf("failed to write response length: %w", err)
+	}
+
+	if _, err := conn.Write(responseBytes); err != nil {
+		return fmt.Errorf("failed to write response: %w", err)
+	}
+
+	return nil
+}
+
+// readFrameCompressed reads one frame like readFrame, inflating it when its
+// length prefix carries compressedFrameFlag. Clients use it to read the
+// responses of a device they said hello to.
+func readFrameCompressed(conn net.Conn) ([]byte, error) {
+	lengthBuf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, lengthBuf); err != nil {
+		return nil, err
+	}
+
+	prefix := binary.BigEndian.Uint32(lengthBuf)
+	messageLength := prefix &^ compressedFrameFlag
+	if messageLength == 0 || messageLength > MaxFrameSize {
+		return nil, fmt.Errorf("%w: invalid message length %d", ErrMalformedFrame, messageLength)
+	}
+
+	messageBuf := make([]byte, messageLength)
+	if _, err := io.ReadFull(conn, messageBuf); err != nil {
+		return nil, fmt.Errorf("%w: truncated message: %v", ErrMalformedFrame, err)
+	}
+	if prefix&compressedFrameFlag == 0 {
+		return messageBuf, nil
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(messageBuf))
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad gzip frame: %v", ErrMalformedFrame, err)
+	}
+	// The uncompressed message is bound by the frame size too
+	messageBuf, err = io.ReadAll(io.LimitReader(gz, MaxFrameSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad gzip frame: %v", ErrMalformedFrame, err)
+	}
+	if len(messageBuf) > MaxFrameSize {
+		return nil, fmt.Errorf("%w: message inflates past %d bytes", ErrMalformedFrame, MaxFrameSize)
+	}
+	return messageBuf, nil
+}
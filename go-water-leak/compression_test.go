@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNegotiateCompression(t *testing.T) {
+	tests := []struct {
+		offered []string
+		want    string
+	}{
+		{nil, CompressionNone},
+		{[]string{"br"}, CompressionNone},
+		{[]string{"br", CompressionGzip}, CompressionGzip},
+	}
+	for _, tt := range tests {
+		if got := negotiateCompression(tt.offered); got != tt.want {
+			t.Errorf("negotiateCompression(%q) = %q, want %q", tt.offered, got, tt.want)
+		}
+	}
+}
+
+// pipeResponse writes response with codec on one end of a pipe and returns
+// the raw frame prefix and the message read back with readFrameCompressed.
+func pipeResponse(t *testing.T, response *badezimmer.BadezimmerResponse, codec string) (uint32, *badezimmer.BadezimmerResponse) {
+	t.Helper()
+	server, client := net.Pipe()
+	defer client.Close()
+
+	go func() {
+		defer server.Close()
+		writeResponseCompressed(server, response, codec)
+	}()
+
+	prefix := make([]byte, 4)
+	if _, err := io.ReadFull(client, prefix); err != nil {
+		t.Fatalf("read prefix: %v", err)
+	}
+	rest, err := io.ReadAll(client)
+	if err != nil {
+		t.Fatalf("read frame: %v", err)
+	}
+
+	reader, writer := net.Pipe()
+	defer reader.Close()
+	go func() {
+		defer writer.Close()
+		writer.Write(append(prefix, rest...))
+	}()
+
+	messageBuf, err := readFrameCompressed(reader)
+	if err != nil {
+		t.Fatalf("readFrameCompressed: %v", err)
+	}
+	got := &badezimmer.BadezimmerResponse{}
+	if err := proto.Unmarshal(messageBuf, got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return binary.BigEndian.Uint32(prefix), got
+}
+
+func largeResponse() *badezimmer.BadezimmerResponse {
+	return &badezimmer.BadezimmerResponse{
+		Response: &badezimmer.BadezimmerResponse_Error{
+			Error: &badezimmer.ErrorDetails{Message: strings.Repeat("leak ", CompressionThreshold)},
+		},
+	}
+}
+
+func TestCompressedResponseRoundTrip(t *testing.T) {
+	response := largeResponse()
+
+	prefix, got := pipeResponse(t, response, CompressionGzip)
+	if prefix&compressedFrameFlag == 0 {
+		t.Error("large response was not compressed")
+	}
+	if !proto.Equal(got, response) {
+		t.Error("compressed response did not round trip")
+	}
+}
+
+func TestSmallResponseStaysUncompressed(t *testing.T) {
+	response := pongResponse()
+
+	prefix, got := pipeResponse(t, response, CompressionGzip)
+	if prefix&compressedFrameFlag != 0 {
+		t.Error("small response was compressed")
+	}
+	if !proto.Equal(got, response) {
+		t.Error("response did not round trip")
+	}
+}
+
+func TestNoCompressionWithoutHello(t *testing.T) {
+	prefix, _ := pipeResponse(t, largeResponse(), CompressionNone)
+	if prefix&compressedFrameFlag != 0 {
+		t.Error("response was compressed without a negotiated codec")
+	}
+}
+
+func TestReadFrameCompressedRejectsBadGzip(t *testing.T) {
+	reader, writer := net.Pipe()
+	defer reader.Close()
+	go func() {
+		defer writer.Close()
+		frame := make([]byte, 4)
+		binary.BigEndian.PutUint32(frame, 3|compressedFrameFlag)
+		writer.Write(append(frame, "bad"...))
+	}()
+
+	if _, err := readFrameCompressed(reader); !errors.Is(err, ErrMalformedFrame) {
+		t.Errorf("err = %v, want ErrMalformedFrame", err)
+	}
+}
+
+func TestCompressionContext(t *testing.T) {
+	ctx := withCompression(context.Background(), CompressionGzip)
+	if got := compressionFromContext(ctx); got != CompressionGzip {
+		t.Errorf("compressionFromContext = %q, want %q", got, CompressionGzip)
+	}
+	if got := compressionFromContext(context.Background()); got != CompressionNone {
+		t.Errorf("compressionFromContext without codec = %q", got)
+	}
+}
+
+// recordingConn keeps the bytes read from the connection.
+type recordingConn struct {
+	net.Conn
+	read []byte
+}
+
+func (c *recordingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.read = append(c.read, p[:n]...)
+	return n, err
+}
+
+func TestHistoryCompressedAfterHello(t *testing.T) {
+	w := NewWaterLeakDetector(0, WithMDNSDisabled(), WithHistorySize(100))
+	generate(w, 99)
+
+	conn := &recordingConn{Conn: pipeTo(t, w)}
+	hello := send(t, conn, &badezimmer.BadezimmerRequest{
+		Request: &badezimmer.BadezimmerRequest_Hello{Hello: &badezimmer.HelloRequest{Compression: []string{CompressionGzip}}},
+	})
+	if hello.GetHelloResponse() == nil {
+		t.Fatalf("hello answered with %v", hello)
+	}
+	conn.read = nil
+
+	response := send(t, conn, historyRequest())
+	if prefix := binary.BigEndian.Uint32(conn.read[:4]); prefix&compressedFrameFlag == 0 {
+		t.Error("large history was not compressed after the hello")
+	}
+	if n := len(response.GetGetHistoryResponse().GetSamples()); n != 100 {
+		t.Errorf("got %d samples, want 100", n)
+	}
+}
@@ -0,0 +1,693 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// RequestHandler answers a request received on the TCP control channel. A
+// handler may take over conn, e.g. to stream updates, and return nil once it
+// is done, which ends the connection.
+type RequestHandler func(ctx context.Context, conn net.Conn, request *badezimmer.BadezimmerRequest) *badezimmer.BadezimmerResponse
+
+// Device advertises a service over mDNS and serves the TCP control protocol
+// for it. It knows nothing about the device it stands for: requests go to
+// its handler, so presets like WaterLeakDetector only add their own data.
+type Device struct {
+	mdns    *BadezimmerMDNS
+	info    *MDNSServiceInfo
+	handler RequestHandler
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	// propsMu guards info.Properties, and any state presets keep alongside
+	propsMu sync.RWMutex
+
+	mdnsDisabled bool
+
+	stateAddr   string
+	stateServer *http.Server
+
+	activeConnections atomic.Int64
+
+	requestFramingErrors atomic.Uint64
+	requestContentErrors atomic.Uint64
+
+	// draining is set once Stop begins; new requests are refused
+	draining atomic.Bool
+
+	started atomic.Bool
+	stopped atomic.Bool
+
+	// networkSeed seeds the mDNS jitter, delays and transaction ids
+	networkSeed int64
+
+	unixSocketPath string
+	unixListener   net.Listener
+
+	// clock drives the device's and the responder's timers
+	clock     Clock
+	startedAt time.Time
+
+	listener net.Listener
+
+	// conns are the open control connections, drained by Stop
+	connsMu      sync.Mutex
+	conns        map[net.Conn]struct{}
+	drainTimeout time.Duration
+
+	// dscp marks the mDNS and control traffic, zero leaves the default
+	dscp int
+
+	addressProvider AddressProvider
+
+	// groupIP and groupPort override the multicast group when groupIP is set
+	groupIP   string
+	groupPort int
+
+	// authToken gates privileged requests, and read-only ones with readAuth
+	authToken string
+	readAuth  bool
+
+	// Property changes within announceDebounce of each other are announced
+	// together, announceGen counts them while one is pending
+	announceDebounce time.Duration
+	announceMu       sync.Mutex
+	announcePending  bool
+	announceGen      uint64
+}
+
+// Clients holding a connection open, e.g. for a subscription, should send a
+// ping this often and consider the connection dead when the pong takes
+// longer than KeepaliveTimeout.
+const (
+	KeepaliveInterval = 30 * time.Second
+	KeepaliveTimeout  = 10 * time.Second
+)
+
+// Bounds of the delay between retries after failed accepts
+const (
+	acceptBackoffMin = 5 * time.Millisecond
+	acceptBackoffMax = 1 * time.Second
+)
+
+// ErrPortCollision means the TCP port is also the mDNS port, which the
+// listener and the multicast socket cannot share sensibly.
+var ErrPortCollision = errors.New("TCP port collides with the mDNS port")
+
+type DeviceOption func(*Device)
+
+// applyDetector lets every DeviceOption configure a WaterLeakDetector too.
+func (opt DeviceOption) applyDetector(w *WaterLeakDetector) {
+	opt(w.Device)
+}
+
+// WithMDNSDisabled runs the device as a plain TCP service, without joining
+// the multicast group or advertising itself.
+func WithMDNSDisabled() DeviceOption {
+	return func(d *Device) {
+		d.mdnsDisabled = true
+	}
+}
+
+// WithStateAddr serves the device state as JSON on http://addr/state.
+func WithStateAddr(addr string) DeviceOption {
+	return func(d *Device) {
+		d.stateAddr = addr
+	}
+}
+
+// WithNetworkSeed seeds the mDNS jitter, delays and transaction ids.
+func WithNetworkSeed(seed int64) DeviceOption {
+	return func(d *Device) {
+		d.networkSeed = seed
+	}
+}
+
+// WithUnixSocket additionally serves the TCP control protocol on a Unix
+// domain socket at path, for tools running on the same host.
+func WithUnixSocket(path string) DeviceOption {
+	return func(d *Device) {
+		d.unixSocketPath = path
+	}
+}
+
+// WithDeviceClock replaces the real clock of the device and its responder.
+func WithDeviceClock(clock Clock) DeviceOption {
+	return func(d *Device) {
+		d.clock = clock
+	}
+}
+
+// WithDeviceDSCP marks the mDNS and TCP control traffic with a DSCP value
+// between 0 and MaxDSCP. Start fails on values out of range.
+func WithDeviceDSCP(dscp int) DeviceOption {
+	return func(d *Device) {
+		d.dscp = dscp
+	}
+}
+
+// WithDeviceAddressProvider replaces the host interfaces the device takes its
+// addresses from, see WithAddressProvider.
+func WithDeviceAddressProvider(provider AddressProvider) DeviceOption {
+	return func(d *Device) {
+		d.addressProvider = provider
+	}
+}
+
+// WithDeviceMulticastGroup advertises the device on another multicast group
+// and port, see WithMulticastGroup.
+func WithDeviceMulticastGroup(ip string, port int) DeviceOption {
+	return func(d *Device) {
+		d.groupIP = ip
+		d.groupPort = port
+	}
+}
+
+// NewDevice creates a device advertising info, with requests answered by
+// handler. info.Port is the TCP port served.
+func NewDevice(info *MDNSServiceInfo, handler RequestHandler, opts ...DeviceOption) *Device {
+	d := newDevice()
+	for _, opt := range opts {
+		opt(d)
+	}
+	d.init(info, handler)
+	return d
+}
+
+// newDevice returns a device with defaults, ready for options. init must be
+// called once they are applied.
+func newDevice() *Device {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Device{
+		ctx:          ctx,
+		cancel:       cancel,
+		networkSeed:  randomSeed,
+		clock:        realClock{},
+		conns:        make(map[net.Conn]struct{}),
+		drainTimeout: DefaultDrainTimeout,
+
+		announceDebounce: DefaultAnnounceDebounce,
+		addressProvider:  SystemAddressProvider{},
+	}
+}
+
+func (d *Device) init(info *MDNSServiceInfo, handler RequestHandler) {
+	d.info = info
+	d.handler = handler
+	opts := []Option{WithRandomSeed(d.networkSeed), WithClock(d.clock), WithDSCP(d.dscp),
+		WithAddressProvider(d.addressProvider)}
+	if d.groupIP != "" {
+		opts = append(opts, WithMulticastGroup(d.groupIP, d.groupPort))
+	}
+	d.mdns = NewBadezimmerMDNS(opts...)
+}
+
+// SetProperty sets a TXT property and announces the change.
+func (d *Device) SetProperty(key, value string) error {
+	return d.SetProperties(map[string]string{key: value})
+}
+
+// SetProperties sets several TXT properties and announces them together.
+// Changes made in quick succession are coalesced, see WithAnnounceDebounce.
+func (d *Device) SetProperties(props map[string]string) error {
+	// Announcing may happen later, so invalid entries are refused now
+	if err := d.mdns.checkTXTEntries(props); err != nil {
+		return err
+	}
+	if err := checkBinaryProperties(props); err != nil {
+		return err
+	}
+
+	d.propsMu.Lock()
+	if d.info.Properties == nil {
+		d.info.Properties = make(map[string]string)
+	}
+	for key, value := range props {
+		d.info.Properties[key] = value
+	}
+	d.propsMu.Unlock()
+
+	return d.announceProperties()
+}
+
+// snapshotInfo returns a copy of the service info taken under propsMu, for
+// use outside of it.
+func (d *Device) snapshotInfo() *MDNSServiceInfo {
+	d.propsMu.RLock()
+	defer d.propsMu.RUnlock()
+	return d.info.Clone()
+}
+
+// Start registers the service and serves the control protocol. It returns
+// ErrAlreadyStarted when called again after succeeding.
+func (d *Device) Start() error {
+	if !d.started.CompareAndSwap(false, true) {
+		return ErrAlreadyStarted
+	}
+
+	if err := d.start(); err != nil {
+		d.started.Store(false)
+		return err
+	}
+	return nil
+}
+
+func (d *Device) start() error {
+	if err := validateDSCP(d.dscp); err != nil {
+		return err
+	}
+
+	if err := d.checkPortCollision(); err != nil {
+		return err
+	}
+
+	// The state server comes first, so the registration advertises its port
+	if d.stateAddr != "" {
+		if err := d.startStateServer(); err != nil {
+			return fmt.Errorf("failed to start state server: %w", err)
+		}
+	}
+
+	// Bind the control sockets before anything is announced, so a busy port
+	// fails the start before there is a registration to take back.
+	// Accepted connections inherit the marking.
+	var lc net.ListenConfig
+	if d.dscp != 0 {
+		lc.Control = dscpControl(d.dscp)
+	}
+	listener, err := lc.Listen(context.Background(), "tcp", fmt.Sprintf("0.0.0.0:%d", d.info.Port))
+	if err != nil {
+		d.closeStateServer()
+		return fmt.Errorf("failed to start TCP server: %w", err)
+	}
+
+	var unixListener net.Listener
+	if d.unixSocketPath != "" {
+		// Remove a socket file left behind by an unclean shutdown
+		if err := os.Remove(d.unixSocketPath); err != nil && !os.IsNotExist(err) {
+			listener.Close()
+			d.closeStateServer()
+			return fmt.Errorf("failed to remove stale unix socket: %w", err)
+		}
+
+		unixListener, err = net.Listen("unix", d.unixSocketPath)
+		if err != nil {
+			listener.Close()
+			d.closeStateServer()
+			return fmt.Errorf("failed to listen on unix socket: %w", err)
+		}
+	}
+
+	closeListeners := func() {
+		if unixListener != nil {
+			unixListener.Close()
+		}
+		listener.Close()
+		d.closeStateServer()
+	}
+
+	if d.mdnsDisabled {
+		log.Println("mDNS disabled, running in TCP-only mode")
+	} else {
+		// Start MDNS
+		if err := d.mdns.Start(); err != nil {
+			closeListeners()
+			return fmt.Errorf("failed to start MDNS: %w", err)
+		}
+
+		// Register service
+		info := d.snapshotInfo()
+		if err := d.mdns.RegisterService(info); err != nil {
+			// A failed announcement leaves the service registered
+			if d.mdns.isRegistered(generateDomainName(info.Type, info.Name)) {
+				d.mdns.UnregisterService(info)
+			}
+			d.mdns.abortStart()
+			closeListeners()
+			return fmt.Errorf("failed to register service: %w", err)
+		}
+
+		// Probing may have renamed the service
+		d.propsMu.Lock()
+		d.info.Name = info.Name
+		d.propsMu.Unlock()
+	}
+
+	log.Printf("Starting %s service on port %d", d.snapshotInfo().Name, d.info.Port)
+	d.listener = listener
+	d.startedAt = d.clock.Now()
+
+	if unixListener != nil {
+		d.unixListener = unixListener
+		log.Printf("Listening for local control on %s", d.unixSocketPath)
+	}
+
+	// Accept connections
+	go d.acceptLoop(listener)
+	if d.unixListener != nil {
+		go d.acceptLoop(d.unixListener)
+	}
+
+	return nil
+}
+
+// checkPortCollision fails when the TCP port is one the responder listens on.
+func (d *Device) checkPortCollision() error {
+	if d.mdnsDisabled {
+		return nil
+	}
+
+	port := int(d.info.Port)
+	if port == d.mdns.groupPort || (d.mdns.standardPort && port == StandardMDNSPort) {
+		return fmt.Errorf("%w: port %d, pick another PORT", ErrPortCollision, port)
+	}
+	return nil
+}
+
+func (d *Device) acceptLoop(listener net.Listener) {
+	// Errors like EMFILE persist for a while, so retrying right away would
+	// only spin; back off until an accept succeeds again
+	var delay time.Duration
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if d.ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return
+			}
+
+			if delay == 0 {
+				delay = acceptBackoffMin
+			} else {
+				delay = min(2*delay, acceptBackoffMax)
+			}
+			log.Printf("Error accepting connection, retrying in %v: %v", delay, err)
+
+			select {
+			case <-d.ctx.Done():
+				return
+			case <-d.clock.After(delay):
+			}
+			continue
+		}
+
+		delay = 0
+
+		// An accept racing with Stop must not slip past the drain
+		if d.draining.Load() {
+			conn.Close()
+			continue
+		}
+		go d.handleConnection(conn)
+	}
+}
+
+// Stop shuts the device down in a fixed order: the listeners stop accepting,
+// open connections are drained, then the service says goodbye over mDNS and
+// the responder closes. Calling it again is a no-op.
+func (d *Device) Stop() error {
+	_, err := d.StopWithReport()
+	return err
+}
+
+// StopWithReport stops like Stop, draining the open connections, and reports
+// how the shutdown went. Calling it again returns an empty report.
+func (d *Device) StopWithReport() (StopReport, error) {
+	if !d.stopped.CompareAndSwap(false, true) {
+		return StopReport{}, nil
+	}
+
+	log.Printf("Stopping %s service...", d.snapshotInfo().Name)
+	d.draining.Store(true)
+	d.cancel()
+
+	var report StopReport
+	if !d.startedAt.IsZero() {
+		report.Uptime = d.clock.Now().Sub(d.startedAt)
+	}
+
+	// No new connections; open ones get UNAVAILABLE until drained.
+	// Closing the unix listener also removes the socket file
+	if d.listener != nil {
+		d.listener.Close()
+	}
+	if d.unixListener != nil {
+		if err := d.unixListener.Close(); err != nil {
+			log.Printf("Error closing unix socket: %v", err)
+		}
+	}
+
+	report.ConnectionsDrained, report.ConnectionsForceClosed = d.drainConnections()
+
+	goodbyesBefore := d.mdns.Stats().GoodbyesSent
+
+	if !d.mdnsDisabled {
+		// Unregister service
+		if err := d.mdns.UnregisterService(d.snapshotInfo()); err != nil {
+			log.Printf("Error unregistering service: %v", err)
+		}
+
+		// Close MDNS
+		if err := d.mdns.Close(); err != nil {
+			log.Printf("Error closing MDNS: %v", err)
+		}
+	}
+
+	d.closeStateServer()
+
+	report.GoodbyesSent = d.mdns.Stats().GoodbyesSent - goodbyesBefore
+
+	log.Printf("Service stopped (%d goodbyes, %d connections drained, %d closed, up %v)",
+		report.GoodbyesSent, report.ConnectionsDrained, report.ConnectionsForceClosed, report.Uptime)
+	return report, nil
+}
+
+func (d *Device) handleConnection(conn net.Conn) {
+	defer conn.Close()
+
+	d.activeConnections.Add(1)
+	defer d.activeConnections.Add(-1)
+
+	d.trackConn(conn)
+	defer d.untrackConn(conn)
+
+	addr := conn.RemoteAddr()
+	log.Printf("Connected by %s", addr)
+
+	// Responses stay uncompressed unless the client says hello first
+	compression := CompressionNone
+
+	for {
+		// Framing errors leave the stream out of sync, so the connection
+		// cannot be used any further
+		messageBuf, err := readFrame(conn)
+		if err != nil {
+			if errors.Is(err, ErrMalformedFrame) {
+				d.requestFramingErrors.Add(1)
+				log.Printf("Closing connection from %s: %v", addr, err)
+			} else if err != io.EOF {
+				log.Printf("Error reading length prefix: %v", err)
+			}
+			return
+		}
+
+		// Content errors only affect this frame, the client gets an error
+		// and may send the next request
+		request := &badezimmer.BadezimmerRequest{}
+		if err := unmarshalOptions.Unmarshal(messageBuf, request); err != nil {
+			d.requestContentErrors.Add(1)
+			log.Printf("Rejecting request from %s: %v", addr, fmt.Errorf("%w: %v", ErrMalformedContent, err))
+			response := errorResponse(badezimmer.ErrorCode_VALIDATION_ERROR, "malformed request: "+err.Error())
+			if err := writeResponse(conn, response); err != nil {
+				log.Printf("Error writing response: %v", err)
+				return
+			}
+			continue
+		}
+
+		// Trace the request with the id provided by the client, or a new one
+		requestID := request.RequestId
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		ctx := withRequestID(d.ctx, requestID)
+		if len(request.Headers) > 0 {
+			ctx = ContextWithHeaders(ctx, request.Headers)
+		}
+		logRequestf(ctx, "Received %T from %s", request.GetRequest(), addr)
+
+		// Refuse new work once shutdown began, so the client reconnects
+		// elsewhere instead of seeing a silent close
+		if d.draining.Load() {
+			logRequestf(ctx, "Refusing request, service is shutting down")
+			response := errorResponse(badezimmer.ErrorCode_UNAVAILABLE, "service is shutting down")
+			correlate(response, request)
+			if err := writeResponse(conn, response); err != nil {
+				logRequestf(ctx, "Error writing response: %v", err)
+			}
+			return
+		}
+
+		if hello := request.GetHello(); hello != nil {
+			compression = negotiateCompression(hello.Compression)
+			logRequestf(ctx, "Negotiated compression %q", compression)
+
+			response := helloResponse(compression)
+			correlate(response, request)
+			if err := writeResponse(conn, response); err != nil {
+				logRequestf(ctx, "Error writing response: %v", err)
+				return
+			}
+			continue
+		}
+
+		if d.requiresAuth(request) && !d.authenticated(request) {
+			logRequestf(ctx, "Rejecting unauthenticated request from %s", addr)
+			response := errorResponse(badezimmer.ErrorCode_UNAUTHENTICATED, "missing or invalid "+AuthTokenHeader+" header")
+			correlate(response, request)
+			if err := writeResponseCompressed(conn, response, compression); err != nil {
+				logRequestf(ctx, "Error writing response: %v", err)
+				return
+			}
+			continue
+		}
+
+		// Execute request; a nil response from the handler means it took
+		// over the connection until it ended
+		response := d.commonResponse(ctx, request)
+		if response == nil {
+			response = d.handler(withCompression(ctx, compression), conn, request)
+		}
+		if response == nil {
+			return
+		}
+		correlate(response, request)
+
+		// Send response
+		if err := writeResponseCompressed(conn, response, compression); err != nil {
+			logRequestf(ctx, "Error writing response: %v", err)
+			return
+		}
+	}
+}
+
+// commonResponse answers the requests every device handles the same way, and
+// returns nil for the others.
+func (d *Device) commonResponse(ctx context.Context, request *badezimmer.BadezimmerRequest) *badezimmer.BadezimmerResponse {
+	switch request.GetRequest().(type) {
+	case *badezimmer.BadezimmerRequest_Ping:
+		return pongResponse()
+	case *badezimmer.BadezimmerRequest_ListServices:
+		return d.listServicesResponse(ctx)
+	}
+	return nil
+}
+
+// listServicesResponse counts the registered instances of each service type.
+func (d *Device) listServicesResponse(ctx context.Context) *badezimmer.BadezimmerResponse {
+	counts := d.mdns.ServiceCounts()
+
+	types := make([]string, 0, len(counts))
+	for serviceType := range counts {
+		types = append(types, serviceType)
+	}
+	sort.Strings(types)
+
+	logRequestf(ctx, "Returning %d service types", len(types))
+
+	list := &badezimmer.ListServicesResponse{
+		Services: make([]*badezimmer.ServiceTypeCount, 0, len(types)),
+	}
+	for _, serviceType := range types {
+		list.Services = append(list.Services, &badezimmer.ServiceTypeCount{
+			Type:      serviceType,
+			Instances: int32(counts[serviceType]),
+		})
+	}
+
+	return &badezimmer.BadezimmerResponse{
+		Response: &badezimmer.BadezimmerResponse_ListServicesResponse{
+			ListServicesResponse: list,
+		},
+	}
+}
+
+// readFrame reads one length-prefixed message. A bad length or a message cut
+// short is reported as ErrMalformedFrame; failing to read the prefix returns
+// the read error as is, io.EOF when the peer closed between messages.
+func readFrame(conn net.Conn) ([]byte, error) {
+	lengthBuf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, lengthBuf); err != nil {
+		return nil, err
+	}
+
+	messageLength := binary.BigEndian.Uint32(lengthBuf)
+	if messageLength == 0 || messageLength > MaxFrameSize {
+		return nil, fmt.Errorf("%w: invalid message length %d", ErrMalformedFrame, messageLength)
+	}
+
+	messageBuf := make([]byte, messageLength)
+	if _, err := io.ReadFull(conn, messageBuf); err != nil {
+		return nil, fmt.Errorf("%w: truncated message: %v", ErrMalformedFrame, err)
+	}
+
+	return messageBuf, nil
+}
+
+// writeResponse sends a length-prefixed response.
+func writeResponse(conn net.Conn, response *badezimmer.BadezimmerResponse) error {
+	responseBytes, err := proto.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	responseLengthBuf := make([]byte, 4)
+	binary.BigEndian.PutUint32(responseLengthBuf, uint32(len(responseBytes)))
+
+	if _, err := conn.Write(responseLengthBuf); err != nil {
+		return fmt.Errorf("failed to write response length: %w", err)
+	}
+
+	if _, err := conn.Write(responseBytes); err != nil {
+		return fmt.Errorf("failed to write response: %w", err)
+	}
+
+	return nil
+}
+
+// pongResponse answers a ping with the server time.
+func pongResponse() *badezimmer.BadezimmerResponse {
+	return &badezimmer.BadezimmerResponse{
+		Response: &badezimmer.BadezimmerResponse_Pong{
+			Pong: &badezimmer.PongResponse{
+				ServerTime: timestamppb.Now(),
+			},
+		},
+	}
+}
+
+func errorResponse(code badezimmer.ErrorCode, message string) *badezimmer.BadezimmerResponse {
+	return &badezimmer.BadezimmerResponse{
+		Response: &badezimmer.BadezimmerResponse_Error{
+			Error: &badezimmer.ErrorDetails{
+				Code:    code,
+				Message: message,
+			},
+		},
+	}
+}
@@ -0,0 +1,718 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+)
+
+func pingRequest() *badezimmer.BadezimmerRequest {
+	return &badezimmer.BadezimmerRequest{
+		Request: &badezimmer.BadezimmerRequest_Ping{Ping: &badezimmer.PingRequest{}},
+	}
+}
+
+func TestTCPOnlyModeServesWithoutMulticast(t *testing.T) {
+	port, err := getRandomAvailableTCPPort()
+	if err != nil {
+		t.Fatalf("failed to pick a port: %v", err)
+	}
+	w := NewWaterLeakDetector(port, WithMDNSDisabled())
+	if err := w.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer w.Stop()
+
+	if w.mdns.conn != nil {
+		t.Errorf("multicast socket bound to %v in TCP-only mode", w.mdns.conn.LocalAddr())
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(int(port))), time.Second)
+	if err != nil {
+		t.Fatalf("failed to dial detector: %v", err)
+	}
+	defer conn.Close()
+	if samples := requestHistory(t, conn); len(samples) != 1 {
+		t.Errorf("got %d samples, want the initial one", len(samples))
+	}
+}
+
+func TestUnixSocketServesHistory(t *testing.T) {
+	port, err := getRandomAvailableTCPPort()
+	if err != nil {
+		t.Fatalf("failed to pick a port: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "detector.sock")
+	w := NewWaterLeakDetector(port, WithMDNSDisabled(), WithUnixSocket(path))
+	if err := w.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		t.Fatalf("failed to dial unix socket: %v", err)
+	}
+	defer conn.Close()
+	if samples := requestHistory(t, conn); len(samples) != 1 {
+		t.Errorf("got %d samples, want the initial one", len(samples))
+	}
+
+	conn.Close()
+	w.Stop()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("socket file left behind after Stop: %v", err)
+	}
+}
+
+func TestDrainingRefusesNewRequests(t *testing.T) {
+	clock := newFakeClock()
+	// Long enough that the drain never times out while the clock races
+	w := newTestDetector(t, clock, WithDrainTimeout(time.Hour))
+	conn := dialDevice(t, w.Device)
+	send(t, conn, pingRequest())
+
+	stopped := make(chan StopReport, 1)
+	go whileAdvancing(clock, func() {
+		report, _ := w.StopWithReport()
+		stopped <- report
+	})
+	waitFor(t, func() bool { return w.draining.Load() })
+
+	response := send(t, conn, pingRequest())
+	if code := response.GetError().GetCode(); code != badezimmer.ErrorCode_UNAVAILABLE {
+		t.Errorf("request while draining answered with %v, want UNAVAILABLE", response)
+	}
+	if _, err := readFrame(conn); err == nil {
+		t.Error("connection left open after the UNAVAILABLE response")
+	}
+
+	report := <-stopped
+	if report.ConnectionsDrained != 1 || report.ConnectionsForceClosed != 0 {
+		t.Errorf("drained %d and force closed %d connections, want 1 drained",
+			report.ConnectionsDrained, report.ConnectionsForceClosed)
+	}
+}
+
+func TestRequestFramingAndContentErrors(t *testing.T) {
+	w := NewWaterLeakDetector(0, WithMDNSDisabled())
+	conn := pipeTo(t, w)
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	// A frame that is not a request gets an error, and the connection
+	// stays usable
+	if _, err := conn.Write([]byte{0, 0, 0, 2, 0xff, 0xff}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	response := receive(t, conn)
+	if code := response.GetError().GetCode(); code != badezimmer.ErrorCode_VALIDATION_ERROR {
+		t.Errorf("malformed request answered with %v, want VALIDATION_ERROR", response)
+	}
+	if samples := requestHistory(t, conn); len(samples) != 1 {
+		t.Errorf("history after a malformed request has %d samples, want 1", len(samples))
+	}
+
+	// A bad length prefix leaves the stream out of sync, so it is closed
+	if _, err := conn.Write([]byte{0xff, 0xff, 0xff, 0x7f}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := readTestFrame(conn); err == nil {
+		t.Error("connection left open after a framing error")
+	}
+
+	stats := w.Stats()
+	if stats.RequestContentErrors != 1 || stats.RequestFramingErrors != 1 {
+		t.Errorf("RequestContentErrors = %d, RequestFramingErrors = %d, want 1 each",
+			stats.RequestContentErrors, stats.RequestFramingErrors)
+	}
+}
+
+func TestGenericDevice(t *testing.T) {
+	port, err := getRandomAvailableTCPPort()
+	if err != nil {
+		t.Fatalf("failed to pick a port: %v", err)
+	}
+	info := &MDNSServiceInfo{
+		Name:       "Hallway",
+		Type:       "_fartdetector._tcp.local.",
+		Port:       port,
+		Kind:       badezimmer.DeviceKind_SENSOR_KIND,
+		Category:   badezimmer.DeviceCategory_FART_DETECTOR,
+		Protocol:   badezimmer.TransportProtocol_TCP_PROTOCOL,
+		Addresses:  []string{"192.0.2.2"},
+		Properties: map[string]string{"intensity": "7"},
+		TTL:        DefaultTTL,
+	}
+	handler := func(ctx context.Context, conn net.Conn, request *badezimmer.BadezimmerRequest) *badezimmer.BadezimmerResponse {
+		return errorResponse(badezimmer.ErrorCode_INVALID_COMMAND, "no such command")
+	}
+
+	clock := newFakeClock()
+	d := NewDevice(info, handler, WithDeviceClock(clock), WithDeviceMulticastGroup(testGroup()))
+	capture := newPacketCapture(t)
+	d.mdns.responseTarget = capture.addr()
+	whileAdvancing(clock, func() { err = d.Start() })
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { whileAdvancing(clock, func() { d.Stop() }) })
+
+	// Registered and announced under its own type
+	domainName := generateDomainName(info.Type, info.Name)
+	if !d.mdns.isRegistered(domainName) {
+		t.Fatalf("%s is not registered", domainName)
+	}
+	for {
+		packet := capture.next(t)
+		if packet.GetQueryResponse() == nil {
+			continue // a probe
+		}
+		announced := recordsToInfo(packet.GetQueryResponse().GetAnswers())
+		if announced == nil || generateDomainName(announced.Type, announced.Name) != domainName {
+			t.Errorf("announced %v", packet.GetQueryResponse())
+		}
+		break
+	}
+
+	// and answers queries for it
+	clock.Advance(MulticastAnswerInterval)
+	deliver(t, d.mdns, &badezimmer.MDNS{
+		TransactionId: 7,
+		Data: &badezimmer.MDNS_QueryRequest{QueryRequest: &badezimmer.MDNSQueryRequest{
+			Questions: []*badezimmer.MDNSQuestion{{Name: info.Type, Type: badezimmer.MDNSType_MDNS_PTR}},
+		}},
+	})
+	if names := answeredNames(capture.next(t)); len(names) != 1 || names[0] != domainName {
+		t.Errorf("query answered with %v", names)
+	}
+
+	// The handler answers the device specific requests
+	conn := dialDevice(t, d)
+	response := send(t, conn, &badezimmer.BadezimmerRequest{
+		Request: &badezimmer.BadezimmerRequest_GetHistory{GetHistory: &badezimmer.GetHistoryRequest{}},
+	})
+	if code := response.GetError().GetCode(); code != badezimmer.ErrorCode_INVALID_COMMAND {
+		t.Errorf("request answered with %v, want the handler's response", response)
+	}
+	if response := send(t, conn, pingRequest()); response.GetPong() == nil {
+		t.Errorf("ping answered with %v", response)
+	}
+}
+
+func TestPingAnsweredWithServerTime(t *testing.T) {
+	w := NewWaterLeakDetector(0, WithMDNSDisabled())
+	conn := pipeTo(t, w)
+
+	var last time.Time
+	for range 3 {
+		before := time.Now()
+		pong := send(t, conn, pingRequest()).GetPong()
+		if pong == nil {
+			t.Fatal("ping not answered with a pong")
+		}
+		if took := time.Since(before); took > time.Second {
+			t.Errorf("pong took %v", took)
+		}
+
+		serverTime := pong.ServerTime.AsTime()
+		if serverTime.Before(before.Add(-time.Second)) || serverTime.After(time.Now().Add(time.Second)) {
+			t.Errorf("server time %v is not now", serverTime)
+		}
+		if serverTime.Before(last) {
+			t.Errorf("server time went back from %v to %v", last, serverTime)
+		}
+		last = serverTime
+	}
+}
+
+func TestListServicesCountsTypes(t *testing.T) {
+	w := NewWaterLeakDetector(0, WithMDNSDisabled())
+	for _, name := range []string{"Kitchen", "Bathroom"} {
+		addService(w.mdns, testService(name))
+	}
+	lamp := testService("Hallway")
+	lamp.Type = "_lamp._tcp.local"
+	addService(w.mdns, lamp)
+
+	conn := pipeTo(t, w)
+	response := send(t, conn, &badezimmer.BadezimmerRequest{
+		Request: &badezimmer.BadezimmerRequest_ListServices{ListServices: &badezimmer.ListServicesRequest{}},
+	})
+
+	got := make(map[string]int32)
+	for _, count := range response.GetListServicesResponse().GetServices() {
+		got[count.Type] = count.Instances
+	}
+	want := map[string]int32{"_lamp._tcp.local.": 1, "_waterleak._tcp.local.": 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("service counts = %v, want %v", got, want)
+	}
+}
+
+func TestStartAndStopTwice(t *testing.T) {
+	port, err := getRandomAvailableTCPPort()
+	if err != nil {
+		t.Fatalf("failed to pick a port: %v", err)
+	}
+	w := NewWaterLeakDetector(port, WithMDNSDisabled())
+	if err := w.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	if err := w.Start(); !errors.Is(err, ErrAlreadyStarted) {
+		t.Errorf("second Start() = %v, want ErrAlreadyStarted", err)
+	}
+
+	if err := w.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if err := w.Stop(); err != nil {
+		t.Errorf("second Stop() = %v, want nil", err)
+	}
+}
+
+func TestBusyPortAnnouncesNothing(t *testing.T) {
+	port, err := getRandomAvailableTCPPort()
+	if err != nil {
+		t.Fatalf("failed to pick a port: %v", err)
+	}
+	busy, err := net.Listen("tcp", net.JoinHostPort("0.0.0.0", strconv.Itoa(int(port))))
+	if err != nil {
+		t.Fatalf("failed to occupy port %d: %v", port, err)
+	}
+	defer busy.Close()
+
+	info := testService("Kitchen")
+	info.Port = port
+	d := NewDevice(info, nil, WithDeviceMulticastGroup(testGroup()))
+	capture := newPacketCapture(t)
+	d.mdns.responseTarget = capture.addr()
+
+	if err := d.Start(); err == nil {
+		d.Stop()
+		t.Fatal("Start succeeded on a busy port")
+	}
+	// Neither probed nor announced, so there is nothing to take back
+	capture.expectNone(t, 500*time.Millisecond)
+}
+
+func TestStartRetriesAfterBusyPortFreed(t *testing.T) {
+	port, err := getRandomAvailableTCPPort()
+	if err != nil {
+		t.Fatalf("failed to pick a port: %v", err)
+	}
+	busy, err := net.Listen("tcp", net.JoinHostPort("0.0.0.0", strconv.Itoa(int(port))))
+	if err != nil {
+		t.Fatalf("failed to occupy port %d: %v", port, err)
+	}
+
+	info := testService("Kitchen")
+	info.Port = port
+	d := NewDevice(info, nil, WithDeviceMulticastGroup(testGroup()))
+	d.mdns.responseTarget = newPacketCapture(t).addr()
+
+	if err := d.Start(); err == nil {
+		t.Fatal("Start succeeded on a busy port")
+	}
+
+	busy.Close()
+	if err := d.Start(); err != nil {
+		t.Fatalf("Start after the port was freed: %v", err)
+	}
+	t.Cleanup(func() { d.Stop() })
+	if !d.mdns.isRegistered(generateDomainName(info.Type, info.Name)) {
+		t.Error("service not registered after the second Start")
+	}
+}
+
+// scriptedListener returns the scripted results from Accept, a nil conn
+// meaning the error, then blocks until closed.
+type scriptedListener struct {
+	results   chan net.Conn
+	accepts   atomic.Int32
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newScriptedListener(results ...net.Conn) *scriptedListener {
+	l := &scriptedListener{results: make(chan net.Conn, len(results)), closed: make(chan struct{})}
+	for _, conn := range results {
+		l.results <- conn
+	}
+	return l
+}
+
+func (l *scriptedListener) Accept() (net.Conn, error) {
+	l.accepts.Add(1)
+	select {
+	case conn := <-l.results:
+		if conn == nil {
+			return nil, &net.OpError{Op: "accept", Net: "tcp", Err: syscall.EMFILE}
+		}
+		return conn, nil
+	default:
+	}
+	<-l.closed
+	return nil, net.ErrClosed
+}
+
+func (l *scriptedListener) Close() error {
+	l.closeOnce.Do(func() { close(l.closed) })
+	return nil
+}
+
+func (l *scriptedListener) Addr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}
+}
+
+func TestAcceptErrorsBackOff(t *testing.T) {
+	captureLog(t)
+	clock := newFakeClock()
+	d := newDevice()
+	d.clock = clock
+
+	server, client := net.Pipe()
+	client.Close()
+	failures := make([]net.Conn, 10)
+	l := newScriptedListener(append(append(failures, server), nil, nil)...)
+	defer l.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.acceptLoop(l)
+	}()
+
+	// The delay doubles from the minimum up to the maximum, and starts over
+	// after an accept succeeds
+	var delays []time.Duration
+	for delay := acceptBackoffMin; len(delays) < len(failures); delay = min(2*delay, acceptBackoffMax) {
+		delays = append(delays, delay)
+	}
+	delays = append(delays, acceptBackoffMin)
+
+	for i, delay := range delays {
+		clock.waitForPending(t, 1)
+		clock.Advance(delay - time.Millisecond)
+		if n := clock.pending(); n != 1 {
+			t.Fatalf("retry %d happened before %v", i, delay)
+		}
+		clock.Advance(time.Millisecond)
+
+		// The accept after the last failure succeeds and is followed by
+		// another failure right away
+		want := int32(i + 2)
+		if i >= len(failures)-1 {
+			want++
+		}
+		waitFor(t, func() bool { return l.accepts.Load() == want })
+	}
+
+	// Cancelling stops the retries without waiting for the delay
+	clock.waitForPending(t, 1)
+	d.cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("accept loop still running after cancellation")
+	}
+}
+
+func TestStopReportCountsOpenConnections(t *testing.T) {
+	clock := newFakeClock()
+	w := newTestDetector(t, clock, WithDrainTimeout(5*time.Second))
+	for range 3 {
+		send(t, dialDevice(t, w.Device), pingRequest())
+	}
+	clock.Advance(time.Minute)
+
+	// The idle connections outlast the drain timeout
+	var report StopReport
+	var err error
+	whileAdvancing(clock, func() { report, err = w.StopWithReport() })
+	if err != nil {
+		t.Fatalf("StopWithReport: %v", err)
+	}
+	if report.ConnectionsDrained != 0 || report.ConnectionsForceClosed != 3 {
+		t.Errorf("drained %d and force closed %d connections, want 3 force closed",
+			report.ConnectionsDrained, report.ConnectionsForceClosed)
+	}
+	if report.Uptime != time.Minute {
+		t.Errorf("Uptime = %v, want 1m0s", report.Uptime)
+	}
+	waitFor(t, func() bool { return w.openConns() == 0 })
+}
+
+func TestStopClosesListenerBeforeGoodbyes(t *testing.T) {
+	captureLog(t)
+	clock := newFakeClock()
+	port, err := getRandomAvailableTCPPort()
+	if err != nil {
+		t.Fatalf("failed to pick a port: %v", err)
+	}
+	w := NewWaterLeakDetector(port, WithDeviceClock(clock), WithDeviceMulticastGroup(testGroup()),
+		WithDrainTimeout(time.Hour))
+	whileAdvancing(clock, func() { err = w.Start() })
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	// An open connection holds the shutdown in the drain
+	conn := dialDevice(t, w.Device)
+	send(t, conn, pingRequest())
+	stopped := make(chan StopReport, 1)
+	go whileAdvancing(clock, func() {
+		report, _ := w.StopWithReport()
+		stopped <- report
+	})
+	waitFor(t, func() bool { return w.draining.Load() })
+
+	address := net.JoinHostPort("127.0.0.1", strconv.Itoa(int(port)))
+	waitFor(t, func() bool {
+		late, err := net.Dial("tcp", address)
+		if err == nil {
+			late.Close()
+		}
+		return err != nil
+	})
+	if sent := w.mdns.Stats().GoodbyesSent; sent != 0 {
+		t.Errorf("sent %d goodbyes while draining", sent)
+	}
+
+	conn.Close()
+	if report := <-stopped; report.GoodbyesSent == 0 {
+		t.Error("no goodbyes sent after the drain")
+	}
+}
+
+func TestAcceptWhileDrainingClosesConnection(t *testing.T) {
+	d := newDevice()
+	d.draining.Store(true)
+	server, client := net.Pipe()
+	defer client.Close()
+	l := newScriptedListener(server)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.acceptLoop(l)
+	}()
+
+	client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, err := client.Read(make([]byte, 1)); err == nil || errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("connection accepted while draining was served: %v", err)
+	}
+	if n := d.openConns(); n != 0 {
+		t.Errorf("%d connections tracked", n)
+	}
+
+	l.Close()
+	<-done
+}
+
+func TestStartRefusesMDNSPort(t *testing.T) {
+	ip, port := testGroup()
+	w := NewWaterLeakDetector(int32(port), WithDeviceMulticastGroup(ip, port))
+	if err := w.Start(); !errors.Is(err, ErrPortCollision) {
+		w.Stop()
+		t.Fatalf("Start() = %v, want ErrPortCollision", err)
+	}
+
+	// Without mDNS the port is free for the listener
+	clock := newFakeClock()
+	w = NewWaterLeakDetector(int32(port), WithMDNSDisabled(), WithDeviceClock(clock), WithDeviceMulticastGroup(ip, port))
+	if err := w.Start(); err != nil {
+		t.Fatalf("Start in TCP-only mode: %v", err)
+	}
+	whileAdvancing(clock, func() { w.Stop() })
+}
+
+// startHandlerDevice starts a TCP-only device whose requests go to handler.
+func startHandlerDevice(t *testing.T, handler RequestHandler, opts ...DeviceOption) *Device {
+	t.Helper()
+	port, err := getRandomAvailableTCPPort()
+	if err != nil {
+		t.Fatalf("failed to pick a port: %v", err)
+	}
+	info := testService("Hallway")
+	info.Port = port
+
+	clock := newFakeClock()
+	opts = append([]DeviceOption{WithMDNSDisabled(), WithDeviceClock(clock)}, opts...)
+	d := NewDevice(info, handler, opts...)
+	if err := d.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { whileAdvancing(clock, func() { d.Stop() }) })
+	return d
+}
+
+func TestHeadersReachHandlerAndAreEchoed(t *testing.T) {
+	seen := make(chan map[string]string, 1)
+	d := startHandlerDevice(t, func(ctx context.Context, conn net.Conn, request *badezimmer.BadezimmerRequest) *badezimmer.BadezimmerResponse {
+		seen <- HeadersFromContext(ctx)
+		return errorResponse(badezimmer.ErrorCode_INVALID_COMMAND, "no such command")
+	})
+
+	headers := map[string]string{"trace-id": "abc123", AuthTokenHeader: "secret"}
+	response := send(t, dialDevice(t, d), &badezimmer.BadezimmerRequest{
+		Headers: headers,
+		Request: &badezimmer.BadezimmerRequest_GetHistory{GetHistory: &badezimmer.GetHistoryRequest{}},
+	})
+
+	if got := <-seen; !reflect.DeepEqual(got, headers) {
+		t.Errorf("handler saw headers %v, want %v", got, headers)
+	}
+	// The token is never sent back
+	if want := map[string]string{"trace-id": "abc123"}; !reflect.DeepEqual(response.Headers, want) {
+		t.Errorf("response headers %v, want %v", response.Headers, want)
+	}
+}
+
+func actuatorRequest(token string) *badezimmer.BadezimmerRequest {
+	request := &badezimmer.BadezimmerRequest{
+		Request: &badezimmer.BadezimmerRequest_SendActuatorCommand{SendActuatorCommand: &badezimmer.SendActuatorCommandRequest{}},
+	}
+	if token != "" {
+		request.Headers = map[string]string{AuthTokenHeader: token}
+	}
+	return request
+}
+
+func TestAuthToken(t *testing.T) {
+	handled := func(ctx context.Context, conn net.Conn, request *badezimmer.BadezimmerRequest) *badezimmer.BadezimmerResponse {
+		return &badezimmer.BadezimmerResponse{Response: &badezimmer.BadezimmerResponse_Pong{Pong: &badezimmer.PongResponse{}}}
+	}
+	history := &badezimmer.BadezimmerRequest{
+		Request: &badezimmer.BadezimmerRequest_GetHistory{GetHistory: &badezimmer.GetHistoryRequest{}},
+	}
+
+	tests := []struct {
+		name     string
+		opts     []DeviceOption
+		request  *badezimmer.BadezimmerRequest
+		rejected bool
+	}{
+		{"no token configured", nil, actuatorRequest(""), false},
+		{"command without token", []DeviceOption{WithAuthToken("secret")}, actuatorRequest(""), true},
+		{"command with wrong token", []DeviceOption{WithAuthToken("secret")}, actuatorRequest("guess"), true},
+		{"command with token", []DeviceOption{WithAuthToken("secret")}, actuatorRequest("secret"), false},
+		{"read left open", []DeviceOption{WithAuthToken("secret")}, history, false},
+		{"read gated", []DeviceOption{WithAuthToken("secret"), WithReadAuth(true)}, history, true},
+		{"ping always open", []DeviceOption{WithAuthToken("secret"), WithReadAuth(true)}, pingRequest(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := startHandlerDevice(t, handled, tt.opts...)
+			response := send(t, dialDevice(t, d), tt.request)
+			rejected := response.GetError().GetCode() == badezimmer.ErrorCode_UNAUTHENTICATED
+			if rejected != tt.rejected {
+				t.Errorf("answered %v, want rejected %v", response, tt.rejected)
+			}
+		})
+	}
+}
+
+func TestStateShowsMemberships(t *testing.T) {
+	d := newDevice()
+	d.init(testService("Hallway"), nil)
+	d.mdns.addMemberships(5369, "eth0")
+
+	recorder := httptest.NewRecorder()
+	d.handleState(recorder, httptest.NewRequest(http.MethodGet, "/state", nil))
+	var state DetectorState
+	if err := json.NewDecoder(recorder.Body).Decode(&state); err != nil {
+		t.Fatalf("failed to decode state: %v", err)
+	}
+	if len(state.Memberships) != 1 || state.Memberships[0].Interface != "eth0" || state.Memberships[0].Port != 5369 {
+		t.Errorf("state memberships = %+v", state.Memberships)
+	}
+}
+
+// Run with -race: probing renames the service while the state is read.
+func TestStateReadDuringStart(t *testing.T) {
+	port, err := getRandomAvailableTCPPort()
+	if err != nil {
+		t.Fatalf("failed to pick a port: %v", err)
+	}
+	info := testService("Hallway")
+	info.Port = port
+	d := NewDevice(info, nil, WithDeviceMulticastGroup(testGroup()))
+	d.mdns.responseTarget = newPacketCapture(t).addr()
+
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	readers.Add(1)
+	go func() {
+		defer readers.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				d.State()
+			}
+		}
+	}()
+
+	err = d.Start()
+	if err == nil {
+		d.Stop()
+	}
+	close(done)
+	readers.Wait()
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	if name := d.State().Name; name != "Hallway" {
+		t.Errorf("state name = %q, want Hallway", name)
+	}
+}
+
+func TestHealthPortAdvertised(t *testing.T) {
+	captureLog(t)
+	clock := newFakeClock()
+	port, err := getRandomAvailableTCPPort()
+	if err != nil {
+		t.Fatalf("failed to pick a port: %v", err)
+	}
+	w := NewWaterLeakDetector(port, WithDeviceClock(clock), WithDeviceMulticastGroup(testGroup()),
+		WithStateAddr("127.0.0.1:0"))
+	whileAdvancing(clock, func() { err = w.Start() })
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { whileAdvancing(clock, func() { w.Stop() }) })
+
+	info := w.snapshotInfo()
+	w.mdns.mu.Lock()
+	registered := w.mdns.registeredServices[generateDomainName(info.Type, info.Name)].Clone()
+	w.mdns.mu.Unlock()
+
+	healthPort := registered.Properties[HealthPortProperty]
+	if healthPort == "" || healthPort == "0" {
+		t.Fatalf("registered TXT %v lacks the bound %s", registered.Properties, HealthPortProperty)
+	}
+	response, err := http.Get("http://" + net.JoinHostPort("127.0.0.1", healthPort) + "/state")
+	if err != nil {
+		t.Fatalf("state server not on the advertised port: %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("GET /state = %s", response.Status)
+	}
+}
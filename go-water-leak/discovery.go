@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// discoveryBufferSize is how many services a discovery can fall behind by
+// before announcements are dropped. They still reach it through the cache
+// when it ends.
+const discoveryBufferSize = 64
+
+// discoverySub receives the services announced while a DiscoverServices call
+// is running, already filtered by its type.
+type discoverySub struct {
+	serviceType string
+	services    chan *MDNSServiceInfo
+}
+
+func (s *discoverySub) matches(info *MDNSServiceInfo) bool {
+	return s.serviceType == normalizeServiceType(ServiceDiscoveryType) ||
+		s.serviceType == normalizeServiceType(info.Type)
+}
+
+// discoveryHub fans the services from incoming responses out to every
+// running discovery, solicited or not.
+type discoveryHub struct {
+	mu   sync.Mutex
+	subs map[*discoverySub]struct{}
+}
+
+func newDiscoveryHub() *discoveryHub {
+	return &discoveryHub{subs: make(map[*discoverySub]struct{})}
+}
+
+func (h *discoveryHub) subscribe(serviceType string) *discoverySub {
+	sub := &discoverySub{
+		serviceType: normalizeServiceType(serviceType),
+		services:    make(chan *MDNSServiceInfo, discoveryBufferSize),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subs[sub] = struct{}{}
+	return sub
+}
+
+func (h *discoveryHub) unsubscribe(sub *discoverySub) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subs, sub)
+}
+
+func (h *discoveryHub) publish(info *MDNSServiceInfo) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subs {
+		if !sub.matches(info) {
+			continue
+		}
+		select {
+		case sub.services <- info.Clone():
+		default:
+		}
+	}
+}
+
+// DiscoverServices queries the network for serviceType and returns the
+// services that answered or announced themselves until ctx is done, along
+// with those cached before the call. Use ServiceDiscoveryType to discover
+// every service. Concurrent calls only see services of their own type.
+// Start must have been called.
+func (m *BadezimmerMDNS) DiscoverServices(ctx context.Context, serviceType string) ([]*MDNSServiceInfo, error) {
+	sub := m.discoveries.subscribe(serviceType)
+	defer m.discoveries.unsubscribe(sub)
+
+	if err := m.sendQuery(normalizeServiceType(serviceType)); err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", serviceType, err)
+	}
+
+	found := make(map[string]*MDNSServiceInfo)
+	for _, info := range m.cache.ofType(serviceType, m.clock.Now()) {
+		found[generateDomainName(info.Type, info.Name)] = info
+	}
+
+	for {
+		select {
+		case info := <-sub.services:
+			found[generateDomainName(info.Type, info.Name)] = info
+		case <-ctx.Done():
+			// Take the announcements that arrived but were not read yet
+			for pending := true; pending; {
+				select {
+				case info := <-sub.services:
+					found[generateDomainName(info.Type, info.Name)] = info
+				default:
+					pending = false
+				}
+			}
+
+			// Catch up on announcements dropped while we were behind
+			for _, info := range m.cache.ofType(serviceType, m.clock.Now()) {
+				key := generateDomainName(info.Type, info.Name)
+				if _, ok := found[key]; !ok {
+					found[key] = info
+				}
+			}
+
+			services := make([]*MDNSServiceInfo, 0, len(found))
+			for _, info := range found {
+				services = append(services, info)
+			}
+			return services, nil
+		}
+	}
+}
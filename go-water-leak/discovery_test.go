@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+)
+
+func discoverySubs(m *BadezimmerMDNS) int {
+	m.discoveries.mu.Lock()
+	defer m.discoveries.mu.Unlock()
+	return len(m.discoveries.subs)
+}
+
+func TestConcurrentDiscoveriesSeeOnlyTheirType(t *testing.T) {
+	m, _ := newCapturedResponder(t)
+
+	serviceTypes := []string{"_waterleak._tcp.local.", "_toilet._tcp.local.", ServiceDiscoveryType}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := make([]chan []string, len(serviceTypes))
+	for i, serviceType := range serviceTypes {
+		results[i] = make(chan []string, 1)
+		go func() {
+			services, err := m.DiscoverServices(ctx, serviceType)
+			if err != nil {
+				t.Errorf("DiscoverServices(%s): %v", serviceType, err)
+			}
+			var names []string
+			for _, info := range services {
+				names = append(names, info.Name)
+			}
+			sort.Strings(names)
+			results[i] <- names
+		}()
+	}
+	waitFor(t, func() bool { return discoverySubs(m) == len(serviceTypes) })
+
+	// Unsolicited announcements of both types
+	leak := testService("Kitchen")
+	toilet := testService("Upstairs")
+	toilet.Type = "_toilet._tcp.local."
+	toilet.Category = badezimmer.DeviceCategory_TOILET
+	announce(m, leak)
+	announce(m, toilet)
+
+	// Without the cache the services can only come from the fan-out
+	m.FlushCache()
+	cancel()
+
+	want := [][]string{{"Kitchen"}, {"Upstairs"}, {"Kitchen", "Upstairs"}}
+	for i, serviceType := range serviceTypes {
+		if got := <-results[i]; !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("discovering %s found %v, want %v", serviceType, got, want[i])
+		}
+	}
+	if n := discoverySubs(m); n != 0 {
+		t.Errorf("%d discoveries still subscribed", n)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+)
+
+// DNS resource record types and class from RFC 1035 and RFC 2782
+const (
+	dnsTypeA   = 1
+	dnsTypePTR = 12
+	dnsTypeTXT = 16
+	dnsTypeSRV = 33
+
+	dnsClassIN         = 1
+	dnsCacheFlushClass = 0x8000
+)
+
+// EncodeStandardDNS renders records as an RFC 1035 response message, with
+// name compression, so they can be checked by standard mDNS tooling. It is
+// meant for diagnostics only; the wire protocol stays protobuf.
+func EncodeStandardDNS(records []*badezimmer.MDNSRecord) ([]byte, error) {
+	e := &dnsEncoder{names: make(map[string]int)}
+
+	// Header: id 0, flags QR|AA, no questions, every record as an answer
+	e.buf = binary.BigEndian.AppendUint16(e.buf, 0)
+	e.buf = binary.BigEndian.AppendUint16(e.buf, 0x8400)
+	e.buf = binary.BigEndian.AppendUint16(e.buf, 0)
+	e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(len(records)))
+	e.buf = binary.BigEndian.AppendUint16(e.buf, 0)
+	e.buf = binary.BigEndian.AppendUint16(e.buf, 0)
+
+	for _, record := range records {
+		if err := e.encodeRecord(record); err != nil {
+			return nil, fmt.Errorf("failed to encode record %s: %w", record.Name, err)
+		}
+	}
+
+	return e.buf, nil
+}
+
+type dnsEncoder struct {
+	buf []byte
+	// names maps each encoded name suffix to its offset for compression
+	names map[string]int
+}
+
+func (e *dnsEncoder) encodeRecord(record *badezimmer.MDNSRecord) error {
+	var rrType uint16
+	switch record.Record.(type) {
+	case *badezimmer.MDNSRecord_PtrRecord:
+		rrType = dnsTypePTR
+	case *badezimmer.MDNSRecord_ARecord:
+		rrType = dnsTypeA
+	case *badezimmer.MDNSRecord_SrvRecord:
+		rrType = dnsTypeSRV
+	case *badezimmer.MDNSRecord_TxtRecord:
+		rrType = dnsTypeTXT
+	default:
+		return fmt.Errorf("unsupported record type %T", record.Record)
+	}
+
+	if err := e.encodeName(record.Name); err != nil {
+		return err
+	}
+
+	class := uint16(dnsClassIN)
+	if record.CacheFlush {
+		class |= dnsCacheFlushClass
+	}
+	e.buf = binary.BigEndian.AppendUint16(e.buf, rrType)
+	e.buf = binary.BigEndian.AppendUint16(e.buf, class)
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(record.Ttl))
+
+	// RDLENGTH is patched once the data is written
+	lengthOffset := len(e.buf)
+	e.buf = append(e.buf, 0, 0)
+
+	switch r := record.Record.(type) {
+	case *badezimmer.MDNSRecord_PtrRecord:
+		if err := e.encodeName(r.PtrRecord.DomainName); err != nil {
+			return err
+		}
+	case *badezimmer.MDNSRecord_ARecord:
+		ip := net.ParseIP(r.ARecord.Address).To4()
+		if ip == nil {
+			return fmt.Errorf("invalid IPv4 address %q", r.ARecord.Address)
+		}
+		e.buf = append(e.buf, ip...)
+	case *badezimmer.MDNSRecord_SrvRecord:
+		// Priority and weight are not modelled, both are zero
+		e.buf = binary.BigEndian.AppendUint16(e.buf, 0)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, 0)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(r.SrvRecord.Port))
+		if err := e.encodeName(r.SrvRecord.Target); err != nil {
+			return err
+		}
+	case *badezimmer.MDNSRecord_TxtRecord:
+		if err := e.encodeTXT(r.TxtRecord.Entries); err != nil {
+			return err
+		}
+	}
+
+	binary.BigEndian.PutUint16(e.buf[lengthOffset:], uint16(len(e.buf)-lengthOffset-2))
+	return nil
+}
+
+// encodeName writes name as labels, replacing the longest suffix already
+// written with a compression pointer.
+func (e *dnsEncoder) encodeName(name string) error {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" {
+		e.buf = append(e.buf, 0)
+		return nil
+	}
+
+	labels := nameLabels(name)
+	for i := range labels {
+		suffix := strings.ToLower(strings.Join(labels[i:], "."))
+		if offset, ok := e.names[suffix]; ok {
+			e.buf = binary.BigEndian.AppendUint16(e.buf, 0xC000|uint16(offset))
+			return nil
+		}
+
+		label := labels[i]
+		if len(label) == 0 {
+			return fmt.Errorf("empty label in name %q", name)
+		}
+		if len(label) > MaxLabelLength {
+			return fmt.Errorf("%w: %q", ErrLabelTooLong, label)
+		}
+		// Pointers only reach the first 14 bits of the message
+		if len(e.buf) < 0x4000 {
+			e.names[suffix] = len(e.buf)
+		}
+		e.buf = append(e.buf, byte(len(label)))
+		e.buf = append(e.buf, label...)
+	}
+
+	e.buf = append(e.buf, 0)
+	return nil
+}
+
+// nameLabels splits name into DNS labels. The instance name of a service is
+// a single label even if it contains dots: it is everything before the
+// service type, whose labels start with an underscore.
+func nameLabels(name string) []string {
+	if i := strings.Index(name, "._"); i > 0 {
+		return append([]string{name[:i]}, strings.Split(name[i+1:], ".")...)
+	}
+	return strings.Split(name, ".")
+}
+
+// encodeTXT writes the entries as key=value strings, sorted by key.
+func (e *dnsEncoder) encodeTXT(entries map[string]string) error {
+	if len(entries) == 0 {
+		// A TXT record holds at least one, possibly empty, string
+		e.buf = append(e.buf, 0)
+		return nil
+	}
+
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		entry := k + "=" + entries[k]
+		if len(entry) > 255 {
+			return fmt.Errorf("TXT entry %q exceeds 255 bytes", k)
+		}
+		e.buf = append(e.buf, byte(len(entry)))
+		e.buf = append(e.buf, entry...)
+	}
+	return nil
+}
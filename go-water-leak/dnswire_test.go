@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestEncodeStandardDNSParses(t *testing.T) {
+	info := &MDNSServiceInfo{
+		Name:       "Kitchen Sensor",
+		Type:       "_waterleak._tcp.local.",
+		Port:       8080,
+		Addresses:  []string{"192.0.2.2"},
+		TTL:        120,
+		Properties: map[string]string{"severity": "3"},
+	}
+
+	msg, err := EncodeStandardDNS(infoToRecords(info))
+	if err != nil {
+		t.Fatalf("EncodeStandardDNS: %v", err)
+	}
+	var parsed dnsmessage.Message
+	if err := parsed.Unpack(msg); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(parsed.Questions) != 0 {
+		t.Errorf("unexpected questions %v", parsed.Questions)
+	}
+
+	answers := make(map[dnsmessage.Type]dnsmessage.Resource)
+	for _, rr := range parsed.Answers {
+		answers[rr.Header.Type] = rr
+	}
+	for _, rtype := range []dnsmessage.Type{dnsmessage.TypePTR, dnsmessage.TypeA, dnsmessage.TypeSRV, dnsmessage.TypeTXT} {
+		if _, ok := answers[rtype]; !ok {
+			t.Fatalf("missing record of type %v in %v", rtype, parsed.Answers)
+		}
+	}
+
+	ptr := answers[dnsmessage.TypePTR]
+	if got := ptr.Header.Name.String(); got != "_waterleak._tcp.local." {
+		t.Errorf("PTR name = %q", got)
+	}
+	if got := ptr.Body.(*dnsmessage.PTRResource).PTR.String(); got != "Kitchen Sensor._waterleak._tcp.local." {
+		t.Errorf("PTR target = %q", got)
+	}
+	if got := answers[dnsmessage.TypeA].Body.(*dnsmessage.AResource).A; got != [4]byte{192, 0, 2, 2} {
+		t.Errorf("A = %v", got)
+	}
+	if got := answers[dnsmessage.TypeSRV].Body.(*dnsmessage.SRVResource).Port; got != 8080 {
+		t.Errorf("SRV port = %d", got)
+	}
+	if txt := answers[dnsmessage.TypeTXT].Body.(*dnsmessage.TXTResource).TXT; !slices.Contains(txt, "severity=3") {
+		t.Errorf("TXT = %q", txt)
+	}
+}
+
+func TestEncodeStandardDNSDottedInstance(t *testing.T) {
+	records := NewRecordSet("Living.Room v2._waterleak._tcp.local.").AddPTR("_waterleak._tcp.local.").Build()
+
+	msg, err := EncodeStandardDNS(records)
+	if err != nil {
+		t.Fatalf("EncodeStandardDNS: %v", err)
+	}
+	var p dnsmessage.Parser
+	if _, err := p.Start(msg); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if err := p.SkipAllQuestions(); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	header, err := p.AnswerHeader()
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if header.Type != dnsmessage.TypePTR || header.Name.String() != "_waterleak._tcp.local." {
+		t.Errorf("answer %v, want the PTR of the service type", header)
+	}
+
+	// dnsmessage refuses dots within labels, so the target is read raw. The
+	// instance must be a single label, dot included.
+	target, err := p.UnknownResource()
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	instance := "Living.Room v2"
+	if data := target.Data; len(data) <= len(instance) || int(data[0]) != len(instance) || string(data[1:1+len(instance)]) != instance {
+		t.Errorf("PTR target %q does not start with the label %q", data, instance)
+	}
+	if _, err := p.AnswerHeader(); !errors.Is(err, dnsmessage.ErrSectionDone) {
+		t.Errorf("more than one answer: %v", err)
+	}
+}
+
+func TestEncodeStandardDNSRejectsLongLabel(t *testing.T) {
+	long := strings.Repeat("a", MaxLabelLength+1)
+	records := NewRecordSet("x._waterleak._tcp.local.").WithTarget(long+".local.").AddSRV(8080, 0).Build()
+
+	if _, err := EncodeStandardDNS(records); err == nil {
+		t.Fatal("expected an error for an over-long label")
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"log"
+	"net"
+	"time"
+)
+
+// DefaultDrainTimeout is how long Stop lets open connections finish before
+// closing them.
+const DefaultDrainTimeout = 2 * time.Second
+
+// StopReport summarizes a shutdown.
+type StopReport struct {
+	// Goodbye packets sent for the registered services
+	GoodbyesSent uint64 `json:"goodbyes_sent"`
+	// Connections that ended on their own during the drain timeout
+	ConnectionsDrained int `json:"connections_drained"`
+	// Connections still open after the drain timeout
+	ConnectionsForceClosed int           `json:"connections_force_closed"`
+	Uptime                 time.Duration `json:"uptime"`
+}
+
+// WithDrainTimeout sets how long Stop waits for open connections, which get
+// UNAVAILABLE for any further request, before closing them.
+func WithDrainTimeout(timeout time.Duration) DeviceOption {
+	return func(d *Device) {
+		d.drainTimeout = timeout
+	}
+}
+
+func (d *Device) trackConn(conn net.Conn) {
+	d.connsMu.Lock()
+	defer d.connsMu.Unlock()
+	d.conns[conn] = struct{}{}
+}
+
+func (d *Device) untrackConn(conn net.Conn) {
+	d.connsMu.Lock()
+	defer d.connsMu.Unlock()
+	delete(d.conns, conn)
+}
+
+func (d *Device) openConns() int {
+	d.connsMu.Lock()
+	defer d.connsMu.Unlock()
+	return len(d.conns)
+}
+
+// drainConnections waits up to the drain timeout for the open connections to
+// end, then closes the rest. It returns how many ended on their own and how
+// many were closed.
+func (d *Device) drainConnections() (drained, forceClosed int) {
+	open := d.openConns()
+	if open == 0 {
+		return 0, 0
+	}
+
+	log.Printf("Draining %d connections", open)
+
+	deadline := d.clock.After(d.drainTimeout)
+	for d.openConns() > 0 {
+		select {
+		case <-deadline:
+			d.connsMu.Lock()
+			for conn := range d.conns {
+				conn.Close()
+				forceClosed++
+			}
+			d.connsMu.Unlock()
+			return open - forceClosed, forceClosed
+		case <-d.clock.After(10 * time.Millisecond):
+		}
+	}
+
+	return open, 0
+}
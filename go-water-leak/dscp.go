@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+// MaxDSCP is the largest Differentiated Services Code Point, which takes
+// the upper six bits of the IP TOS byte.
+const MaxDSCP = 63
+
+var ErrInvalidDSCP = errors.New("DSCP must be between 0 and 63")
+
+func validateDSCP(dscp int) error {
+	if dscp < 0 || dscp > MaxDSCP {
+		return fmt.Errorf("%w, got %d", ErrInvalidDSCP, dscp)
+	}
+	return nil
+}
+
+// setDSCP marks the packets sent from fd with dscp. The low two bits of the
+// TOS byte belong to ECN and are left clear.
+func setDSCP(fd int, dscp int) error {
+	return syscall.SetsockoptInt(fd, syscall.IPPROTO_IP, syscall.IP_TOS, dscp<<2)
+}
+
+// dscpControl is a net.ListenConfig Control function marking the socket
+// with dscp.
+func dscpControl(dscp int) func(network, address string, c syscall.RawConn) error {
+	return func(network, address string, c syscall.RawConn) error {
+		var opErr error
+		err := c.Control(func(fd uintptr) {
+			opErr = setDSCP(int(fd), dscp)
+		})
+		if err != nil {
+			return err
+		}
+		return opErr
+	}
+}
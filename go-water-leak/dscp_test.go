@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+)
+
+// tos reads IP_TOS back from conn.
+func tos(t *testing.T, conn syscall.Conn) int {
+	t.Helper()
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		t.Fatalf("SyscallConn: %v", err)
+	}
+	var value int
+	var opErr error
+	if err := raw.Control(func(fd uintptr) {
+		value, opErr = syscall.GetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_TOS)
+	}); err != nil {
+		t.Fatalf("Control: %v", err)
+	}
+	if opErr != nil {
+		t.Fatalf("failed to read IP_TOS: %v", opErr)
+	}
+	return value
+}
+
+func TestDSCPMarksMulticastSocket(t *testing.T) {
+	m, _ := startTestResponder(t, WithDSCP(46))
+	if got := tos(t, m.conn); got != 46<<2 {
+		t.Errorf("IP_TOS = %#x, want %#x", got, 46<<2)
+	}
+}
+
+func TestDSCPMarksControlListener(t *testing.T) {
+	clock := newFakeClock()
+	w := newTestDetector(t, clock, WithDeviceDSCP(10))
+	if got := tos(t, w.listener.(syscall.Conn)); got != 10<<2 {
+		t.Errorf("IP_TOS = %#x, want %#x", got, 10<<2)
+	}
+}
+
+func TestDSCPOutOfRange(t *testing.T) {
+	for _, dscp := range []int{-1, MaxDSCP + 1} {
+		m := NewBadezimmerMDNS(WithDSCP(dscp))
+		if err := m.Start(); !errors.Is(err, ErrInvalidDSCP) {
+			m.Close()
+			t.Errorf("Start with DSCP %d = %v, want ErrInvalidDSCP", dscp, err)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+//go:build e2e
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"sort"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+	"google.golang.org/protobuf/proto"
+)
+
+// The end-to-end tests run real detectors over multicast, so they need a
+// network that delivers multicast on this host. Run them with
+//
+//	go test -tags e2e -run E2E ./...
+
+// isolatedGroup returns a multicast group and port of our own, so runs don't
+// see each other or real devices.
+func isolatedGroup() (string, int) {
+	return fmt.Sprintf("239.255.%d.%d", rand.Intn(256), 1+rand.Intn(254)), 20000 + rand.Intn(20000)
+}
+
+func startDetector(t *testing.T, ip string, port int) *WaterLeakDetector {
+	t.Helper()
+	tcpPort, err := getRandomAvailableTCPPort()
+	if err != nil {
+		t.Fatalf("failed to pick a TCP port: %v", err)
+	}
+
+	w := NewWaterLeakDetector(tcpPort, WithDeviceMulticastGroup(ip, port))
+	if err := w.Start(); err != nil {
+		t.Fatalf("failed to start detector: %v", err)
+	}
+	return w
+}
+
+// groupListener joins the group like a peer would, to query the detectors
+// and watch what they send.
+func groupListener(t *testing.T, ip string, port int) *net.UDPConn {
+	t.Helper()
+	lc := net.ListenConfig{
+		Control: func(network, address string, c syscall.RawConn) error {
+			var opErr error
+			err := c.Control(func(fd uintptr) {
+				opErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_REUSEADDR, 1)
+				if opErr != nil {
+					return
+				}
+				opErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, SO_REUSEPORT, 1)
+			})
+			if err != nil {
+				return err
+			}
+			return opErr
+		},
+	}
+	packetConn, err := lc.ListenPacket(context.Background(), "udp4", fmt.Sprintf("0.0.0.0:%d", port))
+	if err != nil {
+		t.Fatalf("failed to listen on the group port: %v", err)
+	}
+	conn := packetConn.(*net.UDPConn)
+	t.Cleanup(func() { conn.Close() })
+
+	group := net.ParseIP(ip).To4()
+	mreq := &syscall.IPMreq{Multiaddr: [4]byte{group[0], group[1], group[2], group[3]}}
+	file, err := conn.File()
+	if err != nil {
+		t.Fatalf("failed to get socket file: %v", err)
+	}
+	defer file.Close()
+	if err := syscall.SetsockoptIPMreq(int(file.Fd()), syscall.IPPROTO_IP, syscall.IP_ADD_MEMBERSHIP, mreq); err != nil {
+		t.Fatalf("failed to join %s: %v", ip, err)
+	}
+	return conn
+}
+
+// recordsFor reads responses until one carries records for domainName, and
+// returns them.
+func recordsFor(t *testing.T, conn *net.UDPConn, domainName string, deadline time.Time) []*badezimmer.MDNSRecord {
+	t.Helper()
+	buf := make([]byte, 65536)
+	conn.SetReadDeadline(deadline)
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			t.Fatalf("no response for %s: %v", domainName, err)
+		}
+		data, err := getProtobufData(buf[:n])
+		if err != nil {
+			continue
+		}
+		packet := &badezimmer.MDNS{}
+		if err := proto.Unmarshal(data, packet); err != nil {
+			continue
+		}
+		response := packet.GetQueryResponse()
+		if response == nil {
+			continue
+		}
+
+		var records []*badezimmer.MDNSRecord
+		for _, record := range append(response.Answers, response.AdditionalRecords...) {
+			if record.Name == domainName || record.GetPtrRecord().GetDomainName() == domainName {
+				records = append(records, record)
+			}
+		}
+		if len(records) > 0 {
+			return records
+		}
+	}
+}
+
+func TestE2EDiscoveryAndGoodbye(t *testing.T) {
+	ip, port := isolatedGroup()
+	conn := groupListener(t, ip, port)
+	a := startDetector(t, ip, port)
+	defer a.Stop()
+	b := startDetector(t, ip, port)
+	bStopped := false
+	defer func() {
+		if !bStopped {
+			b.Stop()
+		}
+	}()
+
+	want := b.info
+	domainName := generateDomainName(want.Type, want.Name)
+
+	// Query the group for detectors and collect b's answer. Both detectors
+	// share a name, so the answer must also carry b's port.
+	query := &badezimmer.MDNS{
+		TransactionId: rand.Uint32(),
+		Data: &badezimmer.MDNS_QueryRequest{QueryRequest: &badezimmer.MDNSQueryRequest{
+			Questions: []*badezimmer.MDNSQuestion{{Name: want.Type, Type: badezimmer.MDNSType_MDNS_PTR}},
+		}},
+	}
+	data, err := prepareProtobufRequest(query)
+	if err != nil {
+		t.Fatalf("failed to prepare query: %v", err)
+	}
+	if _, err := conn.WriteToUDP(data, &net.UDPAddr{IP: net.ParseIP(ip), Port: port}); err != nil {
+		t.Fatalf("failed to send query: %v", err)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	var records []*badezimmer.MDNSRecord
+	for {
+		records = recordsFor(t, conn, domainName, deadline)
+		if srvPort(records) == want.Port {
+			break
+		}
+	}
+
+	var gotAddrs []string
+	txt := map[string]string{}
+	for _, record := range records {
+		if r := record.GetARecord(); r != nil {
+			gotAddrs = append(gotAddrs, r.Address)
+		}
+		if r := record.GetTxtRecord(); r != nil {
+			txt = r.Entries
+		}
+	}
+	wantAddrs := append([]string(nil), want.Addresses...)
+	sort.Strings(gotAddrs)
+	sort.Strings(wantAddrs)
+	if fmt.Sprint(gotAddrs) != fmt.Sprint(wantAddrs) {
+		t.Errorf("Addresses = %v, want %v", gotAddrs, wantAddrs)
+	}
+	if txt["kind"] != want.Kind.String() || txt["category"] != want.Category.String() {
+		t.Errorf("kind, category = %q, %q, want %v, %v", txt["kind"], txt["category"], want.Kind, want.Category)
+	}
+
+	// Stopping b must say goodbye with a zero TTL
+	b.Stop()
+	bStopped = true
+	deadline = time.Now().Add(3 * time.Second)
+	for {
+		records = recordsFor(t, conn, domainName, deadline)
+		if srvPort(records) == want.Port && records[0].Ttl == 0 {
+			break
+		}
+	}
+}
+
+// srvPort returns the port of the SRV record among records, or 0.
+func srvPort(records []*badezimmer.MDNSRecord) int32 {
+	for _, record := range records {
+		if r := record.GetSrvRecord(); r != nil {
+			return r.Port
+		}
+	}
+	return 0
+}
@@ -0,0 +1,55 @@
+package main
+
+import "log"
+
+type ServiceEventType int
+
+const (
+	ServiceRegistered ServiceEventType = iota
+	ServiceUpdated
+	ServiceUnregistered
+)
+
+func (t ServiceEventType) String() string {
+	switch t {
+	case ServiceRegistered:
+		return "registered"
+	case ServiceUpdated:
+		return "updated"
+	case ServiceUnregistered:
+		return "unregistered"
+	default:
+		return "unknown"
+	}
+}
+
+// ServiceEvent describes a change to a locally registered service. Info is a
+// copy, so the callback may keep it.
+type ServiceEvent struct {
+	Type ServiceEventType
+	Info *MDNSServiceInfo
+}
+
+// WithOnServiceEvent calls fn, on its own goroutine, whenever a service is
+// registered, updated or unregistered locally.
+func WithOnServiceEvent(fn func(evt ServiceEvent)) Option {
+	return func(m *BadezimmerMDNS) {
+		m.onServiceEvent = fn
+	}
+}
+
+func (m *BadezimmerMDNS) notifyServiceEvent(eventType ServiceEventType, info *MDNSServiceInfo) {
+	if m.onServiceEvent == nil {
+		return
+	}
+
+	evt := ServiceEvent{Type: eventType, Info: info.Clone()}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Service event callback panicked: %v", r)
+			}
+		}()
+		m.onServiceEvent(evt)
+	}()
+}
@@ -0,0 +1,54 @@
+package main
+
+import "time"
+
+// FinalState is the last leak condition the detector reported before it
+// stopped.
+type FinalState struct {
+	Severity  int       `json:"severity"`
+	Location  string    `json:"location"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// WithFinalStateSink hands the final leak state to sink when the detector
+// stops, once the data generator has exited, e.g. to keep an audit record.
+func WithFinalStateSink(sink func(FinalState)) DetectorOption {
+	return detectorOption(func(w *WaterLeakDetector) {
+		w.finalStateSink = sink
+	})
+}
+
+// Stop stops the device like Device.Stop, then flushes the final state.
+func (w *WaterLeakDetector) Stop() error {
+	_, err := w.StopWithReport()
+	return err
+}
+
+// StopWithReport stops the device like Device.StopWithReport, then flushes
+// the final state. Calling it again returns an empty report.
+func (w *WaterLeakDetector) StopWithReport() (StopReport, error) {
+	report, err := w.Device.StopWithReport()
+
+	w.finalStateOnce.Do(func() {
+		// The generator exits on the cancelled context; waiting for it
+		// makes its last sample the final one
+		if w.generatorDone != nil {
+			<-w.generatorDone
+		}
+		if w.finalStateSink == nil {
+			return
+		}
+
+		w.propsMu.RLock()
+		latest := w.history.latest()
+		w.propsMu.RUnlock()
+
+		w.finalStateSink(FinalState{
+			Severity:  latest.Severity,
+			Location:  latest.Location,
+			Timestamp: latest.Timestamp,
+		})
+	})
+
+	return report, err
+}
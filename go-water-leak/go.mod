@@ -2,4 +2,7 @@ module github.com/talDoFlemis/badezimmer/go-water-leak
 
 go 1.23
 
-require google.golang.org/protobuf v1.36.1
+require (
+	golang.org/x/net v0.35.0
+	google.golang.org/protobuf v1.36.1
+)
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// GossipReannounceInterval is the least time between two re-announces
+	// triggered by new peers, so a crowd joining at once causes no storm
+	GossipReannounceInterval = 10 * time.Second
+
+	// Re-announces are spread over this window, so the responders already
+	// on the network do not all answer a new peer at the same time
+	GossipJitterMax = time.Second
+)
+
+// gossipState throttles the re-announces triggered by new peers.
+type gossipState struct {
+	mu      sync.Mutex
+	pending bool
+	last    time.Time
+}
+
+// WithGossipReannounce re-announces our services of a type when a new peer
+// advertising the same type shows up, so its cache fills without waiting for
+// it to query. Re-announces are jittered and at most one runs per
+// GossipReannounceInterval.
+func WithGossipReannounce(enabled bool) Option {
+	return func(m *BadezimmerMDNS) {
+		m.gossipReannounce = enabled
+	}
+}
+
+// peerDiscovered schedules a re-announce of our services of serviceType,
+// unless one is pending or ran recently.
+func (m *BadezimmerMDNS) peerDiscovered(serviceType string) {
+	if !m.gossipReannounce || m.ServiceCounts()[normalizeServiceType(serviceType)] == 0 {
+		return
+	}
+
+	now := m.clock.Now()
+	m.gossip.mu.Lock()
+	if m.gossip.pending || (!m.gossip.last.IsZero() && now.Sub(m.gossip.last) < GossipReannounceInterval) {
+		m.gossip.mu.Unlock()
+		m.counters.gossipThrottled.Add(1)
+		return
+	}
+	m.gossip.pending = true
+	m.gossip.mu.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer func() {
+			m.gossip.mu.Lock()
+			m.gossip.pending = false
+			m.gossip.last = m.clock.Now()
+			m.gossip.mu.Unlock()
+		}()
+
+		if err := m.sleep(m.ctx, time.Duration(m.randIntn(int(GossipJitterMax)))); err != nil {
+			return
+		}
+
+		m.counters.gossipReannounces.Add(1)
+		for _, info := range m.servicesOfType(serviceType) {
+			if err := m.announceService(m.ctx, info); err != nil && !errors.Is(err, ErrBreakerOpen) {
+				log.Printf("Error re-announcing service %s: %v", info.Name, err)
+			}
+		}
+	}()
+}
+
+// servicesOfType returns copies of the registered services of serviceType.
+func (m *BadezimmerMDNS) servicesOfType(serviceType string) []*MDNSServiceInfo {
+	serviceType = normalizeServiceType(serviceType)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var services []*MDNSServiceInfo
+	for _, info := range m.registeredServices {
+		if normalizeServiceType(info.Type) == serviceType {
+			services = append(services, info.Clone())
+		}
+	}
+	return services
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const defaultHistorySize = 100
+
+type leakSample struct {
+	Severity  int
+	Location  string
+	Timestamp time.Time
+}
+
+// sampleHistory is a fixed-size ring buffer of the latest leak samples. It is
+// not safe for concurrent use; callers hold the detector property lock.
+type sampleHistory struct {
+	samples []leakSample
+	next    int
+	full    bool
+}
+
+func newSampleHistory(size int) *sampleHistory {
+	if size < 1 {
+		size = 1
+	}
+	return &sampleHistory{samples: make([]leakSample, size)}
+}
+
+func (h *sampleHistory) add(sample leakSample) {
+	h.samples[h.next] = sample
+	h.next = (h.next + 1) % len(h.samples)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// ordered returns the buffered samples from oldest to newest.
+func (h *sampleHistory) ordered() []leakSample {
+	if !h.full {
+		return append([]leakSample(nil), h.samples[:h.next]...)
+	}
+	result := make([]leakSample, 0, len(h.samples))
+	result = append(result, h.samples[h.next:]...)
+	return append(result, h.samples[:h.next]...)
+}
+
+// latest returns the most recent sample.
+func (h *sampleHistory) latest() leakSample {
+	return h.samples[(h.next-1+len(h.samples))%len(h.samples)]
+}
+
+// severityStats aggregates the severity of the samples taken at or after
+// since.
+func (h *sampleHistory) severityStats(since time.Time) *badezimmer.SeverityStatsResponse {
+	stats := &badezimmer.SeverityStatsResponse{}
+	sum := 0
+	for _, sample := range h.ordered() {
+		if sample.Timestamp.Before(since) {
+			continue
+		}
+		severity := sample.Severity
+		if stats.Count == 0 || int32(severity) < stats.Min {
+			stats.Min = int32(severity)
+		}
+		if stats.Count == 0 || int32(severity) > stats.Max {
+			stats.Max = int32(severity)
+		}
+		sum += severity
+		stats.Count++
+	}
+	if stats.Count > 0 {
+		stats.Average = float64(sum) / float64(stats.Count)
+	}
+	return stats
+}
+
+func (s leakSample) toProto() *badezimmer.LeakSample {
+	return &badezimmer.LeakSample{
+		Severity:  strconv.Itoa(s.Severity),
+		Location:  s.Location,
+		Timestamp: timestamppb.New(s.Timestamp),
+	}
+}
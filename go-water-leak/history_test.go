@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSampleHistoryWrapsAround(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := newSampleHistory(3)
+	for i := range 5 {
+		h.add(leakSample{Severity: i, Timestamp: start.Add(time.Duration(i) * time.Second)})
+	}
+
+	samples := h.ordered()
+	if len(samples) != 3 {
+		t.Fatalf("got %d samples, want 3", len(samples))
+	}
+	for i, sample := range samples {
+		if sample.Severity != i+2 {
+			t.Errorf("sample %d has severity %d, want %d", i, sample.Severity, i+2)
+		}
+	}
+	if got := h.latest().Severity; got != 4 {
+		t.Errorf("latest severity = %d, want 4", got)
+	}
+}
+
+func TestSampleHistoryBeforeFull(t *testing.T) {
+	h := newSampleHistory(3)
+	h.add(leakSample{Severity: 7})
+
+	samples := h.ordered()
+	if len(samples) != 1 || samples[0].Severity != 7 {
+		t.Errorf("ordered() = %v, want the single sample", samples)
+	}
+}
+
+func TestSeverityStatsOverWindow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := newSampleHistory(10)
+	for i, severity := range []int{9, 2, 4, 6} {
+		h.add(leakSample{Severity: severity, Timestamp: start.Add(time.Duration(i) * 5 * time.Second)})
+	}
+
+	// The first sample is older than the window
+	stats := h.severityStats(start.Add(5 * time.Second))
+	if stats.Count != 3 || stats.Min != 2 || stats.Max != 6 || stats.Average != 4 {
+		t.Errorf("stats = %v, want count 3, min 2, max 6, average 4", stats)
+	}
+
+	if stats := h.severityStats(start.Add(time.Minute)); stats.Count != 0 || stats.Average != 0 {
+		t.Errorf("stats of an empty window = %v", stats)
+	}
+}
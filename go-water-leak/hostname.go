@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+)
+
+// WithHostnameRecords also names our addresses <hostname>.local. and points
+// the SRV records there, for resolvers that key off the host name. Services
+// with an explicit Target keep it.
+func WithHostnameRecords(enabled bool) Option {
+	return func(m *BadezimmerMDNS) {
+		m.hostnameRecords = enabled
+	}
+}
+
+// localHostname returns <hostname>.local. for the short host name, so both
+// "box" and "box.lab.example.com." give "box.local.".
+func localHostname() (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("failed to get hostname: %w", err)
+	}
+
+	label, _, _ := strings.Cut(strings.Trim(hostname, "."), ".")
+	if label == "" {
+		return "", fmt.Errorf("hostname %q has no usable label", hostname)
+	}
+	if len(label) > MaxLabelLength {
+		return "", fmt.Errorf("%w: %q is %d octets", ErrLabelTooLong, label, len(label))
+	}
+	return label + ".local.", nil
+}
+
+// resolveHostnameTarget looks the host name up once for WithHostnameRecords,
+// falling back to plain domain name records when it is not usable.
+func (m *BadezimmerMDNS) resolveHostnameTarget() {
+	if !m.hostnameRecords {
+		return
+	}
+
+	target, err := localHostname()
+	if err != nil {
+		log.Printf("Warning: not adding hostname records: %v", err)
+		return
+	}
+	m.hostnameTarget = target
+}
+
+// withHostnameRecords returns the records of info targeting our host name,
+// along with the A records under the domain name that peers already know.
+func (m *BadezimmerMDNS) withHostnameRecords(info *MDNSServiceInfo) []*badezimmer.MDNSRecord {
+	hostInfo := info.Clone()
+	hostInfo.Target = m.hostnameTarget
+	records := infoToRecords(hostInfo)
+
+	domainName := generateDomainName(info.Type, info.Name)
+	set := NewRecordSet(domainName).WithTTL(info.TTL)
+	for _, ip := range info.Addresses {
+		set.AddA(ip)
+	}
+	return append(records, set.Build()...)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHostnameRecords(t *testing.T) {
+	host, err := localHostname()
+	if err != nil {
+		t.Skipf("host name not usable: %v", err)
+	}
+	if label := strings.TrimSuffix(host, ".local."); label == "" || strings.Contains(label, ".") {
+		t.Fatalf("host name %q is not a single label under .local.", host)
+	}
+
+	m, capture := startTestResponder(t, WithHostnameRecords(true), WithAggressiveAdditional(true))
+	info := testService("Kitchen")
+	domainName := addService(m, info)
+
+	ask(m, info.Type)
+	response := capture.next(t).GetQueryResponse()
+	aNames := make(map[string]bool)
+	var srvTarget string
+	for _, record := range append(response.GetAnswers(), response.GetAdditionalRecords()...) {
+		if a := record.GetARecord(); a != nil && a.Address == "192.0.2.2" {
+			aNames[record.Name] = true
+		}
+		if srv := record.GetSrvRecord(); srv != nil {
+			srvTarget = srv.Target
+		}
+	}
+	if srvTarget != host {
+		t.Errorf("SRV target = %q, want %q", srvTarget, host)
+	}
+	if !aNames[host] || !aNames[domainName] {
+		t.Errorf("A records named %v, want %s and %s", aNames, host, domainName)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"log"
+	"net"
+	"time"
+)
+
+// InterfaceWatchInterval is how often the interfaces are checked for going
+// down or coming back.
+const InterfaceWatchInterval = 5 * time.Second
+
+// WithInterfaceWatch watches the interfaces carrying our addresses. When one
+// goes down the services advertising its addresses send a goodbye, so caches
+// drop them instead of waiting out the TTL, and they are re-announced once it
+// is back.
+func WithInterfaceWatch(enabled bool) Option {
+	return func(m *BadezimmerMDNS) {
+		m.interfaceWatch = enabled
+	}
+}
+
+// interfaceWatcher is the state of watchInterfaces, only used by its
+// goroutine.
+type interfaceWatcher struct {
+	up    map[string]bool
+	addrs map[string][]string
+
+	// silenced are the domain names that sent a goodbye because of the
+	// interface going down, re-announced when it comes back
+	silenced map[string][]string
+}
+
+func (m *BadezimmerMDNS) watchInterfaces() {
+	defer m.wg.Done()
+
+	w := &interfaceWatcher{
+		up:       make(map[string]bool),
+		addrs:    make(map[string][]string),
+		silenced: make(map[string][]string),
+	}
+	// The first check is the baseline, it reports no changes
+	m.checkInterfaces(w)
+
+	ticker := m.clock.NewTicker(InterfaceWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C():
+			m.checkInterfaces(w)
+		}
+	}
+}
+
+// checkInterfaces compares the interfaces with the previous check and acts
+// on those that went down or came back.
+func (m *BadezimmerMDNS) checkInterfaces(w *interfaceWatcher) {
+	ifaces, err := m.addressProvider.Interfaces()
+	if err != nil {
+		m.logLimitedf("interfaces", "Error listing interfaces: %v", err)
+		return
+	}
+
+	present := make(map[string]bool, len(ifaces))
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		present[iface.Name] = true
+
+		// A cable pulled leaves the interface up but not running
+		up := iface.Flags&net.FlagUp != 0 && iface.Flags&net.FlagRunning != 0
+		if up && len(iface.IPs) > 0 {
+			addrs := make([]string, 0, len(iface.IPs))
+			for _, ip := range iface.IPs {
+				addrs = append(addrs, ip.String())
+			}
+			w.addrs[iface.Name] = addrs
+		}
+
+		wasUp, known := w.up[iface.Name]
+		w.up[iface.Name] = up
+		if !known {
+			continue
+		}
+		if wasUp && !up {
+			m.interfaceDown(w, iface.Name)
+		} else if !wasUp && up {
+			m.interfaceUp(w, iface.Name)
+		}
+	}
+
+	// A removed interface is down for good
+	for name, wasUp := range w.up {
+		if present[name] {
+			continue
+		}
+		if wasUp {
+			m.interfaceDown(w, name)
+		}
+		delete(w.up, name)
+	}
+}
+
+// interfaceDown sends a goodbye for the services advertising an address of
+// the interface, and forgets the group joined on it.
+func (m *BadezimmerMDNS) interfaceDown(w *interfaceWatcher, name string) {
+	addrs := make(map[string]bool)
+	for _, addr := range w.addrs[name] {
+		addrs[addr] = true
+	}
+
+	var affected []*MDNSServiceInfo
+	m.mu.Lock()
+	for _, info := range m.registeredServices {
+		for _, addr := range info.Addresses {
+			if addrs[addr] {
+				affected = append(affected, info.Clone())
+				break
+			}
+		}
+	}
+	memberships := m.memberships[:0]
+	for _, membership := range m.memberships {
+		if membership.Interface != name {
+			memberships = append(memberships, membership)
+		}
+	}
+	m.memberships = memberships
+	m.mu.Unlock()
+
+	log.Printf("Interface %s went down, sending goodbye for %d services", name, len(affected))
+
+	for _, info := range affected {
+		if err := m.sendGoodbye(m.ctx, info); err != nil {
+			log.Printf("Error sending goodbye for service %s: %v", info.Name, err)
+		}
+		w.silenced[name] = append(w.silenced[name], generateDomainName(info.Type, info.Name))
+	}
+}
+
+// interfaceUp re-announces the services silenced when the interface went
+// down, and records the group joined on it again.
+func (m *BadezimmerMDNS) interfaceUp(w *interfaceWatcher, name string) {
+	silenced := w.silenced[name]
+	delete(w.silenced, name)
+
+	// The kernel restores the joins of an interface coming back up
+	joined := name == m.interfaceName || (m.bindInterface != nil && m.bindInterface.name == name)
+	for _, iface := range m.joinedInterfaces {
+		joined = joined || iface.name == name
+	}
+	if joined {
+		m.addMemberships(m.groupPort, name)
+		if m.legacyConn != nil {
+			m.addMemberships(StandardMDNSPort, name)
+		}
+	}
+
+	log.Printf("Interface %s is back up, re-announcing %d services", name, len(silenced))
+
+	for _, domainName := range silenced {
+		m.mu.RLock()
+		info, ok := m.registeredServices[domainName]
+		if ok {
+			info = info.Clone()
+		}
+		m.mu.RUnlock()
+		if !ok {
+			continue
+		}
+
+		if err := m.announceService(m.ctx, info); err != nil {
+			log.Printf("Error re-announcing service %s: %v", info.Name, err)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestInterfaceDownSendsGoodbyeAndUpReannounces(t *testing.T) {
+	captureLog(t)
+	clock := newFakeClock()
+	eth0, eth1 := fakeInterface("eth0", 2, "192.0.2.2"), fakeInterface("eth1", 3, "192.0.2.9")
+	provider := newFakeAddressProvider(eth0, eth1)
+	m, capture := startTestResponder(t, WithClock(clock), WithAddressProvider(provider), WithInterfaceWatch(true))
+	t.Cleanup(func() { whileAdvancing(clock, func() { m.Close() }) })
+
+	kitchen := addService(m, testService("Kitchen"))
+	bathroom := testService("Bathroom")
+	bathroom.Addresses = []string{"192.0.2.9"}
+	addService(m, bathroom)
+	// The renovation, breaker and watch tickers
+	clock.waitForPending(t, 3)
+
+	// A pulled cable leaves eth0 up but not running
+	down := eth0
+	down.Flags &^= net.FlagRunning
+	provider.set(down, eth1)
+	clock.Advance(InterfaceWatchInterval)
+	for i := range AnnounceBurstCount {
+		packet := capture.next(t)
+		if got := announcedName(packet); got != kitchen {
+			t.Fatalf("goodbye %d for %q, want only %q", i, got, kitchen)
+		}
+		for _, record := range packet.GetQueryResponse().GetAnswers() {
+			if record.Ttl != 0 {
+				t.Errorf("goodbye record %s has TTL %d", record.Name, record.Ttl)
+			}
+		}
+		if i < AnnounceBurstCount-1 {
+			clock.waitForPending(t, 4)
+			clock.Advance(AnnounceBurstInterval)
+		}
+	}
+	capture.expectNone(t, 50*time.Millisecond)
+
+	provider.set(eth0, eth1)
+	clock.Advance(InterfaceWatchInterval)
+	packet := capture.next(t)
+	if got := announcedName(packet); got != kitchen {
+		t.Errorf("re-announced %q, want %q", got, kitchen)
+	}
+	if ttl := packet.GetQueryResponse().GetAnswers()[0].Ttl; ttl == 0 {
+		t.Error("re-announcement is a goodbye")
+	}
+	capture.expectNone(t, 50*time.Millisecond)
+}
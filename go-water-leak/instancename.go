@@ -0,0 +1,62 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+)
+
+// InstanceNameSuffix selects what is appended to the base instance name, so
+// identical images deployed side by side don't all claim the same name.
+type InstanceNameSuffix string
+
+const (
+	SuffixNone      InstanceNameSuffix = "none"
+	SuffixHostname  InstanceNameSuffix = "hostname"
+	SuffixRandomHex InstanceNameSuffix = "random-hex"
+)
+
+// ParseInstanceNameSuffix accepts the mode names used in configuration.
+func ParseInstanceNameSuffix(s string) (InstanceNameSuffix, error) {
+	switch mode := InstanceNameSuffix(s); mode {
+	case SuffixNone, SuffixHostname, SuffixRandomHex:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown instance name suffix %q", s)
+}
+
+// WithInstanceNameSuffix appends a per-device suffix to the instance name.
+func WithInstanceNameSuffix(mode InstanceNameSuffix) DetectorOption {
+	return detectorOption(func(w *WaterLeakDetector) {
+		w.nameSuffix = mode
+	})
+}
+
+// instanceName returns name with the suffix for mode. Random suffixes come
+// from crypto/rand, since the data generator is seeded identically on every
+// device.
+func instanceName(name string, mode InstanceNameSuffix) string {
+	var suffix string
+	switch mode {
+	case SuffixHostname:
+		hostname, err := os.Hostname()
+		if err != nil {
+			log.Printf("Failed to get hostname for instance name, using %q: %v", name, err)
+			return name
+		}
+		suffix = hostname
+	case SuffixRandomHex:
+		buf := make([]byte, 3)
+		if _, err := rand.Read(buf); err != nil {
+			log.Printf("Failed to generate instance name suffix, using %q: %v", name, err)
+			return name
+		}
+		suffix = hex.EncodeToString(buf)
+	default:
+		return name
+	}
+
+	return fmt.Sprintf("%s - %s", name, suffix)
+}
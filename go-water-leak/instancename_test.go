@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"testing"
+)
+
+const baseInstanceName = "Aliexpress Water Leak Detector"
+
+func TestInstanceNameSuffix(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Skipf("no hostname: %v", err)
+	}
+
+	tests := []struct {
+		mode InstanceNameSuffix
+		want *regexp.Regexp
+	}{
+		{SuffixNone, regexp.MustCompile("^" + regexp.QuoteMeta(baseInstanceName) + "$")},
+		{SuffixHostname, regexp.MustCompile("^" + regexp.QuoteMeta(baseInstanceName+" - "+hostname) + "$")},
+		{SuffixRandomHex, regexp.MustCompile("^" + regexp.QuoteMeta(baseInstanceName) + " - [0-9a-f]{6}$")},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			w := NewWaterLeakDetector(8080, WithInstanceNameSuffix(tt.mode))
+			name := w.info.Name
+			if !tt.want.MatchString(name) {
+				t.Errorf("instance name = %q, want %v", name, tt.want)
+			}
+
+			// The records announce the suffixed name
+			if got := recordsToInfo(infoToRecords(w.info)).Name; got != name {
+				t.Errorf("records name the instance %q, want %q", got, name)
+			}
+		})
+	}
+}
+
+func TestRandomSuffixDiffersPerDevice(t *testing.T) {
+	a := instanceName(baseInstanceName, SuffixRandomHex)
+	b := instanceName(baseInstanceName, SuffixRandomHex)
+	if a == b {
+		t.Errorf("two devices got the same name %q", a)
+	}
+}
+
+func TestParseInstanceNameSuffix(t *testing.T) {
+	for _, s := range []string{"none", "hostname", "random-hex"} {
+		if mode, err := ParseInstanceNameSuffix(s); err != nil || string(mode) != s {
+			t.Errorf("ParseInstanceNameSuffix(%q) = %q, %v", s, mode, err)
+		}
+	}
+	if _, err := ParseInstanceNameSuffix("mac"); err == nil {
+		t.Error("accepted an unknown mode")
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"syscall"
+	"time"
+)
+
+// multicastInterface is a NIC the multicast group was joined on. Bridged
+// interfaces may share an address, so sends pick it by index.
+type multicastInterface struct {
+	name  string
+	index int
+	ip    net.IP
+}
+
+// WithAllInterfaces joins the multicast group on every multicast capable
+// interface with an IPv4 address, and sends each multicast packet out of
+// all of them instead of only the one the routing table picks. It takes
+// precedence over WithInterface.
+func WithAllInterfaces(enabled bool) Option {
+	return func(m *BadezimmerMDNS) {
+		m.allInterfaces = enabled
+	}
+}
+
+// multicastInterfaces lists the interfaces that are up, support multicast
+// and have an IPv4 address, loopback excluded.
+func multicastInterfaces(provider AddressProvider) ([]multicastInterface, error) {
+	ifaces, err := provider.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var found []multicastInterface
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagMulticast == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		if len(iface.IPs) == 0 {
+			continue
+		}
+		found = append(found, multicastInterface{name: iface.Name, index: iface.Index, ip: iface.IPs[0]})
+	}
+	return found, nil
+}
+
+// Membership is a multicast group joined on an interface.
+type Membership struct {
+	Group string `json:"group"`
+	Port  int    `json:"port"`
+	// Interface is empty for the one picked by the routing table
+	Interface string    `json:"interface,omitempty"`
+	JoinedAt  time.Time `json:"joined_at"`
+}
+
+// joinInterfaces joins the group on each interface of ifaces. It returns the
+// interfaces joined, and the first error after trying all of them.
+func joinInterfaces(fd int, multicastIP net.IP, ifaces []multicastInterface) ([]multicastInterface, error) {
+	var joined []multicastInterface
+	var firstErr error
+	for _, iface := range ifaces {
+		mreq := &syscall.IPMreqn{Ifindex: int32(iface.index)}
+		copy(mreq.Multiaddr[:], multicastIP.To4())
+		copy(mreq.Address[:], iface.ip.To4())
+
+		err := syscall.SetsockoptIPMreqn(fd, syscall.IPPROTO_IP, syscall.IP_ADD_MEMBERSHIP, mreq)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("interface %s: %w", iface.name, err)
+			}
+			continue
+		}
+		joined = append(joined, iface)
+	}
+	return joined, firstErr
+}
+
+// addMemberships records the successful joins of the group on port.
+func (m *BadezimmerMDNS) addMemberships(port int, ifaceNames ...string) {
+	now := m.clock.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, name := range ifaceNames {
+		m.memberships = append(m.memberships, Membership{
+			Group:     m.groupIP,
+			Port:      port,
+			Interface: name,
+			JoinedAt:  now,
+		})
+	}
+}
+
+// Memberships returns the multicast groups joined by Start, to tell a failed
+// join apart from a missing one. It is empty once closed.
+func (m *BadezimmerMDNS) Memberships() []Membership {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Membership(nil), m.memberships...)
+}
+
+// writeMulticast sends data to the group addr out of every joined interface,
+// or out of the default one when the group was not joined per interface. It
+// returns how many copies were sent, and an error only when none was.
+func (m *BadezimmerMDNS) writeMulticast(conn *net.UDPConn, data []byte, addr *net.UDPAddr) (int, error) {
+	if len(m.joinedInterfaces) == 0 {
+		if _, err := conn.WriteToUDP(data, addr); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get raw socket: %w", err)
+	}
+
+	// IP_MULTICAST_IF is socket wide, so the option and the write it
+	// applies to must not interleave with another send
+	m.multicastSendMu.Lock()
+	defer m.multicastSendMu.Unlock()
+
+	sent := 0
+	var lastErr error
+	for _, iface := range m.joinedInterfaces {
+		mreq := &syscall.IPMreqn{Ifindex: int32(iface.index)}
+		copy(mreq.Address[:], iface.ip.To4())
+
+		var optErr error
+		err := rawConn.Control(func(fd uintptr) {
+			optErr = syscall.SetsockoptIPMreqn(int(fd), syscall.IPPROTO_IP, syscall.IP_MULTICAST_IF, mreq)
+		})
+		if err == nil {
+			err = optErr
+		}
+		if err == nil {
+			_, err = conn.WriteToUDP(data, addr)
+		}
+		if err != nil {
+			log.Printf("Failed to send packet out of interface %s: %v", iface.name, err)
+			lastErr = err
+			continue
+		}
+		sent++
+	}
+
+	if sent == 0 {
+		return 0, lastErr
+	}
+	return sent, nil
+}
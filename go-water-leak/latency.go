@@ -0,0 +1,67 @@
+package main
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// latencyBounds are the upper bounds of the response latency buckets. They
+// span the response jitter window up to a stalled worker pool.
+var latencyBounds = [...]time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	150 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+// LatencyBucket counts the observations up to UpperBound, and above the
+// previous bucket's. The last bucket has no upper bound and a zero one.
+type LatencyBucket struct {
+	UpperBound time.Duration `json:"upper_bound"`
+	Count      uint64        `json:"count"`
+}
+
+// LatencyHistogram is a snapshot of the time from receiving a query to
+// having sent its response.
+type LatencyHistogram struct {
+	Buckets []LatencyBucket `json:"buckets"`
+	Count   uint64          `json:"count"`
+	Sum     time.Duration   `json:"sum"`
+}
+
+type latencyHistogram struct {
+	// One counter per bound, plus the overflow
+	counts [len(latencyBounds) + 1]atomic.Uint64
+	count  atomic.Uint64
+	sum    atomic.Int64
+}
+
+func (h *latencyHistogram) observe(d time.Duration) {
+	i := 0
+	for i < len(latencyBounds) && d > latencyBounds[i] {
+		i++
+	}
+	h.counts[i].Add(1)
+	h.count.Add(1)
+	h.sum.Add(int64(d))
+}
+
+func (h *latencyHistogram) snapshot() LatencyHistogram {
+	snapshot := LatencyHistogram{
+		Buckets: make([]LatencyBucket, len(h.counts)),
+		Count:   h.count.Load(),
+		Sum:     time.Duration(h.sum.Load()),
+	}
+	for i := range h.counts {
+		snapshot.Buckets[i].Count = h.counts[i].Load()
+		if i < len(latencyBounds) {
+			snapshot.Buckets[i].UpperBound = latencyBounds[i]
+		}
+	}
+	return snapshot
+}
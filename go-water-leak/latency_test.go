@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+)
+
+func TestLatencyHistogramBuckets(t *testing.T) {
+	var h latencyHistogram
+	h.observe(5 * time.Millisecond)  // on the first bound
+	h.observe(30 * time.Millisecond) // up to 50ms
+	h.observe(time.Minute)           // overflow
+
+	snapshot := h.snapshot()
+	if snapshot.Count != 3 || snapshot.Sum != time.Minute+35*time.Millisecond {
+		t.Errorf("count %d and sum %v", snapshot.Count, snapshot.Sum)
+	}
+	want := map[int]uint64{0: 1, 3: 1, len(latencyBounds): 1}
+	for i, bucket := range snapshot.Buckets {
+		if bucket.Count != want[i] {
+			t.Errorf("bucket %d (up to %v) counted %d, want %d", i, bucket.UpperBound, bucket.Count, want[i])
+		}
+	}
+	if last := snapshot.Buckets[len(snapshot.Buckets)-1]; last.UpperBound != 0 {
+		t.Errorf("overflow bucket bounded by %v", last.UpperBound)
+	}
+}
+
+func TestResponseLatencyObserved(t *testing.T) {
+	m, capture := startTestResponder(t)
+	info := testService("Kitchen")
+	addService(m, info)
+
+	before := time.Now()
+	deliver(t, m, &badezimmer.MDNS{
+		TransactionId: 1,
+		Data: &badezimmer.MDNS_QueryRequest{QueryRequest: &badezimmer.MDNSQueryRequest{
+			Questions: []*badezimmer.MDNSQuestion{{Name: info.Type, Type: badezimmer.MDNSType_MDNS_PTR}},
+		}},
+	})
+	capture.next(t)
+	elapsed := time.Since(before)
+
+	var latency LatencyHistogram
+	waitFor(t, func() bool {
+		latency = m.Stats().ResponseLatency
+		return latency.Count == 1
+	})
+	if latency.Sum <= 0 || latency.Sum > elapsed {
+		t.Errorf("observed %v, want within the %v round trip", latency.Sum, elapsed)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import "google.golang.org/protobuf/proto"
+
+// Bounds of the messages accepted from the network, checked before any
+// decoding so hostile input cannot make the decoder allocate much.
+const (
+	// MaxPacketSize bounds an mDNS message, the largest multicast packet
+	// RFC 6762 allows
+	MaxPacketSize = 9000
+
+	// MaxFrameSize bounds a message on the TCP control channel
+	MaxFrameSize = 64 * 1024
+
+	// MaxMessageDepth bounds how deeply messages may be nested. Ours nest
+	// four levels at most, the protobuf default allows 10000.
+	MaxMessageDepth = 16
+)
+
+// unmarshalOptions decodes network input, dropping fields we do not know
+// instead of keeping their bytes around.
+var unmarshalOptions = proto.UnmarshalOptions{
+	DiscardUnknown: true,
+	RecursionLimit: MaxMessageDepth,
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestOversizedInputRejectedBeforeDecoding(t *testing.T) {
+	// The prefix claims a gigabyte, nothing of that size may be allocated
+	packet := binary.BigEndian.AppendUint32(nil, 1<<30)
+	packet = append(packet, 0x0a, 0x00)
+	if _, err := getProtobufData(packet); !errors.Is(err, ErrMalformedFrame) {
+		t.Errorf("getProtobufData() = %v, want ErrMalformedFrame", err)
+	}
+	if allocs := testing.AllocsPerRun(10, func() { getProtobufData(packet) }); allocs > 5 {
+		t.Errorf("rejecting the packet took %v allocations", allocs)
+	}
+
+	server, client := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+	go client.Write(binary.BigEndian.AppendUint32(nil, MaxFrameSize+1))
+	if _, err := readFrame(server); !errors.Is(err, ErrMalformedFrame) {
+		t.Errorf("readFrame() = %v, want ErrMalformedFrame", err)
+	}
+}
+
+// nestedGroups returns depth unknown groups nested in each other.
+func nestedGroups(depth int) []byte {
+	var b []byte
+	for range depth {
+		b = protowire.AppendTag(b, 100, protowire.StartGroupType)
+	}
+	for range depth {
+		b = protowire.AppendTag(b, 100, protowire.EndGroupType)
+	}
+	return b
+}
+
+func TestNestedInputDecodedAndDiscarded(t *testing.T) {
+	// As deep as fits in the largest packet accepted
+	nested := nestedGroups((MaxPacketSize - 4) / 4)
+	framed := binary.BigEndian.AppendUint32(nil, uint32(len(nested)))
+	framed = append(framed, nested...)
+
+	start := time.Now()
+	protoBytes, err := getProtobufData(framed)
+	if err != nil {
+		t.Fatalf("getProtobufData: %v", err)
+	}
+	packet := &badezimmer.MDNS{}
+	if err := unmarshalOptions.Unmarshal(protoBytes, packet); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("decoding took %v", elapsed)
+	}
+	if unknown := packet.ProtoReflect().GetUnknown(); len(unknown) != 0 {
+		t.Errorf("kept %d bytes of unknown fields", len(unknown))
+	}
+}
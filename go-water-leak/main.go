@@ -2,20 +2,18 @@ package main
 
 import (
 	"context"
-	"encoding/binary"
 	"fmt"
-	"io"
 	"log"
 	"math/rand"
 	"net"
 	"os"
 	"os/signal"
 	"strconv"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
-	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -25,187 +23,197 @@ const (
 )
 
 var (
-	possibleSeverities = []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}
-	possibleLocations  = []string{"BATHROOM"}
+	possibleLocations = []string{"BATHROOM"}
 )
 
+// WaterLeakDetector is a Device preset for a water leak sensor that
+// generates random leak samples and serves their history.
 type WaterLeakDetector struct {
-	mdns *BadezimmerMDNS
-	info *MDNSServiceInfo
-	ctx  context.Context
-	cancel context.CancelFunc
+	*Device
+
+	// Guarded by propsMu, like the properties the samples come from
+	history     *sampleHistory
+	historySize int
+
+	// severity is the current leak severity, rendered into the
+	// "severity" property when published. Guarded by propsMu
+	severity int
+
+	// Leak data and network jitter use separate sources, so the leak
+	// sequence is reproducible regardless of network timing
+	dataSeed int64
+	dataRand *rand.Rand
+
+	subscribersMu sync.Mutex
+	subscribers   map[*subscriber]struct{}
+
+	nameSuffix    InstanceNameSuffix
+	addressFilter AddressFilter
+
+	// generatorDone is closed when the data generator exits, after which
+	// the final state goes to finalStateSink
+	generatorDone  chan struct{}
+	finalStateSink func(FinalState)
+	finalStateOnce sync.Once
+}
+
+// DetectorOption configures a WaterLeakDetector. Every DeviceOption is also
+// a DetectorOption.
+type DetectorOption interface {
+	applyDetector(w *WaterLeakDetector)
+}
+
+type detectorOption func(*WaterLeakDetector)
+
+func (opt detectorOption) applyDetector(w *WaterLeakDetector) {
+	opt(w)
+}
+
+// WithDataSeed seeds the generator of the leak severity and location values.
+func WithDataSeed(seed int64) DetectorOption {
+	return detectorOption(func(w *WaterLeakDetector) {
+		w.dataSeed = seed
+	})
+}
+
+// WithAddressFilter changes which local addresses are advertised.
+func WithAddressFilter(filter AddressFilter) DetectorOption {
+	return detectorOption(func(w *WaterLeakDetector) {
+		w.addressFilter = filter
+	})
 }
 
-func NewWaterLeakDetector(port int32) *WaterLeakDetector {
-	ctx, cancel := context.WithCancel(context.Background())
+// WithHistorySize sets how many leak samples are kept for history requests.
+func WithHistorySize(n int) DetectorOption {
+	return detectorOption(func(w *WaterLeakDetector) {
+		w.historySize = n
+	})
+}
+
+func NewWaterLeakDetector(port int32, opts ...DetectorOption) *WaterLeakDetector {
+	w := &WaterLeakDetector{
+		Device:      newDevice(),
+		historySize: defaultHistorySize,
+		dataSeed:    randomSeed,
+		subscribers: make(map[*subscriber]struct{}),
+		nameSuffix:  SuffixNone,
+	}
+	for _, opt := range opts {
+		opt.applyDetector(w)
+	}
 	
-	rand.Seed(randomSeed)
+	w.dataRand = rand.New(rand.NewSource(w.dataSeed))
+	w.severity = w.randomSeverity()
 	
 	info := &MDNSServiceInfo{
-		Name:     "Aliexpress Water Leak Detector",
+		Name:     instanceName("Aliexpress Water Leak Detector", w.nameSuffix),
 		Type:     "_waterleak._tcp.local.",
 		Port:     port,
 		Kind:     badezimmer.DeviceKind_SENSOR_KIND,
 		Category: badezimmer.DeviceCategory_WATER_LEAK,
 		Protocol: badezimmer.TransportProtocol_TCP_PROTOCOL,
 		Properties: map[string]string{
-			"severity": possibleSeverities[rand.Intn(len(possibleSeverities))],
-			"location": possibleLocations[rand.Intn(len(possibleLocations))],
+			"severity": strconv.Itoa(w.severity),
+			"location": possibleLocations[w.dataRand.Intn(len(possibleLocations))],
 		},
-		Addresses: getLocalIPv4Addresses(),
+		Addresses: getLocalIPv4Addresses(w.addressProvider, w.addressFilter),
 		TTL:       DefaultTTL,
 	}
-	
-	return &WaterLeakDetector{
-		mdns:   NewBadezimmerMDNS(),
-		info:   info,
-		ctx:    ctx,
-		cancel: cancel,
-	}
+
+	w.Device.init(info, w.handleRequest)
+
+	w.history = newSampleHistory(w.historySize)
+	w.recordSample()
+
+	return w
 }
 
 func (w *WaterLeakDetector) Start() error {
-	// Start MDNS
-	if err := w.mdns.Start(); err != nil {
-		return fmt.Errorf("failed to start MDNS: %w", err)
-	}
-	
-	// Register service
-	if err := w.mdns.RegisterService(w.info); err != nil {
-		return fmt.Errorf("failed to register service: %w", err)
+	if err := w.Device.Start(); err != nil {
+		return err
 	}
 	
-	// Start TCP server
-	listener, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%d", w.info.Port))
-	if err != nil {
-		return fmt.Errorf("failed to start TCP server: %w", err)
-	}
-	
-	log.Printf("Starting Water Leak Detector service on port %d", w.info.Port)
-	
 	// Start random data generator
+	w.generatorDone = make(chan struct{})
 	go w.generateRandomData()
 	
-	// Accept connections
-	go func() {
-		for {
-			conn, err := listener.Accept()
-			if err != nil {
-				select {
-				case <-w.ctx.Done():
-					return
-				default:
-					log.Printf("Error accepting connection: %v", err)
-					continue
-				}
-			}
-			go w.handleConnection(conn)
-		}
-	}()
-	
-	return nil
-}
-
-func (w *WaterLeakDetector) Stop() error {
-	log.Println("Stopping Water Leak Detector service...")
-	w.cancel()
-	
-	// Unregister service
-	if err := w.mdns.UnregisterService(w.info); err != nil {
-		log.Printf("Error unregistering service: %v", err)
-	}
-	
-	// Close MDNS
-	if err := w.mdns.Close(); err != nil {
-		log.Printf("Error closing MDNS: %v", err)
-	}
-	
-	log.Println("Service stopped")
 	return nil
 }
 
 func (w *WaterLeakDetector) generateRandomData() {
-	ticker := time.NewTicker(time.Duration(intervalBetweenLeaksInSeconds) * time.Second)
+	defer close(w.generatorDone)
+	ticker := w.clock.NewTicker(time.Duration(intervalBetweenLeaksInSeconds) * time.Second)
 	defer ticker.Stop()
 	
 	for {
 		select {
 		case <-w.ctx.Done():
 			return
-		case <-ticker.C:
-			w.info.Properties["severity"] = possibleSeverities[rand.Intn(len(possibleSeverities))]
-			w.info.Properties["location"] = possibleLocations[rand.Intn(len(possibleLocations))]
+		case <-ticker.C():
+			w.propsMu.Lock()
+			// Always in range, so the error can be ignored
+			_ = w.setSeverityLocked(w.randomSeverity())
+			w.info.Properties["location"] = possibleLocations[w.dataRand.Intn(len(possibleLocations))]
+			w.renderSeverityLocked()
+			sample := w.recordSampleLocked()
+			w.propsMu.Unlock()
+			
+			w.publish(sample)
 			
-			if err := w.mdns.UpdateService(w.info); err != nil {
+			if w.mdnsDisabled {
+				continue
+			}
+			if err := w.mdns.UpdateService(w.snapshotInfo()); err != nil {
 				log.Printf("Error updating service: %v", err)
 			}
 		}
 	}
 }
 
-func (w *WaterLeakDetector) handleConnection(conn net.Conn) {
-	defer conn.Close()
-	
-	addr := conn.RemoteAddr()
-	log.Printf("Connected by %s", addr)
-	
-	for {
-		// Read length prefix
-		lengthBuf := make([]byte, 4)
-		if _, err := io.ReadFull(conn, lengthBuf); err != nil {
-			if err != io.EOF {
-				log.Printf("Error reading length prefix: %v", err)
-			}
-			return
-		}
-		
-		messageLength := binary.BigEndian.Uint32(lengthBuf)
-		if messageLength == 0 || messageLength > 64*1024 {
-			log.Printf("Invalid message length: %d", messageLength)
-			return
-		}
-		
-		// Read message
-		messageBuf := make([]byte, messageLength)
-		if _, err := io.ReadFull(conn, messageBuf); err != nil {
-			log.Printf("Error reading message: %v", err)
-			return
-		}
-		
-		// Parse request
-		request := &badezimmer.BadezimmerRequest{}
-		if err := proto.Unmarshal(messageBuf, request); err != nil {
-			log.Printf("Error unmarshaling request: %v", err)
-			return
-		}
-		
-		// Execute request
-		response := w.executeRequest(request)
-		
-		// Send response
-		responseBytes, err := proto.Marshal(response)
-		if err != nil {
-			log.Printf("Error marshaling response: %v", err)
-			return
-		}
-		
-		responseLengthBuf := make([]byte, 4)
-		binary.BigEndian.PutUint32(responseLengthBuf, uint32(len(responseBytes)))
-		
-		if _, err := conn.Write(responseLengthBuf); err != nil {
-			log.Printf("Error writing response length: %v", err)
-			return
-		}
-		
-		if _, err := conn.Write(responseBytes); err != nil {
-			log.Printf("Error writing response: %v", err)
-			return
-		}
+func (w *WaterLeakDetector) randomSeverity() int {
+	return MinSeverity + w.dataRand.Intn(MaxSeverity-MinSeverity+1)
+}
+
+func (w *WaterLeakDetector) recordSample() {
+	w.propsMu.Lock()
+	defer w.propsMu.Unlock()
+	w.recordSampleLocked()
+}
+
+// recordSampleLocked appends the current properties to the history. The
+// caller must hold propsMu.
+func (w *WaterLeakDetector) recordSampleLocked() leakSample {
+	sample := leakSample{
+		Severity:  w.severity,
+		Location:  w.info.Properties["location"],
+		Timestamp: w.clock.Now(),
 	}
+	w.history.add(sample)
+	return sample
 }
 
-func (w *WaterLeakDetector) executeRequest(request *badezimmer.BadezimmerRequest) *badezimmer.BadezimmerResponse {
-	// For now, just return empty response for all requests
+// handleRequest answers the control protocol requests of the detector.
+func (w *WaterLeakDetector) handleRequest(ctx context.Context, conn net.Conn, request *badezimmer.BadezimmerRequest) *badezimmer.BadezimmerResponse {
+	// A subscription takes over the connection until it ends
+	if request.GetSubscribe() != nil {
+		w.streamUpdates(ctx, conn, request.RequestId)
+		return nil
+	}
+	
+	return w.executeRequest(ctx, request)
+}
+
+func (w *WaterLeakDetector) executeRequest(ctx context.Context, request *badezimmer.BadezimmerRequest) *badezimmer.BadezimmerResponse {
+	switch request.GetRequest().(type) {
+	case *badezimmer.BadezimmerRequest_GetHistory:
+		return w.historyResponse(ctx)
+	case *badezimmer.BadezimmerRequest_GetStatus:
+		return w.statusResponse(ctx)
+	case *badezimmer.BadezimmerRequest_GetSeverityStats:
+		return w.severityStatsResponse(ctx, request.GetGetSeverityStats())
+	}
+
+	// For now, just return empty response for all other requests
 	return &badezimmer.BadezimmerResponse{
 		Response: &badezimmer.BadezimmerResponse_Empty{
 			Empty: &emptypb.Empty{},
@@ -213,6 +221,66 @@ func (w *WaterLeakDetector) executeRequest(request *badezimmer.BadezimmerRequest
 	}
 }
 
+func (w *WaterLeakDetector) historyResponse(ctx context.Context) *badezimmer.BadezimmerResponse {
+	w.propsMu.RLock()
+	samples := w.history.ordered()
+	w.propsMu.RUnlock()
+
+	logRequestf(ctx, "Returning %d history samples", len(samples))
+
+	history := &badezimmer.GetHistoryResponse{
+		Samples: make([]*badezimmer.LeakSample, 0, len(samples)),
+	}
+	for _, sample := range samples {
+		history.Samples = append(history.Samples, sample.toProto())
+	}
+
+	return &badezimmer.BadezimmerResponse{
+		Response: &badezimmer.BadezimmerResponse_GetHistoryResponse{
+			GetHistoryResponse: history,
+		},
+	}
+}
+
+// statusResponse returns only the latest sample, so status checks do not pay
+// for the whole history.
+func (w *WaterLeakDetector) statusResponse(ctx context.Context) *badezimmer.BadezimmerResponse {
+	w.propsMu.RLock()
+	latest := w.history.latest()
+	w.propsMu.RUnlock()
+
+	logRequestf(ctx, "Returning the latest sample of severity %d", latest.Severity)
+
+	return &badezimmer.BadezimmerResponse{
+		Response: &badezimmer.BadezimmerResponse_StatusResponse{
+			StatusResponse: latest.toProto(),
+		},
+	}
+}
+
+// severityStatsResponse aggregates the severity over the requested window,
+// which must be covered by the retained history.
+func (w *WaterLeakDetector) severityStatsResponse(ctx context.Context, request *badezimmer.GetSeverityStatsRequest) *badezimmer.BadezimmerResponse {
+	window := time.Duration(request.WindowSeconds) * time.Second
+	retained := time.Duration(w.historySize) * time.Duration(intervalBetweenLeaksInSeconds) * time.Second
+	if window <= 0 || window > retained {
+		return errorResponse(badezimmer.ErrorCode_VALIDATION_ERROR,
+			fmt.Sprintf("window must be between 1s and the %v of retained history, got %v", retained, window))
+	}
+
+	w.propsMu.RLock()
+	stats := w.history.severityStats(w.clock.Now().Add(-window))
+	w.propsMu.RUnlock()
+
+	logRequestf(ctx, "Returning severity stats of %d samples over %v", stats.Count, window)
+
+	return &badezimmer.BadezimmerResponse{
+		Response: &badezimmer.BadezimmerResponse_SeverityStatsResponse{
+			SeverityStatsResponse: stats,
+		},
+	}
+}
+
 func getRandomAvailableTCPPort() (int32, error) {
 	listener, err := net.Listen("tcp", ":0")
 	if err != nil {
@@ -227,6 +295,15 @@ func getRandomAvailableTCPPort() (int32, error) {
 func main() {
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 	
+	// Subcommands turn the binary into a diagnostic tool, without one it
+	// runs the detector
+	if len(os.Args) > 1 && os.Args[1] == "browse" {
+		if err := runBrowse(os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("Browse failed: %v", err)
+		}
+		return
+	}
+	
 	// Check for port from environment variable
 	var port int32
 	if portStr := os.Getenv("PORT"); portStr != "" {
@@ -244,19 +321,48 @@ func main() {
 		}
 	}
 	
-	detector := NewWaterLeakDetector(port)
+	var opts []DetectorOption
+	if disabledStr := os.Getenv("MDNS_DISABLED"); disabledStr != "" {
+		disabled, err := strconv.ParseBool(disabledStr)
+		if err != nil {
+			log.Fatalf("Invalid MDNS_DISABLED environment variable: %v", err)
+		}
+		if disabled {
+			opts = append(opts, WithMDNSDisabled())
+		}
+	}
+	
+	if stateAddr := os.Getenv("STATE_ADDR"); stateAddr != "" {
+		opts = append(opts, WithStateAddr(stateAddr))
+	}
+	
+	if socketPath := os.Getenv("UNIX_SOCKET"); socketPath != "" {
+		opts = append(opts, WithUnixSocket(socketPath))
+	}
+	
+	if token := os.Getenv("AUTH_TOKEN"); token != "" {
+		opts = append(opts, WithAuthToken(token))
+	}
+	
+	if suffixStr := os.Getenv("INSTANCE_NAME_SUFFIX"); suffixStr != "" {
+		suffix, err := ParseInstanceNameSuffix(suffixStr)
+		if err != nil {
+			log.Fatalf("Invalid INSTANCE_NAME_SUFFIX environment variable: %v", err)
+		}
+		opts = append(opts, WithInstanceNameSuffix(suffix))
+	}
+	
+	detector := NewWaterLeakDetector(port, opts...)
 	
 	if err := detector.Start(); err != nil {
 		log.Fatalf("Failed to start detector: %v", err)
 	}
 	
-	// Wait for interrupt signal
-	sigChan := make(chan os.Signal, 1)
+	// Wait for interrupt signal, buffered for the second one forcing exit
+	sigChan := make(chan os.Signal, 2)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 	
-	<-sigChan
-	
-	if err := detector.Stop(); err != nil {
+	if err := awaitShutdown(sigChan, detector.Stop, os.Exit); err != nil {
 		log.Fatalf("Error stopping detector: %v", err)
 	}
 }
@@ -0,0 +1,388 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+	"google.golang.org/protobuf/proto"
+)
+
+// newTestDetector starts a TCP-only detector driven by clock.
+func newTestDetector(t *testing.T, clock *fakeClock, opts ...DetectorOption) *WaterLeakDetector {
+	t.Helper()
+	port, err := getRandomAvailableTCPPort()
+	if err != nil {
+		t.Fatalf("failed to pick a port: %v", err)
+	}
+
+	opts = append([]DetectorOption{WithMDNSDisabled(), WithDeviceClock(clock)}, opts...)
+	w := NewWaterLeakDetector(port, opts...)
+	if err := w.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { whileAdvancing(clock, func() { w.Stop() }) })
+	return w
+}
+
+// dialDevice opens a control connection to d.
+func dialDevice(t *testing.T, d *Device) net.Conn {
+	t.Helper()
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(int(d.info.Port))), time.Second)
+	if err != nil {
+		t.Fatalf("failed to dial device: %v", err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// tick advances clock by a generator interval and waits for the sample it
+// produces.
+func tick(t *testing.T, w *WaterLeakDetector, clock *fakeClock) {
+	t.Helper()
+	clock.Advance(time.Duration(intervalBetweenLeaksInSeconds) * time.Second)
+	waitFor(t, func() bool { return latestSample(w).Timestamp.Equal(clock.Now()) })
+}
+
+// latestSample returns the sample w recorded last.
+func latestSample(w *WaterLeakDetector) leakSample {
+	w.propsMu.RLock()
+	defer w.propsMu.RUnlock()
+	return w.history.latest()
+}
+
+// pipeTo returns an in-memory control connection served by w.
+func pipeTo(t *testing.T, w *WaterLeakDetector) net.Conn {
+	t.Helper()
+	client, server := net.Pipe()
+	go w.handleConnection(server)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// send makes request over conn and returns the response.
+func send(t *testing.T, conn net.Conn, request *badezimmer.BadezimmerRequest) *badezimmer.BadezimmerResponse {
+	t.Helper()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	requestBytes, err := proto.Marshal(request)
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+	frame := binary.BigEndian.AppendUint32(nil, uint32(len(requestBytes)))
+	if _, err := conn.Write(append(frame, requestBytes...)); err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	return receive(t, conn)
+}
+
+// receive reads the next response from conn, which may be compressed.
+func receive(t *testing.T, conn net.Conn) *badezimmer.BadezimmerResponse {
+	t.Helper()
+	responseBuf, err := readFrameCompressed(conn)
+	if err != nil {
+		t.Fatalf("failed to read response: %v", err)
+	}
+	response := &badezimmer.BadezimmerResponse{}
+	if err := proto.Unmarshal(responseBuf, response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	return response
+}
+
+// readTestFrame reads one length-prefixed frame from conn.
+func readTestFrame(conn net.Conn) ([]byte, error) {
+	lengthBuf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, lengthBuf); err != nil {
+		return nil, err
+	}
+	frame := make([]byte, binary.BigEndian.Uint32(lengthBuf))
+	if _, err := io.ReadFull(conn, frame); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+func historyRequest() *badezimmer.BadezimmerRequest {
+	return &badezimmer.BadezimmerRequest{
+		Request: &badezimmer.BadezimmerRequest_GetHistory{GetHistory: &badezimmer.GetHistoryRequest{}},
+	}
+}
+
+// requestHistory asks for the history over conn.
+func requestHistory(t *testing.T, conn net.Conn) []*badezimmer.LeakSample {
+	t.Helper()
+	return send(t, conn, historyRequest()).GetGetHistoryResponse().GetSamples()
+}
+
+// generate records count samples the way the generator does on each tick.
+func generate(w *WaterLeakDetector, count int) {
+	for i := range count {
+		time.Sleep(time.Millisecond)
+		w.propsMu.Lock()
+		w.setSeverityLocked(i % (MaxSeverity + 1))
+		w.renderSeverityLocked()
+		sample := w.recordSampleLocked()
+		w.propsMu.Unlock()
+		w.publish(sample)
+	}
+}
+
+func TestHistoryReturnsGeneratedSamplesInOrder(t *testing.T) {
+	w := NewWaterLeakDetector(0)
+	generate(w, 4)
+
+	samples := requestHistory(t, pipeTo(t, w))
+	if len(samples) != 5 {
+		t.Fatalf("got %d samples, want the initial one and 4 generated", len(samples))
+	}
+	for i := 1; i < len(samples); i++ {
+		if !samples[i].Timestamp.AsTime().After(samples[i-1].Timestamp.AsTime()) {
+			t.Errorf("sample %d is not newer than the previous one", i)
+		}
+	}
+	if got, want := samples[4].Severity, w.info.Properties["severity"]; got != want {
+		t.Errorf("latest severity = %s, want %s", got, want)
+	}
+}
+
+func TestHistoryKeepsTheLatestSamples(t *testing.T) {
+	w := NewWaterLeakDetector(0, WithHistorySize(3))
+	generate(w, 5)
+
+	samples := requestHistory(t, pipeTo(t, w))
+	if len(samples) != 3 {
+		t.Fatalf("got %d samples, want 3", len(samples))
+	}
+	w.propsMu.RLock()
+	want := w.history.ordered()
+	w.propsMu.RUnlock()
+	for i, sample := range samples {
+		if !sample.Timestamp.AsTime().Equal(want[i].Timestamp) {
+			t.Errorf("sample %d at %v, want %v", i, sample.Timestamp.AsTime(), want[i].Timestamp)
+		}
+	}
+}
+
+// dataSequence returns the first samples w generates.
+func dataSequence(w *WaterLeakDetector) []string {
+	sequence := []string{w.info.Properties["severity"] + "@" + w.info.Properties["location"]}
+	for range 5 {
+		sequence = append(sequence, strconv.Itoa(w.randomSeverity()))
+	}
+	return sequence
+}
+
+func TestDataSeedIndependentOfNetworkSeed(t *testing.T) {
+	a := NewWaterLeakDetector(8080, WithDataSeed(42), WithNetworkSeed(1))
+	b := NewWaterLeakDetector(8080, WithDataSeed(42), WithNetworkSeed(2))
+
+	if got, want := dataSequence(b), dataSequence(a); !reflect.DeepEqual(got, want) {
+		t.Errorf("data sequences differ: %v and %v", got, want)
+	}
+
+	differ := false
+	for range 5 {
+		if a.mdns.nextTransactionID() != b.mdns.nextTransactionID() {
+			differ = true
+		}
+	}
+	if !differ {
+		t.Error("different network seeds produced the same transaction ids")
+	}
+}
+
+func TestRequestIDInLogsAndResponse(t *testing.T) {
+	logs := captureLog(t)
+	w := NewWaterLeakDetector(0)
+	conn := pipeTo(t, w)
+
+	request := historyRequest()
+	request.RequestId = "trace-42"
+	response := send(t, conn, request)
+	if response.RequestId != "trace-42" {
+		t.Errorf("response RequestId = %q, want the request's", response.RequestId)
+	}
+	if !logs.contains("[req trace-42] Returning 1 history samples") {
+		t.Errorf("handler log lacks the request id:\n%s", logs)
+	}
+
+	// Requests without an id are traced under a generated one
+	response = send(t, conn, historyRequest())
+	if response.RequestId != "" {
+		t.Errorf("response RequestId = %q, want none", response.RequestId)
+	}
+	if !regexp.MustCompile(`\[req [0-9a-f]{16}\] Received`).MatchString(logs.String()) {
+		t.Errorf("no generated request id in the logs:\n%s", logs)
+	}
+}
+
+func subscriberCount(w *WaterLeakDetector) int {
+	w.subscribersMu.Lock()
+	defer w.subscribersMu.Unlock()
+	return len(w.subscribers)
+}
+
+func TestStalledSubscriberIsDisconnected(t *testing.T) {
+	logs := captureLog(t)
+	w := NewWaterLeakDetector(0, WithMDNSDisabled())
+
+	// Nothing reads the client end, so the first write blocks for good
+	server, client := net.Pipe()
+	defer client.Close()
+	streaming := make(chan struct{})
+	go func() {
+		defer close(streaming)
+		w.streamUpdates(context.Background(), server, "stalled")
+	}()
+	waitFor(t, func() bool { return subscriberCount(w) == 1 })
+
+	// The generator keeps ticking while the subscriber falls behind
+	generate(w, subscriberBufferSize+maxDroppedUpdates+1)
+
+	select {
+	case <-streaming:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stalled subscriber still streaming")
+	}
+	if n := subscriberCount(w); n != 0 {
+		t.Errorf("%d subscribers left", n)
+	}
+	if want := fmt.Sprintf("after %d dropped updates", maxDroppedUpdates); !logs.contains(want) {
+		t.Errorf("disconnect not logged with the dropped count %q", want)
+	}
+}
+
+func TestCurrentSeverityMatchesTXT(t *testing.T) {
+	clock := newFakeClock()
+	w := newTestDetector(t, clock)
+	clock.waitForPending(t, 1)
+
+	for range 5 {
+		tick(t, w, clock)
+
+		severity := w.CurrentSeverity()
+		if txt := w.snapshotInfo().Properties["severity"]; txt != strconv.Itoa(severity) {
+			t.Errorf("CurrentSeverity() = %d, TXT says %s", severity, txt)
+		}
+	}
+}
+
+func TestSeverityOutOfRangeRejected(t *testing.T) {
+	w := NewWaterLeakDetector(8080)
+	before := w.CurrentSeverity()
+
+	for _, severity := range []int{MinSeverity - 1, MaxSeverity + 1} {
+		w.propsMu.Lock()
+		err := w.setSeverityLocked(severity)
+		w.propsMu.Unlock()
+		if !errors.Is(err, ErrInvalidSeverity) {
+			t.Errorf("setting severity %d = %v, want ErrInvalidSeverity", severity, err)
+		}
+	}
+	if got := w.CurrentSeverity(); got != before {
+		t.Errorf("severity changed to %d by rejected values", got)
+	}
+}
+
+func TestSnapshotWhileGenerating(t *testing.T) {
+	clock := newFakeClock()
+	w := newTestDetector(t, clock)
+	clock.waitForPending(t, 1)
+
+	// Run with -race: the generator writes the live properties meanwhile
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			info := w.snapshotInfo()
+			_ = info.Properties["severity"] + info.Properties["location"]
+		}
+	}()
+
+	for range 20 {
+		tick(t, w, clock)
+	}
+	close(stop)
+	<-done
+}
+
+func TestFinalStateFlushedOnStop(t *testing.T) {
+	clock := newFakeClock()
+	var states []FinalState
+	w := newTestDetector(t, clock, WithFinalStateSink(func(state FinalState) {
+		states = append(states, state)
+	}))
+	clock.waitForPending(t, 1)
+	for range 3 {
+		tick(t, w, clock)
+	}
+	last := latestSample(w)
+
+	whileAdvancing(clock, func() { w.Stop() })
+	whileAdvancing(clock, func() { w.Stop() })
+
+	want := []FinalState{{Severity: last.Severity, Location: last.Location, Timestamp: last.Timestamp}}
+	if !reflect.DeepEqual(states, want) {
+		t.Errorf("sink got %+v, want %+v once", states, want)
+	}
+}
+
+func severityStatsRequest(window time.Duration) *badezimmer.BadezimmerRequest {
+	return &badezimmer.BadezimmerRequest{
+		Request: &badezimmer.BadezimmerRequest_GetSeverityStats{GetSeverityStats: &badezimmer.GetSeverityStatsRequest{
+			WindowSeconds: uint32(window / time.Second),
+		}},
+	}
+}
+
+func TestSeverityStatsRequest(t *testing.T) {
+	clock := newFakeClock()
+	w := newTestDetector(t, clock, WithHistorySize(5))
+	clock.waitForPending(t, 1)
+	for range 3 {
+		tick(t, w, clock)
+	}
+
+	w.propsMu.RLock()
+	samples := w.history.ordered()
+	w.propsMu.RUnlock()
+	lowest, highest, sum := samples[0].Severity, samples[0].Severity, 0
+	for _, sample := range samples {
+		lowest, highest = min(lowest, sample.Severity), max(highest, sample.Severity)
+		sum += sample.Severity
+	}
+
+	conn := dialDevice(t, w.Device)
+	interval := time.Duration(intervalBetweenLeaksInSeconds) * time.Second
+	stats := send(t, conn, severityStatsRequest(5*interval)).GetSeverityStatsResponse()
+	if stats.GetCount() != 4 || int(stats.GetMin()) != lowest || int(stats.GetMax()) != highest ||
+		stats.GetAverage() != float64(sum)/4 {
+		t.Errorf("stats = %v of samples %v", stats, samples)
+	}
+
+	// Windows longer than the retained history, or empty, are rejected
+	for _, window := range []time.Duration{6 * interval, 0} {
+		response := send(t, conn, severityStatsRequest(window))
+		if response.GetError().GetCode() != badezimmer.ErrorCode_VALIDATION_ERROR {
+			t.Errorf("window %v answered with %v, want a validation error", window, response)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import "log"
+
+// EnterMaintenance stops answering queries and announcing services, so
+// clients fail over, while keeping the socket and registrations intact.
+// Goodbyes still go out, so removed services do not linger in caches.
+func (m *BadezimmerMDNS) EnterMaintenance() {
+	if m.maintenance.CompareAndSwap(false, true) {
+		log.Printf("Entering maintenance mode, announcements paused")
+	}
+}
+
+// ExitMaintenance resumes normal operation and re-announces every service
+// right away instead of waiting for the next renovation.
+func (m *BadezimmerMDNS) ExitMaintenance() {
+	if !m.maintenance.CompareAndSwap(true, false) {
+		return
+	}
+
+	log.Printf("Leaving maintenance mode, announcing services")
+	m.announceAll()
+}
+
+// InMaintenance reports whether announcements are paused.
+func (m *BadezimmerMDNS) InMaintenance() bool {
+	return m.maintenance.Load()
+}
@@ -3,11 +3,15 @@ package main
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
 	"net"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -19,13 +23,56 @@ import (
 const (
 	MulticastIP          = "224.0.0.251"
 	MulticastPort        = 5369
+	StandardMDNSPort     = 5353
 	DefaultTTL           = 4500
 	ServiceDiscoveryType = "_services._dns-sd._udp.local"
 
+	// Goodbyes are repeated AnnounceBurstCount times, AnnounceBurstInterval
+	// apart, since a single lost datagram leaves stale records in caches.
+	AnnounceBurstCount    = 3
+	AnnounceBurstInterval = 250 * time.Millisecond
+
+	// Upper bound of the multicast response jitter window. Close waits this
+	// long after the last goodbye so the datagrams leave before the socket.
+	ResponseJitterMax = 120 * time.Millisecond
+
+	// Received packets wait in a bounded queue for a pool of handlers, so a
+	// slow handler never stalls the socket reader
+	PacketQueueSize = 128
+	PacketWorkers   = 4
+
+	// DefaultMaxResponseRecords bounds the answer and additional records of
+	// a single response, however many questions the query asks
+	DefaultMaxResponseRecords = 100
+
+	// DefaultMaxRegisteredServices bounds the services renovated every
+	// cycle and answered to every meta-query
+	DefaultMaxRegisteredServices = 256
+
+	// DNS limits from RFC 1035
+	MaxLabelLength = 63
+	MaxNameLength  = 255
+
 	// SO_REUSEPORT for Linux
 	SO_REUSEPORT = 15
 )
 
+var (
+	ErrServiceNotRegistered = errors.New("service not registered")
+	ErrAlreadyRegistered    = errors.New("service already registered, use UpdateService to change it")
+	ErrAlreadyStarted       = errors.New("already started")
+	ErrTooManyServices      = errors.New("too many registered services")
+	ErrLabelTooLong         = errors.New("DNS label exceeds 63 octets")
+	ErrNameTooLong          = errors.New("DNS name exceeds 255 octets")
+
+	// ErrMalformedFrame means the length prefix does not match the data,
+	// ErrMalformedContent that a complete frame is not a valid message
+	ErrMalformedFrame   = errors.New("malformed frame")
+	ErrMalformedContent = errors.New("malformed message content")
+
+	ErrInvalidAnnounceInterval = errors.New("announce interval must be positive")
+)
+
 type MDNSServiceInfo struct {
 	Name       string
 	Type       string
@@ -36,32 +83,611 @@ type MDNSServiceInfo struct {
 	Properties map[string]string
 	Addresses  []string
 	TTL        int32
+
+	// Target is the host name the SRV record points at and the A records
+	// are named for, the domain name when empty
+	Target string
+
+	// Service is the service label of the SRV record, parsed from Type
+	// when empty
+	Service string
+
+	// Ephemeral services are announced once and answered until
+	// unregistered, but not renovated, so caches let them expire
+	Ephemeral bool
+
+	// PublicProperties lists the properties announced and answered to type
+	// and meta-queries, all of them when nil. The full set only goes to
+	// queries naming the instance.
+	PublicProperties []string
+
+	// ExpiresAt is when a discovered service drops out of the cache unless
+	// announced again, zero for our own services
+	ExpiresAt time.Time
+}
+
+// Clone returns a deep copy of info, sharing no map or slice with it, safe
+// to hand out while the original keeps changing.
+func (info *MDNSServiceInfo) Clone() *MDNSServiceInfo {
+	c := *info
+	if info.Addresses != nil {
+		c.Addresses = append([]string(nil), info.Addresses...)
+	}
+	if info.PublicProperties != nil {
+		c.PublicProperties = append([]string(nil), info.PublicProperties...)
+	}
+	if info.Properties != nil {
+		c.Properties = make(map[string]string, len(info.Properties))
+		for k, v := range info.Properties {
+			c.Properties[k] = v
+		}
+	}
+	return &c
+}
+
+// RemainingTTL returns how long a discovered service stays cached after now,
+// zero once expired.
+func (info *MDNSServiceInfo) RemainingTTL(now time.Time) time.Duration {
+	if remaining := info.ExpiresAt.Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// public returns info with only its public properties, info itself when
+// every property is public.
+func (info *MDNSServiceInfo) public() *MDNSServiceInfo {
+	if info.PublicProperties == nil {
+		return info
+	}
+
+	c := *info
+	c.Properties = make(map[string]string, len(info.PublicProperties))
+	for _, key := range info.PublicProperties {
+		if v, ok := info.Properties[key]; ok {
+			c.Properties[key] = v
+		}
+	}
+	return &c
+}
+
+// Validate checks that the service produces a domain name within the DNS
+// limits. The instance name is a single label, even if it contains dots.
+func (info *MDNSServiceInfo) Validate() error {
+	if info.Name == "" {
+		return fmt.Errorf("service instance name is empty")
+	}
+	if strings.Trim(info.Type, ".") == "" {
+		return fmt.Errorf("service type is empty")
+	}
+
+	labels := append([]string{info.Name}, strings.Split(strings.Trim(info.Type, "."), ".")...)
+
+	// Each label is encoded with a length octet, plus the terminating root
+	encodedLength := 1
+	for _, label := range labels {
+		if len(label) > MaxLabelLength {
+			return fmt.Errorf("%w: %q is %d octets", ErrLabelTooLong, label, len(label))
+		}
+		encodedLength += len(label) + 1
+	}
+	if encodedLength > MaxNameLength {
+		return fmt.Errorf("%w: %s is %d octets", ErrNameTooLong, generateDomainName(info.Type, info.Name), encodedLength)
+	}
+
+	return nil
 }
 
 type BadezimmerMDNS struct {
+	mu                 sync.RWMutex
 	conn               *net.UDPConn
+	legacyConn         *net.UDPConn
 	registeredServices map[string]*MDNSServiceInfo // key: domain_name
-	sentPackets        [][]byte
-	sentPacketsMu      sync.Mutex
+	sentTxIDs          sentTransactions
 	ctx                context.Context
 	cancel             context.CancelFunc
 	wg                 sync.WaitGroup
+
+	// answerableTypes restricts which service types queries are answered
+	// for. A nil map answers for every registered type.
+	answerableTypes map[string]bool
+
+	// nextTransactionID generates the id stamped on every outgoing packet
+	nextTransactionID func() uint32
+
+	counters mdnsCounters
+
+	// Multicast group the responder joins and sends to
+	groupIP   string
+	groupPort int
+
+	// rng drives delays and transaction ids; *rand.Rand is not safe for
+	// concurrent use so it is guarded by rngMu
+	rng   *rand.Rand
+	rngMu sync.Mutex
+
+	// aggressiveAdditional includes SRV, A and TXT records as additional
+	// records when answering PTR and meta-queries
+	aggressiveAdditional bool
+
+	// interfaceName selects the NIC to join the group on, the kernel
+	// default is used when empty
+	interfaceName string
+
+	// allInterfaces joins and sends on every multicast interface, those
+	// found by Start are kept in joinedInterfaces
+	allInterfaces    bool
+	joinedInterfaces []multicastInterface
+	multicastSendMu  sync.Mutex
+
+	addressProvider AddressProvider
+
+	// bindAddress is the local address of the multicast socket, parsed by
+	// Start into bindIP and, for a unicast one, its bindInterface
+	bindAddress   string
+	bindIP        net.IP
+	bindInterface *multicastInterface
+
+	breaker sendBreaker
+
+	// setsockopt sets the reuse options on the multicast socket, replaced
+	// by tests to simulate platforms without SO_REUSEPORT
+	setsockopt func(fd, level, opt, value int) error
+
+	// errLog rate limits the errors repeated on every packet
+	errLog logLimiter
+
+	// reverseLookup answers <ip>.in-addr.arpa. queries for our addresses
+	reverseLookup bool
+
+	// instanceID is stamped on outgoing packets; packets carrying the same
+	// id come from sibling processes and are ignored
+	instanceID string
+
+	// cache holds services announced by other responders
+	cache             *serviceCache
+	collisionStrategy CollisionStrategy
+
+	onServiceEvent func(evt ServiceEvent)
+
+	// standardPort also listens on and mirrors packets to StandardMDNSPort
+	standardPort bool
+
+	// maintenance silences queries and announcements, see EnterMaintenance
+	maintenance atomic.Bool
+
+	questions *questionTracker
+
+	// recentAnswers limits how often each service is multicast
+	recentAnswers *answerTracker
+
+	// uptimeProperty adds the seconds since startedAt to the TXT record
+	uptimeProperty bool
+	startedAt      time.Time
+
+	// sequenceNumbers adds the announcement count of each service to its
+	// TXT record
+	sequenceNumbers bool
+	sequences       sequenceTracker
+
+	started atomic.Bool
+	closed  atomic.Bool
+
+	recordInterceptor func([]*badezimmer.MDNSRecord) []*badezimmer.MDNSRecord
+
+	clock Clock
+
+	// unicastAnswers answers queries sent to our address by unicast
+	unicastAnswers bool
+
+	discoveries *discoveryHub
+
+	// dscp marks outgoing packets for QoS, zero leaves the default
+	dscp int
+
+	txtOverflow TXTOverflowPolicy
+
+	recordingPath string
+	recorder      *packetRecorder
+
+	// loopbackDiscovery copies multicast packets to 127.0.0.1
+	loopbackDiscovery bool
+
+	maxResponseRecords    int
+	maxRegisteredServices int
+
+	// hostnameRecords targets <hostname>.local., looked up by Start into
+	// hostnameTarget
+	hostnameRecords bool
+	hostnameTarget  string
+
+	// probes are the names being probed, see handleProbeRecords
+	probes probeTracker
+
+	// memberships are the successful group joins, guarded by mu
+	memberships []Membership
+
+	gossipReannounce bool
+	gossip           gossipState
+
+	// interfaceWatch sends goodbyes for services on interfaces going down
+	interfaceWatch bool
+
+	// responseTarget replaces the multicast group as destination, for tests
+	responseTarget *net.UDPAddr
+
+	// announceInterval replaces the renovation at 75% of the TTL when
+	// announceIntervalSet
+	announceInterval    time.Duration
+	announceIntervalSet bool
+}
+
+type Option func(*BadezimmerMDNS)
+
+// WithAnswerableTypes only answers queries for the given service types,
+// including when enumerating services for the meta-query.
+func WithAnswerableTypes(types []string) Option {
+	return func(m *BadezimmerMDNS) {
+		m.answerableTypes = make(map[string]bool, len(types))
+		for _, t := range types {
+			m.answerableTypes[normalizeServiceType(t)] = true
+		}
+	}
+}
+
+// WithTransactionIDSource replaces the random transaction ids of outgoing
+// packets, e.g. with a counter for reproducible packet bytes in tests. Our
+// own packets are recognized by their id, so peers on the same group must
+// not draw from the same sequence.
+func WithTransactionIDSource(next func() uint32) Option {
+	return func(m *BadezimmerMDNS) {
+		m.nextTransactionID = next
+	}
+}
+
+// WithMulticastGroup overrides the multicast group and port, e.g. to isolate
+// test instances from the real network.
+func WithMulticastGroup(ip string, port int) Option {
+	return func(m *BadezimmerMDNS) {
+		m.groupIP = ip
+		m.groupPort = port
+	}
+}
+
+// WithRandomSeed seeds the source of jitter, delays and transaction ids.
+func WithRandomSeed(seed int64) Option {
+	return func(m *BadezimmerMDNS) {
+		m.rng = rand.New(rand.NewSource(seed))
+	}
+}
+
+// WithAggressiveAdditional controls whether query answers carry the SRV, A
+// and TXT records alongside the PTR. Enabled by default.
+func WithAggressiveAdditional(enabled bool) Option {
+	return func(m *BadezimmerMDNS) {
+		m.aggressiveAdditional = enabled
+	}
+}
+
+// WithInterface joins the multicast group on the named network interface
+// instead of the one picked by the routing table.
+func WithInterface(name string) Option {
+	return func(m *BadezimmerMDNS) {
+		m.interfaceName = name
+	}
+}
+
+// WithReverseLookup answers reverse-lookup queries (<ip>.in-addr.arpa.) for
+// the addresses of registered services with a PTR to the service.
+func WithReverseLookup(enabled bool) Option {
+	return func(m *BadezimmerMDNS) {
+		m.reverseLookup = enabled
+	}
+}
+
+// WithInstanceID marks this responder as one of several processes of the same
+// device. Siblings sharing the id ignore each other's queries and responses,
+// which they would otherwise see through SO_REUSEPORT.
+func WithInstanceID(id string) Option {
+	return func(m *BadezimmerMDNS) {
+		m.instanceID = id
+	}
+}
+
+// WithStandardPort also answers on the conventional mDNS port 5353, next to
+// the configured one. The payload stays protobuf, so this only helps tools
+// that expect the port, not standard mDNS clients.
+func WithStandardPort(enabled bool) Option {
+	return func(m *BadezimmerMDNS) {
+		m.standardPort = enabled
+	}
+}
+
+// WithUptimeProperty advertises an "uptime" TXT entry with the seconds since
+// Start. It is refreshed whenever records are sent anyway, it never causes an
+// announcement by itself.
+func WithUptimeProperty(enabled bool) Option {
+	return func(m *BadezimmerMDNS) {
+		m.uptimeProperty = enabled
+	}
+}
+
+// WithRecordInterceptor lets intercept rewrite or drop the answers and the
+// additional records of every response before it is sent, e.g. to strip a
+// TXT entry or advertise a NAT address. The records passed in are freshly
+// built for each packet, so they may be modified in place. A response left
+// without records is not sent.
+func WithRecordInterceptor(intercept func([]*badezimmer.MDNSRecord) []*badezimmer.MDNSRecord) Option {
+	return func(m *BadezimmerMDNS) {
+		m.recordInterceptor = intercept
+	}
+}
+
+// WithClock replaces the real clock driving timers, TTLs and timestamps.
+func WithClock(clock Clock) Option {
+	return func(m *BadezimmerMDNS) {
+		m.clock = clock
+	}
+}
+
+// WithUnicastAnswers answers queries that were sent directly to our address,
+// rather than to the group, with a unicast packet to the querier.
+func WithUnicastAnswers(enabled bool) Option {
+	return func(m *BadezimmerMDNS) {
+		m.unicastAnswers = enabled
+	}
+}
+
+// WithDSCP marks the outgoing packets with a DSCP value between 0 and
+// MaxDSCP, so network operators can prioritize mDNS traffic. Start fails on
+// values out of range.
+func WithDSCP(dscp int) Option {
+	return func(m *BadezimmerMDNS) {
+		m.dscp = dscp
+	}
+}
+
+// WithLoopbackDiscovery also sends every announcement and multicast
+// response to 127.0.0.1 on the group port, so clients on the same host can
+// discover services where multicast is blocked.
+func WithLoopbackDiscovery(enabled bool) Option {
+	return func(m *BadezimmerMDNS) {
+		m.loopbackDiscovery = enabled
+	}
+}
+
+// WithMaxResponseRecords caps the answer and additional records of a single
+// response at n, DefaultMaxResponseRecords by default. Services that do not
+// fit are left out of the response.
+func WithMaxResponseRecords(n int) Option {
+	return func(m *BadezimmerMDNS) {
+		if n > 0 {
+			m.maxResponseRecords = n
+		}
+	}
+}
+
+// WithAnnounceInterval re-announces the services every interval instead of
+// at 75% of their TTL. Start fails unless the interval is positive.
+func WithAnnounceInterval(interval time.Duration) Option {
+	return func(m *BadezimmerMDNS) {
+		m.announceInterval = interval
+		m.announceIntervalSet = true
+	}
+}
+
+// WithMaxRegisteredServices caps the services registered at once at n,
+// DefaultMaxRegisteredServices by default. RegisterService fails with
+// ErrTooManyServices beyond it.
+func WithMaxRegisteredServices(n int) Option {
+	return func(m *BadezimmerMDNS) {
+		if n > 0 {
+			m.maxRegisteredServices = n
+		}
+	}
 }
 
-func NewBadezimmerMDNS() *BadezimmerMDNS {
+// WithResponseTarget sends the packets meant for the multicast group to
+// addr instead, without the standard port and loopback copies. It is meant
+// for tests, which can capture exactly what would have been multicast with a
+// plain UDP socket.
+func WithResponseTarget(addr *net.UDPAddr) Option {
+	return func(m *BadezimmerMDNS) {
+		m.responseTarget = addr
+	}
+}
+
+func NewBadezimmerMDNS(opts ...Option) *BadezimmerMDNS {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &BadezimmerMDNS{
+	m := &BadezimmerMDNS{
 		registeredServices: make(map[string]*MDNSServiceInfo),
-		sentPackets:        make([][]byte, 0, 50),
 		ctx:                ctx,
 		cancel:             cancel,
+		groupIP:            MulticastIP,
+		groupPort:          MulticastPort,
+
+		aggressiveAdditional: true,
+		cache:                newServiceCache(),
+		questions:            newQuestionTracker(),
+		discoveries:          newDiscoveryHub(),
+		maxResponseRecords:   DefaultMaxResponseRecords,
+		collisionStrategy:    numericSuffixStrategy,
+		clock:                realClock{},
+
+		maxRegisteredServices: DefaultMaxRegisteredServices,
+		recentAnswers:         newAnswerTracker(),
+		addressProvider:       SystemAddressProvider{},
+		bindAddress:           "0.0.0.0",
+		setsockopt:            syscall.SetsockoptInt,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.rng == nil {
+		m.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
 	}
+	if m.nextTransactionID == nil {
+		m.nextTransactionID = m.randUint32
+	}
+	return m
+}
+
+func (m *BadezimmerMDNS) randIntn(n int) int {
+	m.rngMu.Lock()
+	defer m.rngMu.Unlock()
+	return m.rng.Intn(n)
+}
+
+func (m *BadezimmerMDNS) randUint32() uint32 {
+	m.rngMu.Lock()
+	defer m.rngMu.Unlock()
+	return m.rng.Uint32()
 }
 
+// Start binds the multicast socket and starts answering queries. It returns
+// ErrAlreadyStarted when called again after succeeding.
 func (m *BadezimmerMDNS) Start() error {
+	if !m.started.CompareAndSwap(false, true) {
+		return ErrAlreadyStarted
+	}
+
+	if err := m.start(); err != nil {
+		m.started.Store(false)
+		return err
+	}
+	return nil
+}
+
+func (m *BadezimmerMDNS) start() error {
+	multicastIP := net.ParseIP(m.groupIP).To4()
+	if multicastIP == nil || !multicastIP.IsMulticast() {
+		return fmt.Errorf("invalid IPv4 multicast group %q", m.groupIP)
+	}
+
+	if err := validateDSCP(m.dscp); err != nil {
+		return err
+	}
+
+	if err := m.resolveBindAddress(); err != nil {
+		return err
+	}
+
+	if m.announceIntervalSet {
+		if m.announceInterval <= 0 {
+			return fmt.Errorf("%w: %v", ErrInvalidAnnounceInterval, m.announceInterval)
+		}
+		if m.announceInterval > DefaultTTL*time.Second {
+			log.Printf("Warning: announce interval %v exceeds the TTL of %ds, records expire between announces", m.announceInterval, DefaultTTL)
+		}
+	}
+
+	m.resolveHostnameTarget()
+
+	if m.allInterfaces {
+		ifaces, err := multicastInterfaces(m.addressProvider)
+		if err != nil {
+			return err
+		}
+		if len(ifaces) == 0 {
+			log.Printf("Warning: no multicast interface found, using the default one")
+		}
+		m.joinedInterfaces = ifaces
+	}
+
+	if m.recordingPath != "" {
+		recorder, err := openRecorder(m.recordingPath)
+		if err != nil {
+			return err
+		}
+		m.recorder = recorder
+	}
+
+	conn, err := m.listenMulticast(multicastIP, m.groupPort)
+	if err != nil {
+		m.recorder.close()
+		m.recorder = nil
+		return err
+	}
+	m.startedAt = m.clock.Now()
+
+	m.mu.Lock()
+	m.conn = conn
+	m.mu.Unlock()
+
+	// Only packets sent from the new socket count as our own
+	m.sentTxIDs.reset()
+
+	var legacyConn *net.UDPConn
+	if m.standardPort && m.groupPort != StandardMDNSPort {
+		legacyConn, err = m.listenMulticast(multicastIP, StandardMDNSPort)
+		if err != nil {
+			conn.Close()
+			m.recorder.close()
+			m.recorder = nil
+			return fmt.Errorf("failed to listen on standard mDNS port: %w", err)
+		}
+
+		m.mu.Lock()
+		m.legacyConn = legacyConn
+		m.mu.Unlock()
+	}
+
+	log.Printf("BadezimmerMDNS listening on %s:%d", m.groupIP, m.groupPort)
+
+	// Start packet handlers, fed by the receive loops
+	packets := make(chan receivedPacket, PacketQueueSize)
+	for i := 0; i < PacketWorkers; i++ {
+		m.wg.Add(1)
+		go m.packetWorker(packets)
+	}
+
+	// Start receive loops and wait until they are reading, so queries are
+	// handled before any service gets announced
+	var receivers sync.WaitGroup
+	ready := make(chan struct{})
+	receivers.Add(1)
+	m.wg.Add(1)
+	go m.recvLoop(conn, ready, packets, &receivers)
+	<-ready
+
+	if legacyConn != nil {
+		log.Printf("BadezimmerMDNS also listening on %s:%d", m.groupIP, StandardMDNSPort)
+
+		ready := make(chan struct{})
+		receivers.Add(1)
+		m.wg.Add(1)
+		go m.recvLoop(legacyConn, ready, packets, &receivers)
+		<-ready
+	}
+
+	// The workers stop once every receive loop is done feeding them
+	go func() {
+		receivers.Wait()
+		close(packets)
+	}()
+
+	// Start renovation loop
+	m.wg.Add(1)
+	go m.renovateLoop()
+
+	// Start circuit breaker probing
+	m.wg.Add(1)
+	go m.breakerLoop()
+
+	if m.interfaceWatch {
+		m.wg.Add(1)
+		go m.watchInterfaces()
+	}
+
+	return nil
+}
+
+// listenMulticast binds a UDP socket to port and joins the multicast group on
+// it, on the configured interface if any.
+func (m *BadezimmerMDNS) listenMulticast(multicastIP net.IP, port int) (*net.UDPConn, error) {
 	addr := &net.UDPAddr{
-		IP:   net.ParseIP("0.0.0.0"),
-		Port: MulticastPort,
+		IP:   m.bindIP,
+		Port: port,
 	}
 
 	// Create a listening connection with SO_REUSEPORT to allow multiple processes
@@ -69,13 +695,10 @@ func (m *BadezimmerMDNS) Start() error {
 		Control: func(network, address string, c syscall.RawConn) error {
 			var opErr error
 			err := c.Control(func(fd uintptr) {
-				// Enable SO_REUSEADDR
-				opErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_REUSEADDR, 1)
-				if opErr != nil {
-					return
+				opErr = setReuseOptions(int(fd), m.setsockopt)
+				if opErr == nil && m.dscp != 0 {
+					opErr = setDSCP(int(fd), m.dscp)
 				}
-				// Enable SO_REUSEPORT to allow multiple processes to bind to the same port
-				opErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, SO_REUSEPORT, 1)
 			})
 			if err != nil {
 				return err
@@ -86,115 +709,354 @@ func (m *BadezimmerMDNS) Start() error {
 
 	packetConn, err := lc.ListenPacket(context.Background(), "udp4", addr.String())
 	if err != nil {
-		return fmt.Errorf("failed to listen UDP: %w", err)
+		return nil, fmt.Errorf("failed to listen UDP: %w", err)
 	}
 
 	conn, ok := packetConn.(*net.UDPConn)
 	if !ok {
-		return fmt.Errorf("failed to cast to UDPConn")
+		packetConn.Close()
+		return nil, fmt.Errorf("failed to cast to UDPConn")
 	}
 
-	m.conn = conn
+	err = conn.SetReadBuffer(65536)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to set read buffer: %w", err)
+	}
+
+	// Join multicast group using IP_ADD_MEMBERSHIP
+	mreq := &syscall.IPMreq{
+		Multiaddr: [4]byte{multicastIP[0], multicastIP[1], multicastIP[2], multicastIP[3]},
+		Interface: [4]byte{0, 0, 0, 0}, // Use default interface
+	}
 
-	// Join multicast group
-	multicastIP := net.ParseIP(MulticastIP)
+	if m.interfaceName != "" && len(m.joinedInterfaces) == 0 {
+		ifaceIP, err := interfaceIPv4(m.interfaceName)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		copy(mreq.Interface[:], ifaceIP)
+		log.Printf("Joining multicast group on interface %s (%s)", m.interfaceName, ifaceIP)
+	} else if m.bindInterface != nil && len(m.joinedInterfaces) == 0 {
+		// The group is joined where the bind address lives
+		copy(mreq.Interface[:], m.bindInterface.ip)
+		log.Printf("Joining multicast group on interface %s (%s)", m.bindInterface.name, m.bindInterface.ip)
+	}
 
-	err = conn.SetReadBuffer(65536)
+	// Set the multicast options on the socket itself. conn.File() would
+	// switch it to blocking mode, leaving reads deaf to deadlines and Close.
+	rawConn, err := conn.SyscallConn()
 	if err != nil {
-		return fmt.Errorf("failed to set read buffer: %w", err)
+		conn.Close()
+		return nil, fmt.Errorf("failed to get raw socket: %w", err)
 	}
 
-	// Get socket file descriptor and set multicast options
-	file, err := conn.File()
+	var pktinfoErr, bindErr, joinErr error
+	var joined []multicastInterface
+	err = rawConn.Control(func(fd uintptr) {
+		// Report the destination of each packet, to tell unicast queries apart
+		pktinfoErr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_PKTINFO, 1)
+		bindErr = m.setBindOptions(int(fd))
+		if len(m.joinedInterfaces) > 0 {
+			joined, joinErr = joinInterfaces(int(fd), multicastIP, m.joinedInterfaces)
+		} else {
+			joinErr = syscall.SetsockoptIPMreq(int(fd), syscall.IPPROTO_IP, syscall.IP_ADD_MEMBERSHIP, mreq)
+		}
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get socket file: %w", err)
+		conn.Close()
+		return nil, fmt.Errorf("failed to set socket options: %w", err)
 	}
-	defer file.Close()
 
-	fd := int(file.Fd())
+	if bindErr != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to set bind address options: %w", bindErr)
+	}
 
-	// Join multicast group using IP_ADD_MEMBERSHIP
-	mreq := &syscall.IPMreq{
-		Multiaddr: [4]byte{multicastIP[0], multicastIP[1], multicastIP[2], multicastIP[3]},
-		Interface: [4]byte{0, 0, 0, 0}, // Use default interface
+	if pktinfoErr != nil {
+		log.Printf("Warning: failed to enable IP_PKTINFO, unicast queries are answered by multicast: %v", pktinfoErr)
 	}
 
-	if err := syscall.SetsockoptIPMreq(fd, syscall.IPPROTO_IP, syscall.IP_ADD_MEMBERSHIP, mreq); err != nil {
-		log.Printf("Warning: failed to join multicast group: %v", err)
-	} else {
-		log.Printf("Joined multicast group %s", MulticastIP)
+	if len(m.joinedInterfaces) > 0 {
+		names := make([]string, 0, len(joined))
+		for _, iface := range joined {
+			names = append(names, iface.name)
+		}
+		m.addMemberships(port, names...)
+	} else if joinErr == nil && m.interfaceName == "" && m.bindInterface != nil {
+		m.addMemberships(port, m.bindInterface.name)
+	} else if joinErr == nil {
+		m.addMemberships(port, m.interfaceName)
 	}
 
-	log.Printf("BadezimmerMDNS listening on %s:%d", MulticastIP, MulticastPort)
+	if joinErr != nil {
+		log.Printf("Warning: failed to join multicast group: %v", joinErr)
+	} else if len(m.joinedInterfaces) > 0 {
+		log.Printf("Joined multicast group %s on port %d on %d interfaces", m.groupIP, port, len(m.joinedInterfaces))
+	} else {
+		log.Printf("Joined multicast group %s on port %d", m.groupIP, port)
+	}
 
-	// Start receive loop
-	m.wg.Add(1)
-	go m.recvLoop()
+	return conn, nil
+}
 
-	// Start renovation loop
-	m.wg.Add(1)
-	go m.renovateLoop()
+// LocalAddr returns the local address the multicast socket is bound to, or
+// nil if Start has not been called.
+func (m *BadezimmerMDNS) LocalAddr() net.Addr {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 
-	return nil
+	if m.conn == nil {
+		return nil
+	}
+	return m.conn.LocalAddr()
 }
 
+// Close sends goodbyes for the registered services and releases the socket.
+// Calling it again is a no-op.
 func (m *BadezimmerMDNS) Close() error {
-	m.cancel()
-
-	// Send goodbye packets for all registered services
-	for _, info := range m.registeredServices {
-		goodbyeInfo := *info
-		goodbyeInfo.TTL = 0
-		m.broadcastService(&goodbyeInfo)
-		log.Printf("Sent goodbye packet for service: %s", info.Name)
+	if !m.closed.CompareAndSwap(false, true) {
+		return nil
 	}
 
+	m.cancel()
+
 	if m.conn != nil {
+		// Send goodbye packets for all registered services. They are the
+		// last packets we send, so they go out although m.ctx is cancelled.
+		services := m.snapshotServices()
+		for _, info := range services {
+			if err := m.sendGoodbye(context.Background(), info); err != nil {
+				log.Printf("Error sending goodbye for service %s: %v", info.Name, err)
+				continue
+			}
+			log.Printf("Sent goodbye packet for service: %s", info.Name)
+		}
+
+		// Give the goodbyes time to hit the wire before closing the socket
+		if len(services) > 0 {
+			<-m.clock.After(ResponseJitterMax)
+		}
+
 		m.conn.Close()
 	}
+	if m.legacyConn != nil {
+		m.legacyConn.Close()
+	}
 
 	m.wg.Wait()
+
+	m.mu.Lock()
+	m.memberships = nil
+	m.mu.Unlock()
+
+	if err := m.recorder.close(); err != nil {
+		log.Printf("Error closing recording: %v", err)
+	}
 	return nil
 }
 
+// abortStart closes a responder whose Start succeeded but whose owner then
+// failed to start, and readies it for another Start.
+func (m *BadezimmerMDNS) abortStart() {
+	m.Close()
+
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.recorder = nil
+	m.closed.Store(false)
+	m.started.Store(false)
+}
+
 func (m *BadezimmerMDNS) RegisterService(info *MDNSServiceInfo) error {
 	log.Printf("Registering service: %s on port %d", info.Name, info.Port)
 
+	if err := info.Validate(); err != nil {
+		return fmt.Errorf("invalid service %s: %w", info.Name, err)
+	}
+	if err := m.checkTXTEntries(info.Properties); err != nil {
+		return fmt.Errorf("invalid service %s: %w", info.Name, err)
+	}
+
+	// The caller keeps its info, later changes go through UpdateService.
+	// It only learns the name picked by probing.
+	info.Type = normalizeServiceType(info.Type)
+	callerInfo := info
+	info = info.Clone()
+
+	// Fail fast, before the delay and probing
+	if m.isRegistered(generateDomainName(info.Type, info.Name)) {
+		return fmt.Errorf("%w: %s", ErrAlreadyRegistered, generateDomainName(info.Type, info.Name))
+	}
+	if err := m.checkServiceLimit(); err != nil {
+		return err
+	}
+
 	// Add random delay
-	time.Sleep(time.Duration(150+rand.Intn(100)) * time.Millisecond)
+	if err := m.sleep(m.ctx, time.Duration(150+m.randIntn(100))*time.Millisecond); err != nil {
+		return err
+	}
 
+	// Make sure nobody else announces the name before claiming it
+	if err := m.probe(info); err != nil {
+		return err
+	}
+	callerInfo.Name = info.Name
+
+	// The service only becomes visible to handleQuery once it is complete.
+	// A concurrent registration may have claimed the name meanwhile.
 	domainName := generateDomainName(info.Type, info.Name)
+	m.mu.Lock()
+	if _, exists := m.registeredServices[domainName]; exists {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrAlreadyRegistered, domainName)
+	}
+	if len(m.registeredServices) >= m.maxRegisteredServices {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: limit is %d", ErrTooManyServices, m.maxRegisteredServices)
+	}
 	m.registeredServices[domainName] = info
+	m.mu.Unlock()
+
+	m.notifyServiceEvent(ServiceRegistered, info)
 
 	// Broadcast service
-	return m.broadcastService(info)
+	return m.announceService(m.ctx, info)
 }
 
 func (m *BadezimmerMDNS) UnregisterService(info *MDNSServiceInfo) error {
 	log.Printf("Unregistering service: %s", info.Name)
 
 	domainName := generateDomainName(info.Type, info.Name)
+	m.mu.Lock()
 	delete(m.registeredServices, domainName)
+	m.mu.Unlock()
+
+	m.notifyServiceEvent(ServiceUnregistered, info)
 
 	// Send goodbye packet
-	goodbyeInfo := *info
-	goodbyeInfo.TTL = 0
-	return m.broadcastService(&goodbyeInfo)
+	return m.sendGoodbye(m.ctx, info)
+}
+
+func (m *BadezimmerMDNS) isRegistered(domainName string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, exists := m.registeredServices[domainName]
+	return exists
+}
+
+// checkServiceLimit fails when no further service may be registered.
+func (m *BadezimmerMDNS) checkServiceLimit() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.registeredServices) >= m.maxRegisteredServices {
+		return fmt.Errorf("%w: limit is %d", ErrTooManyServices, m.maxRegisteredServices)
+	}
+	return nil
+}
+
+// ServiceCounts returns the number of registered instances per service type.
+func (m *BadezimmerMDNS) ServiceCounts() map[string]int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, info := range m.registeredServices {
+		counts[normalizeServiceType(info.Type)]++
+	}
+	return counts
+}
+
+// UpdateService replaces the info of a registered service and re-announces
+// it. Services not registered are refused with ErrServiceNotRegistered.
+func (m *BadezimmerMDNS) UpdateService(info *MDNSServiceInfo) error {
+	log.Printf("Updating service: %s", info.Name)
+
+	if err := info.Validate(); err != nil {
+		return fmt.Errorf("invalid service %s: %w", info.Name, err)
+	}
+	if err := m.checkTXTEntries(info.Properties); err != nil {
+		return fmt.Errorf("invalid service %s: %w", info.Name, err)
+	}
+
+	// The caller keeps its info, normalized or not
+	info = info.Clone()
+	info.Type = normalizeServiceType(info.Type)
+
+	domainName := generateDomainName(info.Type, info.Name)
+	m.mu.Lock()
+	if _, ok := m.registeredServices[domainName]; !ok {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrServiceNotRegistered, domainName)
+	}
+	m.registeredServices[domainName] = info
+	m.mu.Unlock()
+
+	m.notifyServiceEvent(ServiceUpdated, info)
+
+	return m.announceService(m.ctx, info)
 }
 
-func (m *BadezimmerMDNS) UpdateService(info *MDNSServiceInfo) error {
-	log.Printf("Updating service: %s", info.Name)
+// UpdateAddresses replaces the A-record addresses of a registered service and
+// re-announces it, leaving the rest of the service info untouched.
+func (m *BadezimmerMDNS) UpdateAddresses(domainName string, addrs []string) error {
+	m.mu.Lock()
+	info, ok := m.registeredServices[domainName]
+	if !ok {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrServiceNotRegistered, domainName)
+	}
+	info.Addresses = append([]string(nil), addrs...)
+	info = info.Clone()
+	m.mu.Unlock()
+
+	log.Printf("Updated addresses of service %s: %v", info.Name, addrs)
+
+	return m.announceService(m.ctx, info)
+}
+
+// FlushService makes resolvers drop their cached records of a registered
+// service, e.g. after announcing a wrong configuration, by sending a goodbye
+// and then announcing the current records. Unlike UnregisterService the
+// service stays registered and answered.
+func (m *BadezimmerMDNS) FlushService(domainName string) error {
+	m.mu.RLock()
+	info, ok := m.registeredServices[domainName]
+	if ok {
+		info = info.Clone()
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrServiceNotRegistered, domainName)
+	}
+
+	log.Printf("Flushing cached records of service: %s", info.Name)
 
-	domainName := generateDomainName(info.Type, info.Name)
-	m.registeredServices[domainName] = info
+	if err := m.sendGoodbye(m.ctx, info); err != nil {
+		return fmt.Errorf("failed to send goodbye: %w", err)
+	}
+	return m.announceService(m.ctx, info)
+}
 
-	return m.broadcastService(info)
+type receivedPacket struct {
+	data []byte
+	addr *net.UDPAddr
+	// unicast is set for packets sent to one of our addresses rather than
+	// to the multicast group
+	unicast bool
+	// receivedAt is when the packet was read, zero for replayed packets
+	receivedAt time.Time
 }
 
-func (m *BadezimmerMDNS) recvLoop() {
+func (m *BadezimmerMDNS) recvLoop(conn *net.UDPConn, ready chan<- struct{}, packets chan<- receivedPacket, receivers *sync.WaitGroup) {
 	defer m.wg.Done()
+	defer receivers.Done()
 
 	buffer := make([]byte, 65536)
+	oob := make([]byte, 128)
+	close(ready)
 	for {
 		select {
 		case <-m.ctx.Done():
@@ -202,45 +1064,78 @@ func (m *BadezimmerMDNS) recvLoop() {
 		default:
 		}
 
-		m.conn.SetReadDeadline(time.Now().Add(1 * time.Second))
-		n, addr, err := m.conn.ReadFromUDP(buffer)
+		conn.SetReadDeadline(time.Now().Add(1 * time.Second))
+		n, oobn, _, addr, err := conn.ReadMsgUDP(buffer, oob)
 		if err != nil {
 			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
 				continue
 			}
-			log.Printf("Error reading from UDP: %v", err)
+			// The socket is closed by Close() on shutdown
+			if errors.Is(err, net.ErrClosed) || m.ctx.Err() != nil {
+				return
+			}
+			m.logLimitedf("recv", "Error reading from UDP: %v", err)
 			continue
 		}
 
 		data := buffer[:n]
+		m.counters.packetsReceived.Add(1)
 
-		// Skip our own packets
-		if m.isSentPacket(data) {
-			continue
+		log.Printf("Received packet from %s (%d bytes)", addr.IP, n)
+
+		// The read buffer is reused, so the handler gets its own copy
+		packet := receivedPacket{data: append([]byte(nil), data...), addr: addr, receivedAt: m.clock.Now()}
+		if dst := packetDestination(oob[:oobn]); dst != nil && !dst.IsMulticast() {
+			packet.unicast = true
+		}
+		select {
+		case packets <- packet:
+		default:
+			m.counters.packetsDropped.Add(1)
+			m.logLimitedf("queue-full", "Packet queue full, dropping packet from %s", addr.IP)
 		}
+	}
+}
 
-		log.Printf("Received packet from %s (%d bytes)", addr.IP, n)
-		m.handlePacket(data, addr)
+func (m *BadezimmerMDNS) packetWorker(packets <-chan receivedPacket) {
+	defer m.wg.Done()
+
+	for packet := range packets {
+		m.handlePacket(packet)
 	}
 }
 
 func (m *BadezimmerMDNS) renovateLoop() {
 	defer m.wg.Done()
 
-	// Renovate at 75% of TTL
+	// Renovate at 75% of TTL, unless a fixed cadence is configured
 	renovationInterval := time.Duration(float64(DefaultTTL)*0.75) * time.Second
-	ticker := time.NewTicker(renovationInterval)
+	if m.announceIntervalSet {
+		renovationInterval = m.announceInterval
+	}
+	ticker := m.clock.NewTicker(renovationInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-m.ctx.Done():
 			return
-		case <-ticker.C:
+		case <-ticker.C():
+			if m.maintenance.Load() {
+				continue
+			}
+
+			m.counters.renovationCycles.Add(1)
 			count := 0
-			for _, info := range m.registeredServices {
-				if err := m.broadcastService(info); err != nil {
-					log.Printf("Error renovating service %s: %v", info.Name, err)
+			for _, info := range m.snapshotServices() {
+				if info.Ephemeral {
+					continue
+				}
+				if err := m.broadcastService(m.ctx, info); err != nil {
+					if errors.Is(err, ErrBreakerOpen) {
+						continue
+					}
+					m.logLimitedf("renovate:"+info.Name, "Error renovating service %s: %v", info.Name, err)
 				} else {
 					count++
 				}
@@ -252,67 +1147,266 @@ func (m *BadezimmerMDNS) renovateLoop() {
 	}
 }
 
-func (m *BadezimmerMDNS) handlePacket(data []byte, addr *net.UDPAddr) {
+// snapshotServices returns copies of the registered services, taken under a
+// brief lock so that broadcasting them, which may sleep, does not hold up
+// registrations.
+func (m *BadezimmerMDNS) snapshotServices() []*MDNSServiceInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	services := make([]*MDNSServiceInfo, 0, len(m.registeredServices))
+	for _, info := range m.registeredServices {
+		services = append(services, info.Clone())
+	}
+	return services
+}
+
+// announceAll broadcasts every registered service once.
+func (m *BadezimmerMDNS) announceAll() {
+	for _, info := range m.snapshotServices() {
+		if err := m.announceService(m.ctx, info); err != nil && !errors.Is(err, ErrBreakerOpen) {
+			m.logLimitedf("announce:"+info.Name, "Error announcing service %s: %v", info.Name, err)
+		}
+	}
+}
+
+func (m *BadezimmerMDNS) handlePacket(received receivedPacket) {
+	data, addr := received.data, received.addr
+	record := func() {
+		m.recorder.record(RecordedPacket{Unicast: received.unicast, Addr: addr, Data: data})
+	}
+
 	protoBytes, err := getProtobufData(data)
 	if err != nil {
-		log.Printf("Error extracting protobuf data: %v", err)
+		record()
+		m.counters.framingErrors.Add(1)
+		m.logLimitedf("framing:"+addr.IP.String(), "Dropping packet from %s with bad framing: %v", addr.IP, err)
 		return
 	}
 
 	packet := &badezimmer.MDNS{}
-	if err := proto.Unmarshal(protoBytes, packet); err != nil {
-		log.Printf("Error unmarshaling MDNS packet: %v", err)
+	if err := unmarshalOptions.Unmarshal(protoBytes, packet); err != nil {
+		record()
+		m.counters.contentErrors.Add(1)
+		m.logLimitedf("malformed:"+addr.IP.String(), "Dropping packet from %s: %v", addr.IP, fmt.Errorf("%w: %v", ErrMalformedContent, err))
+		return
+	}
+
+	// Skip our own packets, looped back by the group
+	if m.sentTxIDs.contains(packet.TransactionId) {
+		m.counters.selfSuppressed.Add(1)
+		return
+	}
+	record()
+
+	if m.instanceID != "" && packet.InstanceId == m.instanceID {
+		m.counters.siblingSuppressed.Add(1)
 		return
 	}
 
 	switch packet.GetData().(type) {
 	case *badezimmer.MDNS_QueryRequest:
-		m.handleQuery(packet.GetQueryRequest(), addr)
+		m.handleQuery(packet.GetQueryRequest(), addr, received.unicast, received.receivedAt)
 	case *badezimmer.MDNS_QueryResponse:
-		// We could handle responses here if needed
-		log.Printf("Received query response from %s", addr.IP)
+		m.handleResponse(packet.GetQueryResponse(), addr)
 	}
 }
 
-func (m *BadezimmerMDNS) handleQuery(query *badezimmer.MDNSQueryRequest, addr *net.UDPAddr) {
+// handleQuery answers query on the multicast group, or directly to addr when
+// the query was sent to us by unicast and unicast answers are enabled.
+func (m *BadezimmerMDNS) handleQuery(query *badezimmer.MDNSQueryRequest, addr *net.UDPAddr, unicast bool, receivedAt time.Time) {
+	if len(query.GetQuestions()) == 0 {
+		m.counters.emptyQueries.Add(1)
+		log.Printf("Ignoring query without questions from %s", addr.IP)
+		return
+	}
+
+	m.handleProbeRecords(query.GetAuthorityRecords())
+
+	if m.maintenance.Load() {
+		return
+	}
+
 	var ptrRecords []*badezimmer.MDNSRecord
 	var additionalRecords []*badezimmer.MDNSRecord
 
+	// Queries with many questions must not blow up the response; what
+	// does not fit is left out
+	limited := 0
+	fits := func(n int) bool {
+		if len(ptrRecords)+len(additionalRecords)+n > m.maxResponseRecords {
+			limited++
+			return false
+		}
+		return true
+	}
+
+	now := m.clock.Now()
+
+	var dest *net.UDPAddr
+	if unicast && m.unicastAnswers {
+		dest = addr
+	}
+
+	// A service matched by several questions is only answered once
+	answered := make(map[string]bool)
+	var answeredNames []string
+	answer := func(domainName string, info *MDNSServiceInfo, direct bool) {
+		// Names being probed are not ours yet
+		if answered[domainName] || m.probes.get(domainName) != nil {
+			return
+		}
+		answered[domainName] = true
+
+		// Records multicast a moment ago are still fresh in every cache.
+		// The full properties are other records than the public ones.
+		sentKey := domainName
+		if direct {
+			sentKey += "|full"
+		}
+		if dest == nil && m.recentAnswers.recentlySent(sentKey, now) {
+			m.counters.rateLimitedAnswers.Add(1)
+			return
+		}
+
+		// Only a query naming the instance gets the private properties
+		if !direct {
+			info = info.public()
+		}
+		records := m.serviceRecords(info)
+		if len(records) == 0 {
+			return
+		}
+
+		// SRV, A and TXT let the querier resolve without a follow-up query,
+		// and are what a query naming the instance asks for
+		if !m.aggressiveAdditional && !direct {
+			records = records[:1]
+		}
+		if !fits(len(records)) {
+			return
+		}
+		ptrRecords = append(ptrRecords, records[0])
+		additionalRecords = append(additionalRecords, records[1:]...)
+		answeredNames = append(answeredNames, sentKey)
+	}
+
+	m.mu.RLock()
 	for _, question := range query.Questions {
-		if question.Name == ServiceDiscoveryType {
+		key := fmt.Sprintf("%s|%s|%s", addr.String(), strings.ToLower(question.Name), question.Type)
+		if !m.questions.shouldAnswer(key, now) {
+			m.counters.duplicateQuestions.Add(1)
+			continue
+		}
+
+		questionType := normalizeServiceType(question.Name)
+		if ip := parseReverseLookupName(question.Name); ip != nil {
+			if m.reverseLookup {
+				for _, record := range m.reverseLookupRecordsLocked(questionType, ip) {
+					if fits(1) {
+						ptrRecords = append(ptrRecords, record)
+					}
+				}
+			}
+		} else if questionType == normalizeServiceType(ServiceDiscoveryType) {
 			// Respond with all our registered services
-			for _, info := range m.registeredServices {
-				records := infoToRecords(info)
-				if len(records) > 0 {
-					ptrRecords = append(ptrRecords, records[0])
-					additionalRecords = append(additionalRecords, records[1:]...)
+			for domainName, info := range m.registeredServices {
+				if m.isAnswerable(info.Type) {
+					answer(domainName, info, false)
 				}
 			}
-		} else {
+		} else if info, ok := m.registeredServices[questionType]; ok {
+			// The question names one of our instances
+			if m.isAnswerable(info.Type) {
+				answer(questionType, info, true)
+			}
+		} else if m.isAnswerable(questionType) {
 			// Check if this question matches any of our registered services
-			for _, info := range m.registeredServices {
-				if info.Type == question.Name {
-					records := infoToRecords(info)
-					if len(records) > 0 {
-						ptrRecords = append(ptrRecords, records[0])
-						additionalRecords = append(additionalRecords, records[1:]...)
-					}
+			for domainName, info := range m.registeredServices {
+				if normalizeServiceType(info.Type) == questionType {
+					answer(domainName, info, false)
 				}
 			}
 		}
 	}
+	m.mu.RUnlock()
+
+	if limited > 0 {
+		log.Printf("Response to %s reached %d records, left out %d answers", addr.IP, m.maxResponseRecords, limited)
+	}
 
 	if len(ptrRecords) > 0 {
 		response := &badezimmer.MDNSQueryResponse{
 			Answers:           ptrRecords,
 			AdditionalRecords: additionalRecords,
 		}
-		m.sendResponse(response)
+		if err := m.sendResponseTo(response, dest); err != nil {
+			m.logLimitedf("answer", "Error answering query from %s: %v", addr.IP, err)
+			return
+		}
+		m.counters.responsesSent.Add(1)
+		if dest == nil {
+			for _, sentKey := range answeredNames {
+				m.recentAnswers.markSent(sentKey, m.clock.Now())
+			}
+		}
+		if !receivedAt.IsZero() {
+			m.counters.responseLatency.observe(m.clock.Now().Sub(receivedAt))
+		}
+	}
+}
+
+// reverseLookupRecordsLocked returns a PTR from name to every registered
+// service advertising ip. The caller must hold m.mu.
+func (m *BadezimmerMDNS) reverseLookupRecordsLocked(name string, ip net.IP) []*badezimmer.MDNSRecord {
+	var records []*badezimmer.MDNSRecord
+	for domainName, info := range m.registeredServices {
+		if !m.isAnswerable(info.Type) {
+			continue
+		}
+		for _, address := range info.Addresses {
+			if ip.Equal(net.ParseIP(address)) {
+				records = append(records, &badezimmer.MDNSRecord{
+					Name:       name,
+					Ttl:        info.TTL,
+					CacheFlush: false,
+					Record: &badezimmer.MDNSRecord_PtrRecord{
+						PtrRecord: &badezimmer.MDNSPointerRecord{
+							Name:       name,
+							DomainName: domainName,
+						},
+					},
+				})
+				break
+			}
+		}
+	}
+	return records
+}
+
+func (m *BadezimmerMDNS) isAnswerable(serviceType string) bool {
+	return m.answerableTypes == nil || m.answerableTypes[normalizeServiceType(serviceType)]
+}
+
+// announceService broadcasts info, unless the responder is in maintenance.
+// Updates made during maintenance go out with ExitMaintenance.
+func (m *BadezimmerMDNS) announceService(ctx context.Context, info *MDNSServiceInfo) error {
+	if m.maintenance.Load() {
+		return nil
 	}
+	return m.broadcastService(ctx, info)
 }
 
-func (m *BadezimmerMDNS) broadcastService(info *MDNSServiceInfo) error {
-	records := infoToRecords(info)
+func (m *BadezimmerMDNS) broadcastService(ctx context.Context, info *MDNSServiceInfo) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if m.sequenceNumbers {
+		m.sequences.next(generateDomainName(info.Type, info.Name))
+	}
+
+	records := m.serviceRecords(info.public())
 	if len(records) == 0 {
 		return fmt.Errorf("no records generated for service")
 	}
@@ -322,66 +1416,177 @@ func (m *BadezimmerMDNS) broadcastService(info *MDNSServiceInfo) error {
 		AdditionalRecords: records[1:],
 	}
 
-	return m.sendResponse(response)
+	if err := m.sendResponse(response); err != nil {
+		return err
+	}
+	m.recentAnswers.markSent(generateDomainName(info.Type, info.Name), m.clock.Now())
+	return nil
+}
+
+// sendGoodbye broadcasts the service records with a zero TTL so resolvers
+// drop them, repeating the packet AnnounceBurstCount times. The burst stops
+// early with the context error when ctx is cancelled.
+func (m *BadezimmerMDNS) sendGoodbye(ctx context.Context, info *MDNSServiceInfo) error {
+	goodbyeInfo := *info
+	goodbyeInfo.TTL = 0
+
+	var lastErr error
+	for i := 0; i < AnnounceBurstCount; i++ {
+		if i > 0 {
+			if err := m.sleep(ctx, AnnounceBurstInterval); err != nil {
+				return err
+			}
+		}
+		if err := m.broadcastService(ctx, &goodbyeInfo); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			lastErr = err
+			continue
+		}
+		m.counters.goodbyesSent.Add(1)
+	}
+	return lastErr
+}
+
+// sleep waits for d, or returns the context error as soon as ctx is
+// cancelled.
+func (m *BadezimmerMDNS) sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-m.clock.After(d):
+		return nil
+	}
 }
 
 func (m *BadezimmerMDNS) sendResponse(response *badezimmer.MDNSQueryResponse) error {
+	return m.sendResponseTo(response, nil)
+}
+
+// sendResponseTo sends response to dest, or to the multicast group when dest
+// is nil.
+func (m *BadezimmerMDNS) sendResponseTo(response *badezimmer.MDNSQueryResponse, dest *net.UDPAddr) error {
+	if m.recordInterceptor != nil {
+		response = &badezimmer.MDNSQueryResponse{
+			Answers:           m.recordInterceptor(response.Answers),
+			AdditionalRecords: m.recordInterceptor(response.AdditionalRecords),
+		}
+		// Nothing left to say once every record was vetoed
+		if len(response.Answers) == 0 && len(response.AdditionalRecords) == 0 {
+			return nil
+		}
+	}
+
 	packet := &badezimmer.MDNS{
-		TransactionId: rand.Uint32(),
+		TransactionId: m.nextTransactionID(),
+		InstanceId:    m.instanceID,
 		Timestamp:     timestamppb.Now(),
 		Data:          &badezimmer.MDNS_QueryResponse{QueryResponse: response},
 	}
 
-	return m.sendPacket(packet)
+	return m.sendPacketTo(packet, dest)
 }
 
 func (m *BadezimmerMDNS) sendPacket(packet *badezimmer.MDNS) error {
+	return m.sendPacketTo(packet, nil)
+}
+
+// sendPacketTo sends packet to dest, or to the multicast group, and mirrored
+// to the standard port if enabled, when dest is nil.
+func (m *BadezimmerMDNS) sendPacketTo(packet *badezimmer.MDNS, dest *net.UDPAddr) error {
 	rawBytes, err := prepareProtobufRequest(packet)
 	if err != nil {
 		return fmt.Errorf("failed to prepare packet: %w", err)
 	}
 
-	m.addSentPacket(rawBytes)
+	if !m.breaker.allow() {
+		return ErrBreakerOpen
+	}
 
-	addr := &net.UDPAddr{
-		IP:   net.ParseIP(MulticastIP),
-		Port: MulticastPort,
+	// Everything meant for the group goes to the test target instead
+	if dest == nil && m.responseTarget != nil {
+		dest = m.responseTarget
+	}
+
+	// Copies sent out of several interfaces share the id, so a single
+	// entry recognizes each of them coming back
+	m.sentTxIDs.add(packet.TransactionId)
+
+	addr := dest
+	if addr == nil {
+		addr = &net.UDPAddr{
+			IP:   net.ParseIP(m.groupIP),
+			Port: m.groupPort,
+		}
+		_, err = m.writeMulticast(m.conn, rawBytes, addr)
+	} else {
+		_, err = m.conn.WriteToUDP(rawBytes, addr)
+	}
+
+	// Same-host clients still hear us when multicast is blocked, so the
+	// copy goes out whether the multicast send failed or not
+	if dest == nil && m.loopbackDiscovery {
+		loopbackAddr := &net.UDPAddr{
+			IP:   net.IPv4(127, 0, 0, 1),
+			Port: m.groupPort,
+		}
+		if _, err := m.conn.WriteToUDP(rawBytes, loopbackAddr); err != nil {
+			m.logLimitedf("loopback", "Failed to copy packet to %s: %v", loopbackAddr, err)
+		}
 	}
 
-	_, err = m.conn.WriteToUDP(rawBytes, addr)
 	if err != nil {
+		m.counters.sendFailures.Add(1)
+		if m.breaker.failure() {
+			log.Printf("Send circuit breaker opened after %d consecutive failures, pausing sends", BreakerFailureThreshold)
+		}
 		return fmt.Errorf("failed to send packet: %w", err)
 	}
+	m.counters.packetsSent.Add(1)
+	m.recorder.record(RecordedPacket{Sent: true, Unicast: dest != nil, Addr: addr, Data: rawBytes})
 
-	log.Printf("Sent packet (%d bytes, txid: %d)", len(rawBytes), packet.TransactionId)
-	return nil
-}
-
-func (m *BadezimmerMDNS) addSentPacket(data []byte) {
-	m.sentPacketsMu.Lock()
-	defer m.sentPacketsMu.Unlock()
+	// The probe that closes the breaker is part of a full re-announce
+	if m.breaker.success() {
+		log.Printf("Send circuit breaker closed, sends resumed")
+	}
 
-	// Keep last 50 packets
-	if len(m.sentPackets) >= 50 {
-		m.sentPackets = m.sentPackets[1:]
+	// Mirror to the conventional port for clients that only listen there
+	if dest == nil && m.legacyConn != nil {
+		legacyAddr := &net.UDPAddr{
+			IP:   net.ParseIP(m.groupIP),
+			Port: StandardMDNSPort,
+		}
+		if _, err := m.writeMulticast(m.conn, rawBytes, legacyAddr); err != nil {
+			m.logLimitedf("mirror", "Failed to mirror packet to port %d: %v", StandardMDNSPort, err)
+		}
 	}
-	m.sentPackets = append(m.sentPackets, data)
+
+	log.Printf("Sent packet to %s (%d bytes, txid: %d)", addr, len(rawBytes), packet.TransactionId)
+	return nil
 }
 
-func (m *BadezimmerMDNS) isSentPacket(data []byte) bool {
-	m.sentPacketsMu.Lock()
-	defer m.sentPacketsMu.Unlock()
+// packetDestination returns the destination address from an IP_PKTINFO
+// control message, or nil if there is none.
+func packetDestination(oob []byte) net.IP {
+	msgs, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return nil
+	}
 
-	for _, sent := range m.sentPackets {
-		if bytesEqual(sent, data) {
-			return true
+	for _, msg := range msgs {
+		// struct in_pktinfo: ifindex, local address, header destination
+		if msg.Header.Level == syscall.IPPROTO_IP && msg.Header.Type == syscall.IP_PKTINFO && len(msg.Data) >= syscall.SizeofInet4Pktinfo {
+			return net.IPv4(msg.Data[8], msg.Data[9], msg.Data[10], msg.Data[11])
 		}
 	}
-	return false
+	return nil
 }
 
+// prepareProtobufRequest frames msg with its length. Maps are marshalled in
+// a stable order, so the same packet always has the same bytes.
 func prepareProtobufRequest(msg proto.Message) ([]byte, error) {
-	serialized, err := proto.Marshal(msg)
+	serialized, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
 	if err != nil {
 		return nil, err
 	}
@@ -395,100 +1600,190 @@ func prepareProtobufRequest(msg proto.Message) ([]byte, error) {
 
 func getProtobufData(data []byte) ([]byte, error) {
 	if len(data) < 4 {
-		return nil, fmt.Errorf("data too short for length prefix")
+		return nil, fmt.Errorf("%w: %d bytes is too short for length prefix", ErrMalformedFrame, len(data))
 	}
 
 	messageLength := binary.BigEndian.Uint32(data[:4])
+	if messageLength > MaxPacketSize {
+		return nil, fmt.Errorf("%w: %d bytes exceeds the limit of %d", ErrMalformedFrame, messageLength, MaxPacketSize)
+	}
 	if uint32(len(data)-4) < messageLength {
-		return nil, fmt.Errorf("data shorter than expected message length")
+		return nil, fmt.Errorf("%w: prefix announces %d bytes, got %d", ErrMalformedFrame, messageLength, len(data)-4)
 	}
 
 	return data[4 : 4+messageLength], nil
 }
 
+// parseReverseLookupName returns the IPv4 address encoded in a reverse
+// lookup name like "4.3.2.1.in-addr.arpa.", or nil if name is not one.
+func parseReverseLookupName(name string) net.IP {
+	const suffix = ".in-addr.arpa"
+	name = strings.TrimRight(strings.ToLower(name), ".")
+	if !strings.HasSuffix(name, suffix) {
+		return nil
+	}
+
+	octets := strings.Split(strings.TrimSuffix(name, suffix), ".")
+	if len(octets) != 4 {
+		return nil
+	}
+	for i, j := 0, len(octets)-1; i < j; i, j = i+1, j-1 {
+		octets[i], octets[j] = octets[j], octets[i]
+	}
+
+	return net.ParseIP(strings.Join(octets, ".")).To4()
+}
+
 func generateDomainName(serviceType, instanceName string) string {
-	return fmt.Sprintf("%s.%s", instanceName, serviceType)
+	return fmt.Sprintf("%s.%s", instanceName, normalizeServiceType(serviceType))
 }
 
-func infoToRecords(info *MDNSServiceInfo) []*badezimmer.MDNSRecord {
+// normalizeServiceType makes a service type end in exactly one dot, so
+// "_waterleak._tcp.local" and "_waterleak._tcp.local." are the same type.
+func normalizeServiceType(serviceType string) string {
+	return strings.TrimRight(serviceType, ".") + "."
+}
+
+// serviceRecords returns the records we announce for info, with the entries
+// computed at send time added to the TXT record and over-length entries
+// truncated.
+func (m *BadezimmerMDNS) serviceRecords(info *MDNSServiceInfo) []*badezimmer.MDNSRecord {
 	var records []*badezimmer.MDNSRecord
-	domainName := generateDomainName(info.Type, info.Name)
+	if m.hostnameTarget != "" && info.Target == "" {
+		records = m.withHostnameRecords(info)
+	} else {
+		records = infoToRecords(info)
+	}
+	for _, record := range records {
+		txt := record.GetTxtRecord()
+		if txt == nil {
+			continue
+		}
 
-	// 1. PTR Record
-	ptrRecord := &badezimmer.MDNSRecord{
-		Name:       info.Type,
-		Ttl:        info.TTL,
-		CacheFlush: false,
-		Record: &badezimmer.MDNSRecord_PtrRecord{
-			PtrRecord: &badezimmer.MDNSPointerRecord{
-				Name:       info.Type,
-				DomainName: domainName,
-			},
-		},
+		if m.uptimeProperty && !m.startedAt.IsZero() {
+			txt.Entries["uptime"] = strconv.FormatInt(int64(m.clock.Now().Sub(m.startedAt)/time.Second), 10)
+		}
+		if m.sequenceNumbers {
+			txt.Entries[SequenceProperty] = m.sequenceEntry(info)
+		}
+		truncateTXTEntries(txt.Entries)
 	}
-	records = append(records, ptrRecord)
+	return records
+}
+
+func infoToRecords(info *MDNSServiceInfo) []*badezimmer.MDNSRecord {
+	serviceType := normalizeServiceType(info.Type)
+	domainName := generateDomainName(serviceType, info.Name)
 
-	// 2. A Records
+	set := NewRecordSet(domainName).WithTTL(info.TTL).WithTarget(info.Target).WithService(info.Service).AddPTR(serviceType)
 	for _, ip := range info.Addresses {
-		aRecord := &badezimmer.MDNSRecord{
-			Name:       domainName,
-			Ttl:        info.TTL,
-			CacheFlush: true,
-			Record: &badezimmer.MDNSRecord_ARecord{
-				ARecord: &badezimmer.MDNSARecord{
-					Name:    domainName,
-					Address: ip,
-				},
-			},
-		}
-		records = append(records, aRecord)
-	}
-
-	// 3. SRV Record
-	service := "_http"
-	if parts := splitServiceType(info.Type); len(parts) > 0 {
-		service = parts[0]
-	}
-
-	srvRecord := &badezimmer.MDNSRecord{
-		Name:       domainName,
-		Ttl:        info.TTL,
-		CacheFlush: true,
-		Record: &badezimmer.MDNSRecord_SrvRecord{
-			SrvRecord: &badezimmer.MDNSSRVRecord{
-				Name:     info.Name,
-				Protocol: info.Protocol,
-				Service:  service,
-				Instance: info.Name,
-				Port:     info.Port,
-				Target:   domainName,
-			},
-		},
+		set.AddA(ip)
 	}
-	records = append(records, srvRecord)
+	set.AddSRV(info.Port, info.Protocol)
 
-	// 4. TXT Record
 	txtEntries := make(map[string]string)
 	txtEntries["kind"] = info.Kind.String()
 	txtEntries["category"] = info.Category.String()
 	for k, v := range info.Properties {
 		txtEntries[k] = v
 	}
+	set.AddTXT(txtEntries)
 
-	txtRecord := &badezimmer.MDNSRecord{
-		Name:       domainName,
-		Ttl:        info.TTL,
-		CacheFlush: true,
-		Record: &badezimmer.MDNSRecord_TxtRecord{
-			TxtRecord: &badezimmer.MDNSTextRecord{
-				Name:    domainName,
-				Entries: txtEntries,
-			},
-		},
+	return set.Build()
+}
+
+// recordsToInfo rebuilds the service announced by the first PTR record from
+// a set of records in any order, correlating SRV, A and TXT records through
+// the PTR domain name. It returns nil when there is no PTR record.
+func recordsToInfo(records []*badezimmer.MDNSRecord) *MDNSServiceInfo {
+	var ptr *badezimmer.MDNSRecord
+	for _, record := range records {
+		if record.GetPtrRecord() != nil {
+			ptr = record
+			break
+		}
+	}
+	if ptr == nil {
+		return nil
 	}
-	records = append(records, txtRecord)
 
-	return records
+	serviceType := normalizeServiceType(ptr.GetPtrRecord().Name)
+	domainName := ptr.GetPtrRecord().DomainName
+	info := &MDNSServiceInfo{
+		Name:       strings.TrimSuffix(strings.TrimSuffix(domainName, serviceType), "."),
+		Type:       serviceType,
+		Properties: make(map[string]string),
+		TTL:        ptr.Ttl,
+	}
+
+	// A records may be named after the SRV target rather than the domain
+	target := domainName
+	for _, record := range records {
+		if srv := record.GetSrvRecord(); srv != nil && record.Name == domainName {
+			info.Port = srv.Port
+			info.Protocol = srv.Protocol
+			if srv.Target != "" && srv.Target != domainName {
+				target = srv.Target
+				info.Target = srv.Target
+			}
+			if srv.Service != "" && srv.Service != serviceLabel(serviceType) {
+				info.Service = srv.Service
+			}
+		}
+	}
+
+	for _, record := range records {
+		switch {
+		case record.GetARecord() != nil:
+			if record.Name == domainName || record.Name == target {
+				info.Addresses = append(info.Addresses, record.GetARecord().Address)
+			}
+		case record.GetTxtRecord() != nil:
+			if record.Name != domainName {
+				continue
+			}
+			for k, v := range record.GetTxtRecord().Entries {
+				// Values we can't map, e.g. from a peer with a newer
+				// proto, are kept as properties so they are re-advertised
+				// unchanged
+				switch k {
+				case "kind":
+					if n, ok := parseEnumValue(v, badezimmer.DeviceKind_value); ok {
+						info.Kind = badezimmer.DeviceKind(n)
+					} else {
+						info.Properties[k] = v
+					}
+				case "category":
+					if n, ok := parseEnumValue(v, badezimmer.DeviceCategory_value); ok {
+						info.Category = badezimmer.DeviceCategory(n)
+					} else {
+						info.Properties[k] = v
+					}
+				default:
+					// A binary property that does not decode would
+					// only fail later in BinaryProperty
+					if validBinaryProperty(k, v) {
+						info.Properties[k] = v
+					}
+				}
+			}
+		}
+	}
+
+	return info
+}
+
+// parseEnumValue maps a TXT enum value back to its number. It accepts the
+// enum names and, since String() renders values missing from the generated
+// code as numbers, decimal numbers.
+func parseEnumValue(v string, values map[string]int32) (int32, bool) {
+	if n, ok := values[v]; ok {
+		return n, true
+	}
+	if n, err := strconv.ParseInt(v, 10, 32); err == nil {
+		return int32(n), true
+	}
+	return 0, false
 }
 
 func splitServiceType(serviceType string) []string {
@@ -502,36 +1797,93 @@ func splitServiceType(serviceType string) []string {
 	return result
 }
 
-func getLocalIPv4Addresses() []string {
+// AddressFilter decides which local addresses are advertised in A records.
+// Link-local addresses (169.254.0.0/16) are never advertised next to a
+// better address.
+type AddressFilter struct {
+	// ExcludeNonRoutable also drops shared, benchmarking and documentation
+	// ranges, which clients usually can't reach either
+	ExcludeNonRoutable bool
+
+	// LastResort advertises the filtered addresses when no other address
+	// is left, rather than none at all
+	LastResort bool
+}
+
+var nonRoutableNets = mustParseCIDRs(
+	"100.64.0.0/10",   // shared address space (carrier-grade NAT)
+	"192.0.0.0/24",    // IETF protocol assignments
+	"192.0.2.0/24",    // TEST-NET-1
+	"198.18.0.0/15",   // benchmarking
+	"198.51.100.0/24", // TEST-NET-2
+	"203.0.113.0/24",  // TEST-NET-3
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets
+}
+
+// filterAddresses applies filter to candidate IPv4 addresses.
+func filterAddresses(candidates []string, filter AddressFilter) []string {
+	var preferred, fallback []string
+	for _, candidate := range candidates {
+		ip := net.ParseIP(candidate)
+		if ip == nil {
+			continue
+		}
+
+		unwanted := ip.IsLinkLocalUnicast()
+		if filter.ExcludeNonRoutable {
+			for _, ipNet := range nonRoutableNets {
+				if ipNet.Contains(ip) {
+					unwanted = true
+					break
+				}
+			}
+		}
+
+		if unwanted {
+			fallback = append(fallback, candidate)
+		} else {
+			preferred = append(preferred, candidate)
+		}
+	}
+
+	if len(preferred) == 0 && filter.LastResort {
+		return fallback
+	}
+	return preferred
+}
+
+func getLocalIPv4Addresses(provider AddressProvider, filter AddressFilter) []string {
 	var addresses []string
 
-	ifaces, err := net.Interfaces()
+	ifaces, err := provider.Interfaces()
 	if err != nil {
 		return addresses
 	}
 
 	excludedPrefixes := []string{"127.", "172.17."}
 
-	for _, iface := range ifaces {
-		addrs, err := iface.Addrs()
-		if err != nil {
-			continue
-		}
-
-		for _, addr := range addrs {
-			var ip net.IP
-			switch v := addr.(type) {
-			case *net.IPNet:
-				ip = v.IP
-			case *net.IPAddr:
-				ip = v.IP
-			}
+	// Bridged interfaces may share an address, which is advertised once
+	seen := make(map[string]bool)
 
-			if ip == nil || ip.To4() == nil {
+	for _, iface := range ifaces {
+		for _, ip := range iface.IPs {
+			ipStr := ip.String()
+			if seen[ipStr] {
 				continue
 			}
+			seen[ipStr] = true
 
-			ipStr := ip.String()
 			excluded := false
 			for _, prefix := range excludedPrefixes {
 				if len(ipStr) >= len(prefix) && ipStr[:len(prefix)] == prefix {
@@ -546,17 +1898,46 @@ func getLocalIPv4Addresses() []string {
 		}
 	}
 
-	return addresses
+	return filterAddresses(addresses, filter)
 }
 
-func bytesEqual(a, b []byte) bool {
-	if len(a) != len(b) {
-		return false
+// setReuseOptions enables SO_REUSEADDR and, where the platform supports it,
+// SO_REUSEPORT. Without SO_REUSEPORT only a single process can use the port,
+// which is logged but not treated as an error.
+func setReuseOptions(fd int, setsockopt func(fd, level, opt, value int) error) error {
+	// Enable SO_REUSEADDR
+	if err := setsockopt(fd, syscall.SOL_SOCKET, syscall.SO_REUSEADDR, 1); err != nil {
+		return err
 	}
-	for i := range a {
-		if a[i] != b[i] {
-			return false
+
+	// Enable SO_REUSEPORT to allow multiple processes to bind to the same port
+	err := setsockopt(fd, syscall.SOL_SOCKET, SO_REUSEPORT, 1)
+	if errors.Is(err, syscall.ENOPROTOOPT) || errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.EOPNOTSUPP) {
+		log.Printf("Warning: SO_REUSEPORT not supported (%v), the multicast port cannot be shared with other processes", err)
+		return nil
+	}
+	return err
+}
+
+// interfaceIPv4 returns the primary IPv4 address of the named interface.
+func interfaceIPv4(name string) (net.IP, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find interface %s: %w", name, err)
+	}
+
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get addresses of interface %s: %w", name, err)
+	}
+
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok {
+			if ip := ipNet.IP.To4(); ip != nil {
+				return ip, nil
+			}
 		}
 	}
-	return true
+
+	return nil, fmt.Errorf("interface %s has no IPv4 address", name)
 }
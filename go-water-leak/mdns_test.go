@@ -0,0 +1,2171 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+	"google.golang.org/protobuf/proto"
+)
+
+// packetCapture receives the packets a responder would have multicast, see
+// WithResponseTarget.
+type packetCapture struct {
+	conn *net.UDPConn
+}
+
+func newPacketCapture(t *testing.T) *packetCapture {
+	t.Helper()
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("failed to listen for packets: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &packetCapture{conn: conn}
+}
+
+func (c *packetCapture) addr() *net.UDPAddr {
+	return c.conn.LocalAddr().(*net.UDPAddr)
+}
+
+// readRaw returns the next datagram, or an error if none arrives within
+// wait.
+func (c *packetCapture) readRaw(wait time.Duration) ([]byte, error) {
+	buffer := make([]byte, 65536)
+	c.conn.SetReadDeadline(time.Now().Add(wait))
+	n, _, err := c.conn.ReadFromUDP(buffer)
+	if err != nil {
+		return nil, err
+	}
+	return buffer[:n], nil
+}
+
+// read returns the next packet, or an error if none arrives within wait.
+func (c *packetCapture) read(wait time.Duration) (*badezimmer.MDNS, error) {
+	data, err := c.readRaw(wait)
+	if err != nil {
+		return nil, err
+	}
+
+	protoBytes, err := getProtobufData(data)
+	if err != nil {
+		return nil, err
+	}
+	packet := &badezimmer.MDNS{}
+	if err := proto.Unmarshal(protoBytes, packet); err != nil {
+		return nil, err
+	}
+	return packet, nil
+}
+
+// next returns the next packet, failing the test if none arrives.
+func (c *packetCapture) next(t *testing.T) *badezimmer.MDNS {
+	t.Helper()
+	packet, err := c.read(2 * time.Second)
+	if err != nil {
+		t.Fatalf("no packet captured: %v", err)
+	}
+	return packet
+}
+
+// expectNone fails the test if a packet arrives within wait.
+func (c *packetCapture) expectNone(t *testing.T, wait time.Duration) {
+	t.Helper()
+	packet, err := c.read(wait)
+	if err == nil {
+		t.Fatalf("unexpected packet captured: %v", packet)
+	}
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("failed to read packets: %v", err)
+	}
+}
+
+// newCapturedResponder returns a responder that is not started, but sends
+// from a loopback socket and delivers what it would multicast to the
+// returned capture.
+func newCapturedResponder(t *testing.T, opts ...Option) (*BadezimmerMDNS, *packetCapture) {
+	t.Helper()
+	capture := newPacketCapture(t)
+
+	opts = append([]Option{WithResponseTarget(capture.addr())}, opts...)
+	m := NewBadezimmerMDNS(opts...)
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	m.conn = conn
+	t.Cleanup(func() {
+		m.cancel()
+		conn.Close()
+	})
+	return m, capture
+}
+
+// testGroup returns a multicast group and port of the test's own.
+func testGroup() (string, int) {
+	return fmt.Sprintf("239.255.%d.%d", rand.Intn(256), 1+rand.Intn(254)), 20000 + rand.Intn(20000)
+}
+
+// startTestResponder starts a responder on a multicast group and port of
+// its own, delivering what it multicasts to the returned capture.
+func startTestResponder(t *testing.T, opts ...Option) (*BadezimmerMDNS, *packetCapture) {
+	t.Helper()
+	capture := newPacketCapture(t)
+
+	opts = append([]Option{WithRandomSeed(1), WithMulticastGroup(testGroup()), WithResponseTarget(capture.addr())}, opts...)
+	m := NewBadezimmerMDNS(opts...)
+	if err := m.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { m.Close() })
+	return m, capture
+}
+
+// logBuffer collects log output, safe for the goroutines logging into it.
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *logBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (b *logBuffer) contains(s string) bool {
+	return strings.Contains(b.String(), s)
+}
+
+// captureLog redirects the log output until the test ends.
+func captureLog(t *testing.T) *logBuffer {
+	t.Helper()
+	buf := &logBuffer{}
+	log.SetOutput(buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return buf
+}
+
+// addService registers info with m directly, skipping the delay, probing
+// and announcement of RegisterService.
+func addService(m *BadezimmerMDNS, info *MDNSServiceInfo) string {
+	domainName := generateDomainName(info.Type, info.Name)
+	m.mu.Lock()
+	m.registeredServices[domainName] = info
+	m.mu.Unlock()
+	return domainName
+}
+
+func testService(name string) *MDNSServiceInfo {
+	return &MDNSServiceInfo{
+		Name:       name,
+		Type:       "_waterleak._tcp.local.",
+		Port:       8080,
+		Kind:       badezimmer.DeviceKind_SENSOR_KIND,
+		Category:   badezimmer.DeviceCategory_WATER_LEAK,
+		Addresses:  []string{"192.0.2.2"},
+		TTL:        DefaultTTL,
+		Properties: map[string]string{"severity": "3"},
+	}
+}
+
+// querierPorts hands out a source port per query, so the questions of one
+// test are never suppressed as retransmissions of another.
+var querierPorts atomic.Int32
+
+func nextQuerier() *net.UDPAddr {
+	return &net.UDPAddr{IP: net.IPv4(192, 0, 2, 100), Port: 10000 + int(querierPorts.Add(1))}
+}
+
+// ask hands m a multicast query for the PTR records of names.
+func ask(m *BadezimmerMDNS, names ...string) {
+	query := &badezimmer.MDNSQueryRequest{}
+	for _, name := range names {
+		query.Questions = append(query.Questions, &badezimmer.MDNSQuestion{Name: name, Type: badezimmer.MDNSType_MDNS_PTR})
+	}
+	m.handleQuery(query, nextQuerier(), false, time.Time{})
+}
+
+// answeredNames returns the domain names the PTR answers of packet point at.
+func answeredNames(packet *badezimmer.MDNS) []string {
+	var names []string
+	for _, record := range packet.GetQueryResponse().GetAnswers() {
+		if ptr := record.GetPtrRecord(); ptr != nil {
+			names = append(names, ptr.DomainName)
+		}
+	}
+	return names
+}
+
+// announcedName returns the domain name the PTR answer of packet points at.
+func announcedName(packet *badezimmer.MDNS) string {
+	answers := packet.GetQueryResponse().GetAnswers()
+	if len(answers) == 0 {
+		return ""
+	}
+	return answers[0].GetPtrRecord().GetDomainName()
+}
+
+func TestCloseSendsGoodbyeBurstsBeforeClosing(t *testing.T) {
+	m, capture := newCapturedResponder(t)
+	kitchen := addService(m, testService("Kitchen"))
+	bathroom := addService(m, testService("Bathroom"))
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Everything was sent by the time Close returned
+	goodbyes := make(map[string]int)
+	for range 2 * AnnounceBurstCount {
+		packet := capture.next(t)
+		for _, record := range packet.GetQueryResponse().GetAnswers() {
+			if record.Ttl != 0 {
+				t.Errorf("goodbye record %s has TTL %d", record.Name, record.Ttl)
+			}
+		}
+		goodbyes[announcedName(packet)]++
+	}
+	capture.expectNone(t, 50*time.Millisecond)
+
+	for _, name := range []string{kitchen, bathroom} {
+		if goodbyes[name] != AnnounceBurstCount {
+			t.Errorf("%s got %d goodbyes, want %d", name, goodbyes[name], AnnounceBurstCount)
+		}
+	}
+	if got := m.Stats().GoodbyesSent; got != 2*AnnounceBurstCount {
+		t.Errorf("GoodbyesSent = %d, want %d", got, 2*AnnounceBurstCount)
+	}
+
+	// The socket is released afterwards
+	if _, err := m.conn.WriteToUDP([]byte{0}, capture.addr()); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("write after Close: %v, want net.ErrClosed", err)
+	}
+}
+
+func TestCloseTwiceIsNoop(t *testing.T) {
+	m, capture := newCapturedResponder(t)
+	addService(m, testService("Kitchen"))
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	for range AnnounceBurstCount {
+		capture.next(t)
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+	capture.expectNone(t, 50*time.Millisecond)
+}
+
+func TestStartAgainAfterAbortedStart(t *testing.T) {
+	m, _ := startTestResponder(t)
+
+	// As a device does when it fails to start after the responder did
+	m.abortStart()
+	if err := m.Start(); err != nil {
+		t.Fatalf("Start after abortStart: %v", err)
+	}
+	if err := m.RegisterService(testService("Kitchen")); err != nil {
+		t.Errorf("RegisterService after restart: %v", err)
+	}
+}
+
+func TestCloseStopsReceivingQuietly(t *testing.T) {
+	m, _ := startTestResponder(t)
+	logs := captureLog(t)
+
+	done := make(chan struct{})
+	go func() {
+		m.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close did not return, the receive loop is still running")
+	}
+
+	if logs.contains("Error reading from UDP") {
+		t.Errorf("clean shutdown logged a read error:\n%s", logs)
+	}
+}
+
+func TestLocalAddrAfterStart(t *testing.T) {
+	if addr := NewBadezimmerMDNS().LocalAddr(); addr != nil {
+		t.Errorf("LocalAddr before Start = %v, want nil", addr)
+	}
+
+	m, _ := startTestResponder(t)
+	addr, ok := m.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		t.Fatalf("LocalAddr = %v, want a UDP address", m.LocalAddr())
+	}
+	if addr.Port != m.groupPort {
+		t.Errorf("bound port %d, want the group port %d", addr.Port, m.groupPort)
+	}
+
+	w := NewWaterLeakDetector(0)
+	w.mdns = m
+	if got := w.State().MulticastAddr; got != addr.String() {
+		t.Errorf("state multicast_addr = %q, want %q", got, addr.String())
+	}
+}
+
+func TestAnswerableTypes(t *testing.T) {
+	newResponder := func(t *testing.T) (*BadezimmerMDNS, *packetCapture) {
+		m, capture := newCapturedResponder(t, WithAnswerableTypes([]string{"_waterleak._tcp.local."}))
+		addService(m, testService("Kitchen"))
+		lamp := testService("Lamp")
+		lamp.Type = "_lamp._tcp.local."
+		addService(m, lamp)
+		return m, capture
+	}
+
+	t.Run("allowed type", func(t *testing.T) {
+		m, capture := newResponder(t)
+		ask(m, "_waterleak._tcp.local.")
+		if got := answeredNames(capture.next(t)); len(got) != 1 || got[0] != "Kitchen._waterleak._tcp.local." {
+			t.Errorf("answered %v", got)
+		}
+	})
+
+	t.Run("registered but not allowed", func(t *testing.T) {
+		m, capture := newResponder(t)
+		ask(m, "_lamp._tcp.local.")
+		ask(m, "Lamp._lamp._tcp.local.")
+		capture.expectNone(t, 100*time.Millisecond)
+	})
+
+	t.Run("meta-query", func(t *testing.T) {
+		m, capture := newResponder(t)
+		ask(m, ServiceDiscoveryType)
+		if got := answeredNames(capture.next(t)); len(got) != 1 || got[0] != "Kitchen._waterleak._tcp.local." {
+			t.Errorf("meta-query answered %v, want only the allowed type", got)
+		}
+	})
+}
+
+func TestTransactionIDSource(t *testing.T) {
+	var next uint32
+	m, capture := newCapturedResponder(t, WithTransactionIDSource(func() uint32 {
+		next++
+		return next
+	}))
+	info := testService("Kitchen")
+	addService(m, info)
+
+	for range 3 {
+		if err := m.broadcastService(m.ctx, info); err != nil {
+			t.Fatalf("broadcastService: %v", err)
+		}
+	}
+	for want := uint32(1); want <= 3; want++ {
+		if got := capture.next(t).TransactionId; got != want {
+			t.Errorf("transaction id = %d, want %d", got, want)
+		}
+	}
+}
+
+// completeRecordSet reports whether response carries the PTR, SRV, A and TXT
+// records of a service.
+func completeRecordSet(response *badezimmer.MDNSQueryResponse) bool {
+	var ptr, srv, a, txt bool
+	for _, record := range append(response.GetAnswers(), response.GetAdditionalRecords()...) {
+		switch {
+		case record.GetPtrRecord() != nil:
+			ptr = true
+		case record.GetSrvRecord() != nil:
+			srv = true
+		case record.GetARecord() != nil:
+			a = true
+		case record.GetTxtRecord() != nil:
+			txt = true
+		}
+	}
+	return ptr && srv && a && txt
+}
+
+func TestQueriesDuringRegistrationGetNoPartialRecords(t *testing.T) {
+	m, capture := newCapturedResponder(t)
+	info := testService("Kitchen")
+	serviceType := info.Type
+	domainName := generateDomainName(serviceType, info.Name)
+
+	done := make(chan error, 1)
+	go func() { done <- m.RegisterService(info) }()
+
+	// Query all through the delay and the probes
+	for registering := true; registering; {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("RegisterService: %v", err)
+			}
+			registering = false
+		default:
+			ask(m, serviceType, domainName)
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// Let the last question out of the retransmission window
+	time.Sleep(QuestionSuppressionWindow)
+	ask(m, serviceType)
+
+	var probes, responses int
+	for {
+		packet, err := capture.read(100 * time.Millisecond)
+		if err != nil {
+			break
+		}
+		if packet.GetQueryRequest() != nil {
+			probes++
+			continue
+		}
+		responses++
+		if !completeRecordSet(packet.GetQueryResponse()) {
+			t.Errorf("partial response: %v", packet.GetQueryResponse())
+		}
+	}
+
+	if probes != ProbeAttempts {
+		t.Errorf("sent %d probes, want %d", probes, ProbeAttempts)
+	}
+	// At least the announcement and the answer to the last query
+	if responses < 2 {
+		t.Errorf("sent %d responses, want the announcement and an answer", responses)
+	}
+}
+
+// deliver sends packet to m's socket from a socket of its own.
+func deliver(t *testing.T, m *BadezimmerMDNS, packet *badezimmer.MDNS) {
+	t.Helper()
+	rawBytes, err := prepareProtobufRequest(packet)
+	if err != nil {
+		t.Fatalf("failed to frame packet: %v", err)
+	}
+	deliverRaw(t, m, rawBytes)
+}
+
+// deliverRaw sends the datagram rawBytes to m's socket.
+func deliverRaw(t *testing.T, m *BadezimmerMDNS, rawBytes []byte) {
+	t.Helper()
+	conn, err := net.DialUDP("udp4", nil, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: m.LocalAddr().(*net.UDPAddr).Port})
+	if err != nil {
+		t.Fatalf("failed to dial responder: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write(rawBytes); err != nil {
+		t.Fatalf("failed to send packet: %v", err)
+	}
+}
+
+func TestStatsCountExchange(t *testing.T) {
+	m, capture := startTestResponder(t)
+	info := testService("Kitchen")
+	addService(m, info)
+
+	deliver(t, m, &badezimmer.MDNS{
+		TransactionId: 7,
+		Data: &badezimmer.MDNS_QueryRequest{QueryRequest: &badezimmer.MDNSQueryRequest{
+			Questions: []*badezimmer.MDNSQuestion{{Name: info.Type, Type: badezimmer.MDNSType_MDNS_PTR}},
+		}},
+	})
+	response, err := capture.readRaw(2 * time.Second)
+	if err != nil {
+		t.Fatalf("query was not answered: %v", err)
+	}
+
+	// Our own answer coming back is recognized and dropped
+	deliverRaw(t, m, response)
+	waitFor(t, func() bool { return m.Stats().SelfSuppressed == 1 })
+
+	stats := m.Stats()
+	if stats.PacketsReceived != 2 {
+		t.Errorf("PacketsReceived = %d, want 2", stats.PacketsReceived)
+	}
+	if stats.PacketsSent != 1 {
+		t.Errorf("PacketsSent = %d, want the answer", stats.PacketsSent)
+	}
+	if stats.ResponsesSent != 1 {
+		t.Errorf("ResponsesSent = %d, want 1", stats.ResponsesSent)
+	}
+}
+
+func TestServiceTypeTrailingDot(t *testing.T) {
+	dotted, undotted := testService("Kitchen"), testService("Kitchen")
+	undotted.Type = strings.TrimSuffix(undotted.Type, ".")
+
+	if a, b := generateDomainName(dotted.Type, dotted.Name), generateDomainName(undotted.Type, undotted.Name); a != b {
+		t.Errorf("domain names differ: %q and %q", a, b)
+	}
+	want, got := infoToRecords(dotted), infoToRecords(undotted)
+	if len(got) != len(want) {
+		t.Fatalf("got %d records, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !proto.Equal(got[i], want[i]) {
+			t.Errorf("record %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	m, capture := newCapturedResponder(t, WithUnicastAnswers(true))
+	addService(m, testService("Kitchen"))
+	if err := m.UpdateService(undotted); err != nil {
+		t.Fatalf("UpdateService: %v", err)
+	}
+	capture.next(t)
+	if undotted.Type != "_waterleak._tcp.local" {
+		t.Errorf("UpdateService changed the caller's type to %q", undotted.Type)
+	}
+
+	domainName := generateDomainName(dotted.Type, dotted.Name)
+	m.mu.RLock()
+	registered, ok := m.registeredServices[domainName]
+	m.mu.RUnlock()
+	if !ok {
+		t.Fatalf("%s is not registered", domainName)
+	}
+	if registered.Type != dotted.Type {
+		t.Errorf("registered type = %q, want %q", registered.Type, dotted.Type)
+	}
+
+	// Both spellings of the type find the service
+	for _, name := range []string{"_waterleak._tcp.local.", "_waterleak._tcp.local"} {
+		query := &badezimmer.MDNSQueryRequest{Questions: []*badezimmer.MDNSQuestion{{Name: name, Type: badezimmer.MDNSType_MDNS_PTR}}}
+		m.handleQuery(query, capture.addr(), true, time.Time{})
+		if names := answeredNames(capture.next(t)); len(names) != 1 || names[0] != domainName {
+			t.Errorf("query for %q answered with %v", name, names)
+		}
+	}
+}
+
+func TestUpdateAddressesKeepsProperties(t *testing.T) {
+	m, capture := newCapturedResponder(t)
+	info := testService("Kitchen")
+	info.Properties["location"] = "bathroom"
+	domainName := addService(m, info)
+
+	if err := m.UpdateAddresses(domainName, []string{"192.0.2.7", "192.0.2.8"}); err != nil {
+		t.Fatalf("UpdateAddresses: %v", err)
+	}
+
+	response := capture.next(t).GetQueryResponse()
+	var addrs []string
+	var txt map[string]string
+	for _, record := range append(response.GetAnswers(), response.GetAdditionalRecords()...) {
+		if a := record.GetARecord(); a != nil {
+			addrs = append(addrs, a.Address)
+		}
+		if entries := record.GetTxtRecord().GetEntries(); entries != nil {
+			txt = entries
+		}
+	}
+	sort.Strings(addrs)
+	if !reflect.DeepEqual(addrs, []string{"192.0.2.7", "192.0.2.8"}) {
+		t.Errorf("A records = %v, want the new addresses", addrs)
+	}
+	for k, v := range map[string]string{"severity": "3", "location": "bathroom"} {
+		if txt[k] != v {
+			t.Errorf("TXT %s = %q, want %q", k, txt[k], v)
+		}
+	}
+
+	if err := m.UpdateAddresses("Missing._waterleak._tcp.local.", nil); !errors.Is(err, ErrServiceNotRegistered) {
+		t.Errorf("err = %v, want ErrServiceNotRegistered", err)
+	}
+}
+
+func TestValidateNameLength(t *testing.T) {
+	tests := []struct {
+		name        string
+		instance    string
+		serviceType string
+		want        error
+	}{
+		{"fits", "Kitchen", "_waterleak._tcp.local.", nil},
+		{"longest label", strings.Repeat("a", MaxLabelLength), "_waterleak._tcp.local.", nil},
+		{"long instance", strings.Repeat("a", MaxLabelLength+1), "_waterleak._tcp.local.", ErrLabelTooLong},
+		{"long type label", "Kitchen", "_" + strings.Repeat("w", MaxLabelLength) + "._tcp.local.", ErrLabelTooLong},
+		{"long name", "Kitchen", strings.Repeat(strings.Repeat("x", 60)+".", 4) + "local.", ErrNameTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := testService(tt.instance)
+			info.Type = tt.serviceType
+			if err := info.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	m, _ := newCapturedResponder(t)
+	if err := m.RegisterService(testService(strings.Repeat("a", 300))); !errors.Is(err, ErrLabelTooLong) {
+		t.Errorf("RegisterService() = %v, want ErrLabelTooLong", err)
+	}
+	if len(m.snapshotServices()) != 0 {
+		t.Error("over-long service was registered")
+	}
+}
+
+func TestAggressiveAdditional(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		t.Run(fmt.Sprint(enabled), func(t *testing.T) {
+			clock := newFakeClock()
+			m, capture := newCapturedResponder(t, WithClock(clock), WithAggressiveAdditional(enabled))
+			info := testService("Kitchen")
+			addService(m, info)
+
+			for _, name := range []string{ServiceDiscoveryType, info.Type} {
+				clock.Advance(MulticastAnswerInterval)
+				ask(m, name)
+				response := capture.next(t).GetQueryResponse()
+				if len(response.GetAnswers()) != 1 {
+					t.Fatalf("query for %s got %d answers, want 1", name, len(response.GetAnswers()))
+				}
+				if got := completeRecordSet(response); got != enabled {
+					t.Errorf("query for %s resolvable without a follow-up = %v, want %v", name, got, enabled)
+				}
+			}
+		})
+	}
+}
+
+// groupMembers returns the interfaces the kernel reports as members of
+// group, from /proc/net/igmp.
+func groupMembers(t *testing.T, group net.IP) []string {
+	t.Helper()
+	data, err := os.ReadFile("/proc/net/igmp")
+	if err != nil {
+		t.Skipf("cannot read group memberships: %v", err)
+	}
+
+	// Groups are listed in host byte order under their interface
+	ip := group.To4()
+	hex := fmt.Sprintf("%02X%02X%02X%02X", ip[3], ip[2], ip[1], ip[0])
+	var members []string
+	var iface string
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case !strings.HasPrefix(line, "\t"):
+			if len(fields) > 1 {
+				iface = fields[1]
+			}
+		case fields[0] == hex:
+			members = append(members, iface)
+		}
+	}
+	return members
+}
+
+func TestJoinOnSelectedInterface(t *testing.T) {
+	// Loopback is never the default route for multicast, so a join that
+	// ignored the interface would land elsewhere
+	if _, err := interfaceIPv4("lo"); err != nil {
+		t.Skipf("no loopback interface: %v", err)
+	}
+	m, _ := startTestResponder(t, WithInterface("lo"))
+
+	members := groupMembers(t, net.ParseIP(m.groupIP))
+	if !reflect.DeepEqual(members, []string{"lo"}) {
+		t.Errorf("group %s joined on %v, want [lo]", m.groupIP, members)
+	}
+}
+
+func TestFloodDropsInsteadOfStallingReader(t *testing.T) {
+	m, _ := startTestResponder(t)
+	info := testService("Kitchen")
+	addService(m, info)
+
+	conn, err := net.DialUDP("udp4", nil, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: m.LocalAddr().(*net.UDPAddr).Port})
+	if err != nil {
+		t.Fatalf("failed to dial responder: %v", err)
+	}
+	defer conn.Close()
+
+	// Handlers block on the service lock until the test ends
+	m.mu.Lock()
+	t.Cleanup(m.mu.Unlock)
+
+	// Each packet is read although every handler is stuck. They are sent
+	// one at a time so the socket buffer never overflows.
+	const flood = 4 * PacketQueueSize
+	for i := range flood {
+		rawBytes, err := prepareProtobufRequest(&badezimmer.MDNS{
+			TransactionId: uint32(i + 1),
+			Data: &badezimmer.MDNS_QueryRequest{QueryRequest: &badezimmer.MDNSQueryRequest{
+				Questions: []*badezimmer.MDNSQuestion{{Name: info.Type, Type: badezimmer.MDNSType_MDNS_PTR}},
+			}},
+		})
+		if err != nil {
+			t.Fatalf("failed to frame packet: %v", err)
+		}
+		if _, err := conn.Write(rawBytes); err != nil {
+			t.Fatalf("failed to send packet: %v", err)
+		}
+		waitFor(t, func() bool { return m.Stats().PacketsReceived == uint64(i+1) })
+	}
+
+	if dropped := m.Stats().PacketsDropped; dropped == 0 {
+		t.Error("no packets dropped with the queue full")
+	} else if dropped > flood-PacketQueueSize-PacketWorkers {
+		t.Errorf("dropped %d packets, more than did not fit the queue", dropped)
+	}
+}
+
+func TestRecordsToInfoRoundTrip(t *testing.T) {
+	want := testService("Kitchen")
+	want.Addresses = []string{"192.0.2.2", "192.0.2.3"}
+	want.Protocol = badezimmer.TransportProtocol_TCP_PROTOCOL
+	want.Properties["location"] = "bathroom"
+
+	records := infoToRecords(want)
+	// Records may arrive in any order
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+
+	got := recordsToInfo(records)
+	if got == nil {
+		t.Fatal("recordsToInfo returned nil")
+	}
+	sort.Strings(got.Addresses)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("recordsToInfo() = %+v, want %+v", got, want)
+	}
+
+	// The PTR record is now the last one
+	if recordsToInfo(records[:len(records)-1]) != nil {
+		t.Error("rebuilt a service without its PTR record")
+	}
+}
+
+func TestBreakerOpensAndRecovers(t *testing.T) {
+	m, capture := newCapturedResponder(t)
+	info := testService("Kitchen")
+	addService(m, info)
+
+	// Nothing can be sent to port zero
+	m.responseTarget = &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)}
+	for range BreakerFailureThreshold {
+		if err := m.broadcastService(m.ctx, info); err == nil || errors.Is(err, ErrBreakerOpen) {
+			t.Fatalf("broadcastService() = %v, want a send failure", err)
+		}
+	}
+	if !m.Stats().BreakerOpen {
+		t.Fatal("breaker closed after persistent failures")
+	}
+	if err := m.broadcastService(m.ctx, info); !errors.Is(err, ErrBreakerOpen) {
+		t.Errorf("broadcastService() = %v, want ErrBreakerOpen", err)
+	}
+	if got := m.Stats().SendFailures; got != BreakerFailureThreshold {
+		t.Errorf("SendFailures = %d, want %d", got, BreakerFailureThreshold)
+	}
+
+	// The next probe succeeds and re-announces the service, as the probe
+	// loop does on each tick
+	m.responseTarget = capture.addr()
+	if !m.breaker.tryHalfOpen() {
+		t.Fatal("open breaker refused the probe")
+	}
+	m.announceAll()
+	if name := announcedName(capture.next(t)); name != generateDomainName(info.Type, info.Name) {
+		t.Errorf("probe announced %q", name)
+	}
+	if m.Stats().BreakerOpen {
+		t.Error("breaker still open after a successful probe")
+	}
+}
+
+func TestReverseLookup(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		t.Run(fmt.Sprint(enabled), func(t *testing.T) {
+			m, capture := newCapturedResponder(t, WithReverseLookup(enabled))
+			domainName := addService(m, testService("Kitchen"))
+
+			ask(m, "2.2.0.192.in-addr.arpa.", "9.2.0.192.in-addr.arpa.")
+			if !enabled {
+				capture.expectNone(t, 100*time.Millisecond)
+				return
+			}
+
+			answers := capture.next(t).GetQueryResponse().GetAnswers()
+			if len(answers) != 1 {
+				t.Fatalf("got %d answers, want one for our address", len(answers))
+			}
+			ptr := answers[0].GetPtrRecord()
+			if answers[0].Name != "2.2.0.192.in-addr.arpa." || ptr.GetDomainName() != domainName {
+				t.Errorf("answered %s -> %s, want our address -> %s", answers[0].Name, ptr.GetDomainName(), domainName)
+			}
+		})
+	}
+}
+
+func TestSiblingsDoNotAnswerEachOther(t *testing.T) {
+	a, capture := startTestResponder(t, WithInstanceID("kitchen-1"))
+	info := testService("Kitchen")
+	addService(a, info)
+	target := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: a.LocalAddr().(*net.UDPAddr).Port}
+
+	// query sends a PTR query for info from m, stamped with its instance id
+	query := func(m *BadezimmerMDNS) {
+		m.responseTarget = target
+		err := m.sendPacket(&badezimmer.MDNS{
+			TransactionId: m.nextTransactionID(),
+			InstanceId:    m.instanceID,
+			Data: &badezimmer.MDNS_QueryRequest{QueryRequest: &badezimmer.MDNSQueryRequest{
+				Questions: []*badezimmer.MDNSQuestion{{Name: info.Type, Type: badezimmer.MDNSType_MDNS_PTR}},
+			}},
+		})
+		if err != nil {
+			t.Fatalf("sendPacket: %v", err)
+		}
+	}
+
+	sibling, _ := newCapturedResponder(t, WithRandomSeed(2), WithInstanceID("kitchen-1"))
+	query(sibling)
+	capture.expectNone(t, 200*time.Millisecond)
+	if got := a.Stats().SiblingSuppressed; got != 1 {
+		t.Errorf("SiblingSuppressed = %d, want 1", got)
+	}
+
+	other, _ := newCapturedResponder(t, WithRandomSeed(3), WithInstanceID("bathroom-1"))
+	query(other)
+	if names := answeredNames(capture.next(t)); len(names) != 1 {
+		t.Errorf("query from another instance answered with %v", names)
+	}
+}
+
+// rejectReusePort fails SO_REUSEPORT with err and sets every other option.
+func rejectReusePort(err error) func(fd, level, opt, value int) error {
+	return func(fd, level, opt, value int) error {
+		if level == syscall.SOL_SOCKET && opt == SO_REUSEPORT {
+			return err
+		}
+		return syscall.SetsockoptInt(fd, level, opt, value)
+	}
+}
+
+func TestStartWithoutReusePort(t *testing.T) {
+	logs := captureLog(t)
+	m := NewBadezimmerMDNS(WithRandomSeed(1), WithMulticastGroup(testGroup()))
+	m.setsockopt = rejectReusePort(syscall.ENOPROTOOPT)
+	if err := m.Start(); err != nil {
+		t.Fatalf("Start without SO_REUSEPORT: %v", err)
+	}
+	defer m.Close()
+
+	if !logs.contains("SO_REUSEPORT not supported") {
+		t.Error("missing warning about SO_REUSEPORT")
+	}
+}
+
+func TestSetReuseOptionsKeepsOtherErrors(t *testing.T) {
+	setsockopt := func(fd, level, opt, value int) error {
+		if opt == SO_REUSEPORT {
+			return syscall.EPERM
+		}
+		return nil
+	}
+	if err := setReuseOptions(0, setsockopt); !errors.Is(err, syscall.EPERM) {
+		t.Errorf("setReuseOptions() = %v, want EPERM", err)
+	}
+}
+
+func TestCollisionStrategyNamesRetries(t *testing.T) {
+	var attempts []int
+	strategy := func(base string, attempt int) string {
+		attempts = append(attempts, attempt)
+		return fmt.Sprintf("%s-%d", base, attempt)
+	}
+	m, _ := newCapturedResponder(t, WithCollisionStrategy(strategy))
+
+	// Another responder holds the name and the first retry
+	for _, name := range []string{"Kitchen", "Kitchen-2"} {
+		m.cache.upsert(testService(name), time.Now())
+	}
+
+	info := testService("Kitchen")
+	if err := m.probe(info); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if info.Name != "Kitchen-3" {
+		t.Errorf("claimed %q, want Kitchen-3", info.Name)
+	}
+	if !reflect.DeepEqual(attempts, []int{2, 3}) {
+		t.Errorf("strategy called for attempts %v, want [2 3]", attempts)
+	}
+}
+
+func TestServiceEventCallback(t *testing.T) {
+	events := make(chan ServiceEvent, 3)
+	m, _ := newCapturedResponder(t, WithOnServiceEvent(func(evt ServiceEvent) { events <- evt }))
+
+	var err error
+	err = m.RegisterService(testService("Kitchen"))
+	if err != nil {
+		t.Fatalf("RegisterService: %v", err)
+	}
+	updated := testService("Kitchen")
+	updated.Properties["severity"] = "5"
+	if err := m.UpdateService(updated); err != nil {
+		t.Fatalf("UpdateService: %v", err)
+	}
+	err = m.UnregisterService(testService("Kitchen"))
+	if err != nil {
+		t.Fatalf("UnregisterService: %v", err)
+	}
+
+	// Callbacks run on their own goroutines, in any order
+	got := make(map[ServiceEventType]*MDNSServiceInfo)
+	for range 3 {
+		select {
+		case evt := <-events:
+			got[evt.Type] = evt.Info
+		case <-time.After(2 * time.Second):
+			t.Fatalf("got %d events, want 3", len(got))
+		}
+	}
+	for _, eventType := range []ServiceEventType{ServiceRegistered, ServiceUpdated, ServiceUnregistered} {
+		if info := got[eventType]; info == nil || info.Name != "Kitchen" {
+			t.Errorf("%s event carried %+v", eventType, info)
+		}
+	}
+	if info := got[ServiceUpdated]; info != nil {
+		if info.Properties["severity"] != "5" {
+			t.Errorf("updated event has severity %q, want 5", info.Properties["severity"])
+		}
+		if info == updated {
+			t.Error("callback got the caller's info rather than a copy")
+		}
+	}
+}
+
+func TestStandardPortAnswersQueries(t *testing.T) {
+	m, capture := startTestResponder(t, WithStandardPort(true))
+	info := testService("Kitchen")
+	domainName := addService(m, info)
+
+	rawBytes, err := prepareProtobufRequest(&badezimmer.MDNS{
+		TransactionId: 7,
+		Data: &badezimmer.MDNS_QueryRequest{QueryRequest: &badezimmer.MDNSQueryRequest{
+			Questions: []*badezimmer.MDNSQuestion{{Name: info.Type, Type: badezimmer.MDNSType_MDNS_PTR}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("failed to frame query: %v", err)
+	}
+	conn, err := net.DialUDP("udp4", nil, &net.UDPAddr{IP: net.ParseIP(m.groupIP), Port: StandardMDNSPort})
+	if err != nil {
+		t.Fatalf("failed to dial the standard port: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write(rawBytes); err != nil {
+		t.Fatalf("failed to send query: %v", err)
+	}
+
+	if names := answeredNames(capture.next(t)); len(names) != 1 || names[0] != domainName {
+		t.Errorf("query on port %d answered with %v", StandardMDNSPort, names)
+	}
+}
+
+func TestSentTransactionsForgetOldSocket(t *testing.T) {
+	var sent sentTransactions
+	for id := range uint32(sentTransactionsSize + 10) {
+		sent.add(id)
+	}
+	if sent.contains(5) || !sent.contains(sentTransactionsSize+5) {
+		t.Error("ring does not keep exactly the latest ids")
+	}
+
+	sent.reset()
+	if sent.contains(sentTransactionsSize + 5) {
+		t.Error("id still known after reset")
+	}
+	sent.add(1)
+	if !sent.contains(1) {
+		t.Error("id added after reset is unknown")
+	}
+}
+
+func TestBindingClearsSentTransactions(t *testing.T) {
+	m := NewBadezimmerMDNS(WithRandomSeed(1), WithMulticastGroup(testGroup()))
+	m.sentTxIDs.add(42)
+	if err := m.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Close()
+
+	if m.sentTxIDs.contains(42) {
+		t.Error("id sent before binding still treated as our own")
+	}
+}
+
+func TestMaintenanceSilencesResponder(t *testing.T) {
+	clock := newFakeClock()
+	m, capture := newCapturedResponder(t, WithClock(clock))
+	info := testService("Kitchen")
+	domainName := addService(m, info)
+	d := NewDevice(info, nil)
+	d.mdns = m
+
+	m.EnterMaintenance()
+	if !d.State().Maintenance {
+		t.Error("state does not report maintenance")
+	}
+	ask(m, info.Type)
+	if err := m.announceService(m.ctx, info); err != nil {
+		t.Fatalf("announceService: %v", err)
+	}
+	capture.expectNone(t, 100*time.Millisecond)
+
+	// Leaving announces right away, and queries are answered again
+	m.ExitMaintenance()
+	if name := announcedName(capture.next(t)); name != domainName {
+		t.Errorf("announced %q on exit, want %q", name, domainName)
+	}
+	if d.State().Maintenance {
+		t.Error("state still reports maintenance")
+	}
+	clock.Advance(MulticastAnswerInterval)
+	ask(m, info.Type)
+	if names := answeredNames(capture.next(t)); len(names) != 1 || names[0] != domainName {
+		t.Errorf("query after maintenance answered with %v", names)
+	}
+}
+
+func TestGoodbyesSentDuringMaintenance(t *testing.T) {
+	clock := newFakeClock()
+	m, capture := newCapturedResponder(t, WithClock(clock))
+	info := testService("Kitchen")
+	domainName := addService(m, info)
+
+	m.EnterMaintenance()
+	var err error
+	whileAdvancing(clock, func() { err = m.UnregisterService(info) })
+	if err != nil {
+		t.Fatalf("UnregisterService: %v", err)
+	}
+
+	// Resolvers must still learn that the service is gone
+	for range AnnounceBurstCount {
+		packet := capture.next(t)
+		if name := announcedName(packet); name != domainName {
+			t.Errorf("goodbye for %q, want %q", name, domainName)
+		}
+		for _, record := range packet.GetQueryResponse().GetAnswers() {
+			if record.Ttl != 0 {
+				t.Errorf("goodbye record %s has TTL %d", record.Name, record.Ttl)
+			}
+		}
+	}
+	if got := m.Stats().GoodbyesSent; got != AnnounceBurstCount {
+		t.Errorf("GoodbyesSent = %d, want %d", got, AnnounceBurstCount)
+	}
+}
+
+func TestPacketFramingAndContentErrors(t *testing.T) {
+	logs := captureLog(t)
+	m, capture := newCapturedResponder(t)
+	from := &net.UDPAddr{IP: net.IPv4(192, 0, 2, 100), Port: 5353}
+
+	if _, err := getProtobufData([]byte{0, 0, 0, 10, 1}); !errors.Is(err, ErrMalformedFrame) {
+		t.Errorf("truncated frame err = %v, want ErrMalformedFrame", err)
+	}
+
+	// The prefix announces more bytes than the packet has
+	m.handlePacket(receivedPacket{data: []byte{0, 0, 0, 10, 1}, addr: from})
+	// A whole frame that is not a protobuf message
+	m.handlePacket(receivedPacket{data: []byte{0, 0, 0, 2, 0xff, 0xff}, addr: from})
+
+	stats := m.Stats()
+	if stats.FramingErrors != 1 || stats.ContentErrors != 1 {
+		t.Errorf("FramingErrors = %d, ContentErrors = %d, want 1 each", stats.FramingErrors, stats.ContentErrors)
+	}
+	if !logs.contains("bad framing") || !logs.contains(ErrMalformedContent.Error()) {
+		t.Errorf("errors not logged apart:\n%s", logs)
+	}
+	capture.expectNone(t, 50*time.Millisecond)
+}
+
+func TestRetransmittedQuestionsAnsweredOnce(t *testing.T) {
+	clock := newFakeClock()
+	m, capture := newCapturedResponder(t, WithClock(clock), WithUnicastAnswers(true))
+	info := testService("Kitchen")
+	addService(m, info)
+
+	// Answered by unicast, so only the question suppression applies
+	query := &badezimmer.MDNSQueryRequest{Questions: []*badezimmer.MDNSQuestion{{Name: info.Type, Type: badezimmer.MDNSType_MDNS_PTR}}}
+	for range 3 {
+		m.handleQuery(query, capture.addr(), true, time.Time{})
+		clock.Advance(100 * time.Millisecond)
+	}
+	capture.next(t)
+	capture.expectNone(t, 100*time.Millisecond)
+	if got := m.Stats().DuplicateQuestions; got != 2 {
+		t.Errorf("DuplicateQuestions = %d, want 2", got)
+	}
+
+	// Once the window passed, the question is answered again
+	clock.Advance(QuestionSuppressionWindow)
+	m.handleQuery(query, capture.addr(), true, time.Time{})
+	capture.next(t)
+
+	// The same question from another source is not a retransmission
+	other := newPacketCapture(t)
+	m.handleQuery(query, other.addr(), true, time.Time{})
+	other.next(t)
+}
+
+func TestRegisterServiceTwice(t *testing.T) {
+	m, capture := newCapturedResponder(t)
+
+	if err := m.RegisterService(testService("Kitchen")); err != nil {
+		t.Fatalf("first RegisterService: %v", err)
+	}
+	for {
+		if packet, err := capture.read(100 * time.Millisecond); err != nil || packet.GetQueryResponse() != nil {
+			break
+		}
+	}
+
+	second := testService("Kitchen")
+	second.Port = 9090
+	if err := m.RegisterService(second); !errors.Is(err, ErrAlreadyRegistered) {
+		t.Fatalf("second RegisterService() = %v, want ErrAlreadyRegistered", err)
+	}
+
+	// The first registration stays, and nothing was sent for the second
+	capture.expectNone(t, 100*time.Millisecond)
+	if services := m.snapshotServices(); len(services) != 1 || services[0].Port != 8080 {
+		t.Errorf("registered services = %v, want the first one", services)
+	}
+}
+
+func TestRegisteredServicesCapped(t *testing.T) {
+	clock := newFakeClock()
+	m, _ := newCapturedResponder(t, WithClock(clock), WithMaxRegisteredServices(2))
+
+	for _, name := range []string{"Kitchen", "Bathroom"} {
+		var err error
+		whileAdvancing(clock, func() { err = m.RegisterService(testService(name)) })
+		if err != nil {
+			t.Fatalf("RegisterService(%s): %v", name, err)
+		}
+	}
+
+	if err := m.RegisterService(testService("Hallway")); !errors.Is(err, ErrTooManyServices) {
+		t.Errorf("RegisterService beyond the limit = %v, want ErrTooManyServices", err)
+	}
+	if n := len(m.snapshotServices()); n != 2 {
+		t.Errorf("%d services registered, want 2", n)
+	}
+}
+
+func TestUpdateUnknownServiceFails(t *testing.T) {
+	m, capture := newCapturedResponder(t)
+
+	if err := m.UpdateService(testService("Kitchen")); !errors.Is(err, ErrServiceNotRegistered) {
+		t.Errorf("UpdateService() = %v, want ErrServiceNotRegistered", err)
+	}
+	if n := len(m.snapshotServices()); n != 0 {
+		t.Errorf("%d services registered, want none", n)
+	}
+	capture.expectNone(t, 100*time.Millisecond)
+}
+
+// txtEntries returns the TXT entries of the response in packet.
+func txtEntries(packet *badezimmer.MDNS) map[string]string {
+	response := packet.GetQueryResponse()
+	for _, record := range append(response.GetAnswers(), response.GetAdditionalRecords()...) {
+		if txt := record.GetTxtRecord(); txt != nil {
+			return txt.Entries
+		}
+	}
+	return nil
+}
+
+func TestUptimeAdvancesBetweenAnswers(t *testing.T) {
+	m, capture := startTestResponder(t, WithUptimeProperty(true))
+	info := testService("Kitchen")
+	addService(m, info)
+
+	last := 0
+	for range 2 {
+		// Uptime alone never triggers an announcement
+		capture.expectNone(t, time.Second+100*time.Millisecond)
+
+		ask(m, info.Type)
+		uptime, err := strconv.Atoi(txtEntries(capture.next(t))["uptime"])
+		if err != nil {
+			t.Fatalf("uptime: %v", err)
+		}
+		if uptime <= last {
+			t.Errorf("uptime = %d, want more than %d", uptime, last)
+		}
+		last = uptime
+	}
+}
+
+func TestSequenceNumbersIncrementPerAnnouncement(t *testing.T) {
+	clock := newFakeClock()
+	m, capture := startTestResponder(t, WithClock(clock), WithSequenceNumbers(true), WithAnnounceInterval(time.Minute))
+	t.Cleanup(func() { whileAdvancing(clock, func() { m.Close() }) })
+	addService(m, testService("Kitchen"))
+	clock.waitForPending(t, 2)
+
+	update := testService("Kitchen")
+	update.Properties["severity"] = "5"
+	if err := m.UpdateService(update); err != nil {
+		t.Fatalf("UpdateService: %v", err)
+	}
+	if got := txtEntries(capture.next(t))[SequenceProperty]; got != "1" {
+		t.Errorf("update announced seq %q, want 1", got)
+	}
+
+	clock.Advance(time.Minute)
+	if got := txtEntries(capture.next(t))[SequenceProperty]; got != "2" {
+		t.Errorf("renovation announced seq %q, want 2", got)
+	}
+
+	// Answers repeat the latest number, only announcements bump it
+	clock.Advance(MulticastAnswerInterval)
+	ask(m, "_waterleak._tcp.local.")
+	if got := txtEntries(capture.next(t))[SequenceProperty]; got != "2" {
+		t.Errorf("answer carries seq %q, want 2", got)
+	}
+
+	update.Properties["severity"] = "7"
+	if err := m.UpdateService(update); err != nil {
+		t.Fatalf("UpdateService: %v", err)
+	}
+	if got := txtEntries(capture.next(t))[SequenceProperty]; got != "3" {
+		t.Errorf("second update announced seq %q, want 3", got)
+	}
+}
+
+func TestCancelStopsGoodbyeBurst(t *testing.T) {
+	m, capture := newCapturedResponder(t)
+	info := testService("Kitchen")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.sendGoodbye(ctx, info) }()
+	capture.next(t)
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("sendGoodbye() = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("burst kept going after cancellation")
+	}
+	capture.expectNone(t, 50*time.Millisecond)
+	if got := m.Stats().GoodbyesSent; got != 1 {
+		t.Errorf("GoodbyesSent = %d, want 1", got)
+	}
+
+	if err := m.broadcastService(ctx, info); !errors.Is(err, context.Canceled) {
+		t.Errorf("broadcastService() with a cancelled context = %v", err)
+	}
+}
+
+func TestCancelAbortsRegistration(t *testing.T) {
+	m, _ := newCapturedResponder(t)
+
+	done := make(chan error, 1)
+	go func() { done <- m.RegisterService(testService("Kitchen")) }()
+	m.cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("RegisterService() = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("registration kept waiting after cancellation")
+	}
+}
+
+func TestStartTwice(t *testing.T) {
+	m, _ := startTestResponder(t)
+	if err := m.Start(); !errors.Is(err, ErrAlreadyStarted) {
+		t.Errorf("second Start() = %v, want ErrAlreadyStarted", err)
+	}
+}
+
+func TestRecordInterceptor(t *testing.T) {
+	m, capture := startTestResponder(t, WithRecordInterceptor(func(records []*badezimmer.MDNSRecord) []*badezimmer.MDNSRecord {
+		kept := make([]*badezimmer.MDNSRecord, 0, len(records))
+		for _, record := range records {
+			if record.GetTxtRecord() != nil {
+				record = proto.Clone(record).(*badezimmer.MDNSRecord)
+				delete(record.GetTxtRecord().Entries, "severity")
+			}
+			kept = append(kept, record)
+		}
+		return kept
+	}))
+	info := testService("Kitchen")
+	info.Properties["location"] = "bathroom"
+	addService(m, info)
+
+	ask(m, info.Type)
+	entries := txtEntries(capture.next(t))
+	if _, ok := entries["severity"]; ok {
+		t.Errorf("TXT %v still has the intercepted key", entries)
+	}
+	if entries["location"] != "bathroom" {
+		t.Errorf("TXT %v lost the other keys", entries)
+	}
+	if info.Properties["severity"] != "3" {
+		t.Error("interceptor changed the registered service")
+	}
+}
+
+func TestRecordInterceptorVeto(t *testing.T) {
+	m, capture := startTestResponder(t, WithRecordInterceptor(func([]*badezimmer.MDNSRecord) []*badezimmer.MDNSRecord {
+		return nil
+	}))
+	info := testService("Kitchen")
+	addService(m, info)
+
+	ask(m, info.Type)
+	capture.expectNone(t, 200*time.Millisecond)
+}
+
+func TestRegistrationNotBlockedBySlowRenovation(t *testing.T) {
+	clock := newFakeClock()
+	// The first packet, the renovation, hangs until released
+	stalled, release := make(chan struct{}), make(chan struct{})
+	var first atomic.Bool
+	m, _ := startTestResponder(t, WithClock(clock), WithAnnounceInterval(time.Minute),
+		WithRecordInterceptor(func(records []*badezimmer.MDNSRecord) []*badezimmer.MDNSRecord {
+			if first.CompareAndSwap(false, true) {
+				close(stalled)
+				<-release
+			}
+			return records
+		}))
+	t.Cleanup(func() { whileAdvancing(clock, func() { m.Close() }) })
+	t.Cleanup(func() { close(release) })
+	addService(m, testService("Kitchen"))
+	clock.waitForPending(t, 2)
+
+	clock.Advance(time.Minute)
+	<-stalled
+
+	registered := make(chan error, 1)
+	go whileAdvancing(clock, func() { registered <- m.RegisterService(testService("Bathroom")) })
+	select {
+	case err := <-registered:
+		if err != nil {
+			t.Errorf("RegisterService: %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("registration blocked by the renovation in progress")
+	}
+}
+
+func TestRenovationReannounces(t *testing.T) {
+	clock := newFakeClock()
+	m, capture := startTestResponder(t, WithClock(clock))
+	t.Cleanup(func() { whileAdvancing(clock, func() { m.Close() }) })
+	domainName := addService(m, testService("Kitchen"))
+	clock.waitForPending(t, 2)
+
+	interval := time.Duration(float64(DefaultTTL)*0.75) * time.Second
+	clock.Advance(interval - time.Second)
+	capture.expectNone(t, 50*time.Millisecond)
+
+	clock.Advance(time.Second)
+	if got := announcedName(capture.next(t)); got != domainName {
+		t.Errorf("renovation announced %q, want %q", got, domainName)
+	}
+}
+
+func TestAnnounceIntervalDrivesRenovation(t *testing.T) {
+	clock := newFakeClock()
+	interval := 10 * time.Second
+	m, capture := startTestResponder(t, WithClock(clock), WithAnnounceInterval(interval))
+	t.Cleanup(func() { whileAdvancing(clock, func() { m.Close() }) })
+	domainName := addService(m, testService("Kitchen"))
+	clock.waitForPending(t, 2)
+
+	for range 3 {
+		clock.Advance(interval - time.Second)
+		capture.expectNone(t, 50*time.Millisecond)
+
+		clock.Advance(time.Second)
+		if got := announcedName(capture.next(t)); got != domainName {
+			t.Errorf("announced %q, want %q", got, domainName)
+		}
+	}
+}
+
+func TestAnnounceIntervalValidated(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		m := NewBadezimmerMDNS(WithMulticastGroup(testGroup()), WithAnnounceInterval(interval))
+		if err := m.Start(); !errors.Is(err, ErrInvalidAnnounceInterval) {
+			t.Errorf("Start with interval %v = %v, want ErrInvalidAnnounceInterval", interval, err)
+		}
+	}
+
+	logs := captureLog(t)
+	m := NewBadezimmerMDNS(WithMulticastGroup(testGroup()), WithAnnounceInterval(2*DefaultTTL*time.Second))
+	if err := m.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Close()
+	if !logs.contains("exceeds the TTL") {
+		t.Errorf("no warning for an interval longer than the TTL:\n%s", logs)
+	}
+}
+
+func TestUnicastQueryAnsweredByUnicast(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("enabled=%v", enabled), func(t *testing.T) {
+			m, capture := startTestResponder(t, WithUnicastAnswers(enabled))
+			domainName := addService(m, testService("Kitchen"))
+
+			// The querier sends to the responder's own address, not the group
+			querier := newPacketCapture(t)
+			rawBytes, err := prepareProtobufRequest(&badezimmer.MDNS{
+				TransactionId: 1,
+				Data: &badezimmer.MDNS_QueryRequest{QueryRequest: &badezimmer.MDNSQueryRequest{
+					Questions: []*badezimmer.MDNSQuestion{{Name: "_waterleak._tcp.local.", Type: badezimmer.MDNSType_MDNS_PTR}},
+				}},
+			})
+			if err != nil {
+				t.Fatalf("failed to frame query: %v", err)
+			}
+			responder := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: m.LocalAddr().(*net.UDPAddr).Port}
+			if _, err := querier.conn.WriteToUDP(rawBytes, responder); err != nil {
+				t.Fatalf("failed to send query: %v", err)
+			}
+
+			direct, multicast := querier, capture
+			if !enabled {
+				direct, multicast = capture, querier
+			}
+			if got := answeredNames(direct.next(t)); !reflect.DeepEqual(got, []string{domainName}) {
+				t.Errorf("answered %v, want %s", got, domainName)
+			}
+			multicast.expectNone(t, 100*time.Millisecond)
+		})
+	}
+}
+
+func TestMarshallingIsDeterministic(t *testing.T) {
+	info := testService("Kitchen")
+	for i := range 16 {
+		info.Properties[fmt.Sprintf("key%d", i)] = strconv.Itoa(i)
+	}
+	packet := &badezimmer.MDNS{
+		TransactionId: 1,
+		Data: &badezimmer.MDNS_QueryResponse{QueryResponse: &badezimmer.MDNSQueryResponse{
+			Answers: infoToRecords(info),
+		}},
+	}
+
+	first, err := prepareProtobufRequest(packet)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	// Map iteration order changes between runs, so one repeat could match
+	// by chance
+	for range 10 {
+		again, err := prepareProtobufRequest(packet)
+		if err != nil {
+			t.Fatalf("failed to marshal: %v", err)
+		}
+		if !bytes.Equal(again, first) {
+			t.Fatal("the same packet marshalled to different bytes")
+		}
+	}
+}
+
+func TestKindAndCategoryTXTRoundTrip(t *testing.T) {
+	tests := []struct {
+		kind, category string
+		wantKind       badezimmer.DeviceKind
+		wantCategory   badezimmer.DeviceCategory
+	}{
+		{"SENSOR_KIND", "WATER_LEAK", badezimmer.DeviceKind_SENSOR_KIND, badezimmer.DeviceCategory_WATER_LEAK},
+		{"ACTUATOR_KIND", "LIGHT_LAMP", badezimmer.DeviceKind_ACTUATOR_KIND, badezimmer.DeviceCategory_LIGHT_LAMP},
+		{"UNKNOWN_KIND", "TOILET", badezimmer.DeviceKind_UNKNOWN_KIND, badezimmer.DeviceCategory_TOILET},
+		// Values newer than our generated code, as String() renders them
+		{"7", "42", badezimmer.DeviceKind(7), badezimmer.DeviceCategory(42)},
+		// Names newer than our generated code
+		{"HOLOGRAM_KIND", "BIDET", badezimmer.DeviceKind_UNKNOWN_KIND, badezimmer.DeviceCategory_UNKNOWN_CATEGORY},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind+"/"+tt.category, func(t *testing.T) {
+			records := infoToRecords(testService("Kitchen"))
+			for _, record := range records {
+				if txt := record.GetTxtRecord(); txt != nil {
+					txt.Entries["kind"] = tt.kind
+					txt.Entries["category"] = tt.category
+				}
+			}
+
+			info := recordsToInfo(records)
+			if info.Kind != tt.wantKind || info.Category != tt.wantCategory {
+				t.Errorf("parsed %v/%v, want %v/%v", info.Kind, info.Category, tt.wantKind, tt.wantCategory)
+			}
+
+			// Re-advertising the parsed service keeps the original values
+			entries := txtEntries(&badezimmer.MDNS{Data: &badezimmer.MDNS_QueryResponse{
+				QueryResponse: &badezimmer.MDNSQueryResponse{Answers: infoToRecords(info)},
+			}})
+			if entries["kind"] != tt.kind || entries["category"] != tt.category {
+				t.Errorf("re-advertised %s/%s", entries["kind"], entries["category"])
+			}
+		})
+	}
+}
+
+// hostMulticastInterfaces returns the host interfaces, with loopback made to
+// look like a NIC of its own so there are at least two on most hosts.
+func hostMulticastInterfaces(t *testing.T) *fakeAddressProvider {
+	t.Helper()
+	ifaces, err := SystemAddressProvider{}.Interfaces()
+	if err != nil {
+		t.Fatalf("failed to list interfaces: %v", err)
+	}
+	for i := range ifaces {
+		if ifaces[i].Flags&net.FlagLoopback != 0 {
+			ifaces[i].Flags = ifaces[i].Flags&^net.FlagLoopback | net.FlagMulticast
+		}
+	}
+	return newFakeAddressProvider(ifaces...)
+}
+
+func TestAllInterfacesSendsOutOfEach(t *testing.T) {
+	provider := hostMulticastInterfaces(t)
+	ifaces, _ := multicastInterfaces(provider)
+	if len(ifaces) < 2 {
+		t.Skipf("needs two multicast interfaces, found %d", len(ifaces))
+	}
+
+	ip, port := testGroup()
+	m := NewBadezimmerMDNS(WithRandomSeed(1), WithMulticastGroup(ip, port),
+		WithAllInterfaces(true), WithAddressProvider(provider))
+	if err := m.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { m.Close() })
+
+	var joined []string
+	for _, membership := range m.Memberships() {
+		joined = append(joined, membership.Interface)
+	}
+	var want []string
+	for _, iface := range ifaces {
+		want = append(want, iface.name)
+	}
+	if !reflect.DeepEqual(joined, want) {
+		t.Errorf("joined on %v, want %v", joined, want)
+	}
+
+	sent, err := m.writeMulticast(m.conn, []byte("hello"), &net.UDPAddr{IP: net.ParseIP(ip), Port: port})
+	if err != nil {
+		t.Fatalf("writeMulticast: %v", err)
+	}
+	if sent != len(ifaces) {
+		t.Errorf("sent %d copies, want one per interface (%d)", sent, len(ifaces))
+	}
+}
+
+func TestEphemeralServicesNotRenovated(t *testing.T) {
+	clock := newFakeClock()
+	m, capture := startTestResponder(t, WithClock(clock))
+	t.Cleanup(func() { whileAdvancing(clock, func() { m.Close() }) })
+	kitchen := addService(m, testService("Kitchen"))
+	ephemeral := testService("Alert")
+	ephemeral.Ephemeral = true
+	alert := addService(m, ephemeral)
+	clock.waitForPending(t, 2)
+
+	clock.Advance(time.Duration(float64(DefaultTTL)*0.75) * time.Second)
+	if got := announcedName(capture.next(t)); got != kitchen {
+		t.Errorf("renovation announced %q, want %q", got, kitchen)
+	}
+	capture.expectNone(t, 100*time.Millisecond)
+
+	// Still answered until unregistered
+	ask(m, "_waterleak._tcp.local.")
+	if got := answeredNames(capture.next(t)); !slices.Contains(got, alert) {
+		t.Errorf("answered %v, want %s among them", got, alert)
+	}
+}
+
+func TestSRVServiceLabel(t *testing.T) {
+	tests := []struct {
+		name, service string
+		want          string
+	}{
+		{"set explicitly", "_leak", "_leak"},
+		{"parsed from the type", "", "_waterleak"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := testService("Kitchen")
+			info.Service = tt.service
+
+			records := infoToRecords(info)
+			for _, record := range records {
+				if srv := record.GetSrvRecord(); srv != nil && srv.Service != tt.want {
+					t.Errorf("SRV service = %q, want %q", srv.Service, tt.want)
+				}
+			}
+			// Only a label differing from the parsed one is kept on discovery
+			if got := recordsToInfo(records).Service; got != tt.service {
+				t.Errorf("discovered Service = %q, want %q", got, tt.service)
+			}
+		})
+	}
+}
+
+func TestServiceTargetNamesSRVAndA(t *testing.T) {
+	const host = "detector1.lab.example.com."
+	info := testService("Kitchen")
+	info.Target = host
+	info.Addresses = []string{"192.0.2.2", "192.0.2.3"}
+
+	records := infoToRecords(info)
+	var aNames []string
+	for _, record := range records {
+		if srv := record.GetSrvRecord(); srv != nil && srv.Target != host {
+			t.Errorf("SRV target = %q, want %q", srv.Target, host)
+		}
+		if a := record.GetARecord(); a != nil {
+			aNames = append(aNames, record.Name, a.Name)
+		}
+	}
+	if want := []string{host, host, host, host}; !reflect.DeepEqual(aNames, want) {
+		t.Errorf("A records named %v, want %v", aNames, want)
+	}
+
+	parsed := recordsToInfo(records)
+	if parsed.Target != host || !reflect.DeepEqual(parsed.Addresses, info.Addresses) {
+		t.Errorf("parsed target %q with addresses %v", parsed.Target, parsed.Addresses)
+	}
+
+	// Without a target everything stays named for the domain name
+	info.Target = ""
+	if parsed := recordsToInfo(infoToRecords(info)); parsed.Target != "" || len(parsed.Addresses) != 2 {
+		t.Errorf("parsed target %q with addresses %v, want none and both", parsed.Target, parsed.Addresses)
+	}
+}
+
+func TestPrivatePropertiesOnlyForDirectQueries(t *testing.T) {
+	clock := newFakeClock()
+	m, capture := startTestResponder(t, WithClock(clock), WithAggressiveAdditional(true))
+	t.Cleanup(func() { whileAdvancing(clock, func() { m.Close() }) })
+	info := testService("Kitchen")
+	info.Properties["location"] = "kitchen"
+	info.PublicProperties = []string{"severity"}
+	domainName := addService(m, info)
+	clock.waitForPending(t, 2)
+
+	// Announcements keep the location private
+	clock.Advance(time.Duration(float64(DefaultTTL)*0.75) * time.Second)
+	if entries := txtEntries(capture.next(t)); entries["location"] != "" || entries["severity"] != "3" {
+		t.Errorf("renovation announced TXT %v, want severity only", entries)
+	}
+
+	for _, name := range []string{ServiceDiscoveryType, "_waterleak._tcp.local."} {
+		clock.Advance(MulticastAnswerInterval)
+		ask(m, name)
+		entries := txtEntries(capture.next(t))
+		if _, ok := entries["location"]; ok || entries["severity"] != "3" {
+			t.Errorf("query for %s answered with TXT %v, want severity only", name, entries)
+		}
+	}
+
+	clock.Advance(MulticastAnswerInterval)
+	ask(m, domainName)
+	if entries := txtEntries(capture.next(t)); entries["location"] != "kitchen" || entries["severity"] != "3" {
+		t.Errorf("direct query answered with TXT %v, want every property", entries)
+	}
+}
+
+func TestMulticastAnswersRateLimited(t *testing.T) {
+	clock := newFakeClock()
+	m, capture := startTestResponder(t, WithClock(clock), WithUnicastAnswers(true))
+	t.Cleanup(func() { whileAdvancing(clock, func() { m.Close() }) })
+	domainName := addService(m, testService("Kitchen"))
+
+	ask(m, "_waterleak._tcp.local.")
+	if got := answeredNames(capture.next(t)); !reflect.DeepEqual(got, []string{domainName}) {
+		t.Fatalf("answered %v, want %s", got, domainName)
+	}
+
+	// Another querier within the second is not answered by multicast
+	clock.Advance(MulticastAnswerInterval / 2)
+	ask(m, "_waterleak._tcp.local.")
+	capture.expectNone(t, 100*time.Millisecond)
+	if n := m.Stats().RateLimitedAnswers; n != 1 {
+		t.Errorf("RateLimitedAnswers = %d, want 1", n)
+	}
+
+	// but still by unicast
+	querier := newPacketCapture(t)
+	query := &badezimmer.MDNSQueryRequest{Questions: []*badezimmer.MDNSQuestion{
+		{Name: "_waterleak._tcp.local.", Type: badezimmer.MDNSType_MDNS_PTR},
+	}}
+	m.handleQuery(query, querier.addr(), true, time.Time{})
+	if got := answeredNames(querier.next(t)); !reflect.DeepEqual(got, []string{domainName}) {
+		t.Errorf("unicast answered %v, want %s", got, domainName)
+	}
+
+	clock.Advance(MulticastAnswerInterval / 2)
+	ask(m, "_waterleak._tcp.local.")
+	if got := answeredNames(capture.next(t)); !reflect.DeepEqual(got, []string{domainName}) {
+		t.Errorf("answered %v a second later, want %s", got, domainName)
+	}
+}
+
+func TestFlushServiceSendsGoodbyeThenAnnounces(t *testing.T) {
+	clock := newFakeClock()
+	m, capture := startTestResponder(t, WithClock(clock))
+	t.Cleanup(func() { whileAdvancing(clock, func() { m.Close() }) })
+	domainName := addService(m, testService("Kitchen"))
+
+	var err error
+	whileAdvancing(clock, func() { err = m.FlushService(domainName) })
+	if err != nil {
+		t.Fatalf("FlushService: %v", err)
+	}
+
+	for i := range AnnounceBurstCount + 1 {
+		packet := capture.next(t)
+		if got := announcedName(packet); got != domainName {
+			t.Fatalf("packet %d for %q, want %q", i, got, domainName)
+		}
+		goodbye := packet.GetQueryResponse().GetAnswers()[0].Ttl == 0
+		if want := i < AnnounceBurstCount; goodbye != want {
+			t.Errorf("packet %d is a goodbye: %v, want %v", i, goodbye, want)
+		}
+	}
+	if !m.isRegistered(domainName) {
+		t.Error("service unregistered by the flush")
+	}
+
+	if err := m.FlushService("Hallway._waterleak._tcp.local."); !errors.Is(err, ErrServiceNotRegistered) {
+		t.Errorf("FlushService of an unknown service = %v, want ErrServiceNotRegistered", err)
+	}
+}
+
+func TestCloneSharesNothing(t *testing.T) {
+	info := testService("Kitchen")
+	info.PublicProperties = []string{"severity"}
+	clone := info.Clone()
+	if !reflect.DeepEqual(clone, info) {
+		t.Fatalf("clone %+v differs from %+v", clone, info)
+	}
+
+	info.Properties["severity"] = "9"
+	info.Addresses[0] = "192.0.2.9"
+	info.PublicProperties[0] = "location"
+	if clone.Properties["severity"] != "3" || clone.Addresses[0] != "192.0.2.2" || clone.PublicProperties[0] != "severity" {
+		t.Errorf("clone changed with the original: %+v", clone)
+	}
+}
+
+func TestCachedServicesAreCopies(t *testing.T) {
+	m := NewBadezimmerMDNS()
+	announce(m, testService("Kitchen"))
+	domainName := generateDomainName("_waterleak._tcp.local.", "Kitchen")
+
+	// Run with -race: callers writing their copy race with nobody
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 100 {
+				info := m.cache.get(domainName, time.Now())
+				info.Properties["severity"] = strconv.Itoa(i)
+				info.Addresses[0] = "192.0.2.9"
+			}
+		}()
+	}
+	wg.Wait()
+
+	if info := m.cache.get(domainName, time.Now()); info.Properties["severity"] != "3" || info.Addresses[0] != "192.0.2.2" {
+		t.Errorf("cached service changed through a copy: %+v", info)
+	}
+}
+
+func TestLoopbackDiscovery(t *testing.T) {
+	ip, port := testGroup()
+	listener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port})
+	if err != nil {
+		t.Fatalf("failed to listen on the group port: %v", err)
+	}
+	client := &packetCapture{conn: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	m := NewBadezimmerMDNS(WithRandomSeed(1), WithMulticastGroup(ip, port), WithLoopbackDiscovery(true))
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	m.conn = conn
+	t.Cleanup(func() { conn.Close() })
+
+	// Multicast is blocked: every send out of the only interface fails
+	captureLog(t)
+	m.joinedInterfaces = []multicastInterface{{name: "blocked", ip: net.IPv4(192, 0, 2, 1)}}
+
+	info := testService("Kitchen")
+	domainName := addService(m, info)
+	if err := m.broadcastService(context.Background(), info); err == nil {
+		t.Error("multicast announcement succeeded, want it blocked")
+	}
+	if got := announcedName(client.next(t)); got != domainName {
+		t.Errorf("loopback client heard %q, want %q", got, domainName)
+	}
+}
+
+func TestEmptyQueryCounted(t *testing.T) {
+	m, capture := startTestResponder(t)
+	addService(m, testService("Kitchen"))
+
+	ask(m)
+	if n := m.Stats().EmptyQueries; n != 1 {
+		t.Errorf("EmptyQueries = %d, want 1", n)
+	}
+	capture.expectNone(t, 100*time.Millisecond)
+}
+
+func TestResponseRecordsBounded(t *testing.T) {
+	for _, aggressive := range []bool{false, true} {
+		t.Run(fmt.Sprintf("aggressive=%v", aggressive), func(t *testing.T) {
+			logs := captureLog(t)
+			clock := newFakeClock()
+			m, capture := startTestResponder(t, WithClock(clock), WithMaxResponseRecords(10), WithAggressiveAdditional(aggressive))
+			// Saying goodbye to every service takes a while
+			t.Cleanup(func() { whileAdvancing(clock, func() { m.Close() }) })
+			for i := range 20 {
+				addService(m, testService(fmt.Sprintf("Sensor %d", i)))
+			}
+
+			ask(m, "_waterleak._tcp.local.")
+			response := capture.next(t).GetQueryResponse()
+			total := len(response.GetAnswers()) + len(response.GetAdditionalRecords())
+			if total > 10 {
+				t.Errorf("response has %d records, want at most 10", total)
+			}
+			if len(response.GetAnswers()) == 0 {
+				t.Error("response has no answer at all")
+			}
+			if !logs.contains("left out") {
+				t.Error("reaching the limit was not logged")
+			}
+		})
+	}
+}
+
+func TestOwnTransactionIDsSuppressed(t *testing.T) {
+	clock := newFakeClock()
+	m, capture := startTestResponder(t, WithClock(clock))
+	t.Cleanup(func() { whileAdvancing(clock, func() { m.Close() }) })
+	info := testService("Kitchen")
+	addService(m, info)
+	if err := m.broadcastService(context.Background(), info); err != nil {
+		t.Fatalf("broadcastService: %v", err)
+	}
+	ours := capture.next(t).TransactionId
+	clock.Advance(MulticastAnswerInterval)
+
+	// A query is not a packet we sent, only its id gives it away
+	query := func(id uint32) receivedPacket {
+		data, err := prepareProtobufRequest(&badezimmer.MDNS{
+			TransactionId: id,
+			Data: &badezimmer.MDNS_QueryRequest{QueryRequest: &badezimmer.MDNSQueryRequest{
+				Questions: []*badezimmer.MDNSQuestion{{Name: info.Type, Type: badezimmer.MDNSType_MDNS_PTR}},
+			}},
+		})
+		if err != nil {
+			t.Fatalf("failed to frame query: %v", err)
+		}
+		return receivedPacket{data: data, addr: nextQuerier()}
+	}
+
+	m.handlePacket(query(ours))
+	if n := m.Stats().SelfSuppressed; n != 1 {
+		t.Errorf("SelfSuppressed = %d, want 1", n)
+	}
+	capture.expectNone(t, 100*time.Millisecond)
+
+	m.handlePacket(query(ours + 1))
+	if got := answeredNames(capture.next(t)); len(got) != 1 {
+		t.Errorf("query with another id answered %v", got)
+	}
+}
+
+func TestSimultaneousProbesHaveOneWinner(t *testing.T) {
+	ip, port := testGroup()
+	start := func(seed int64) *BadezimmerMDNS {
+		m := NewBadezimmerMDNS(WithRandomSeed(seed), WithMulticastGroup(ip, port))
+		if err := m.Start(); err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+		t.Cleanup(func() { m.Close() })
+		return m
+	}
+	a, b := start(1), start(2)
+
+	// Same name, different records: the set sorting later keeps the name
+	infoA, infoB := testService("Kitchen"), testService("Kitchen")
+	infoB.Port = 9090
+	domainName := generateDomainName(infoA.Type, infoA.Name)
+	winner, loser := infoA, infoB
+	if compareRecordSets(domainName, a.serviceRecords(infoA.public()), b.serviceRecords(infoB.public())) < 0 {
+		winner, loser = infoB, infoA
+	}
+
+	var wg sync.WaitGroup
+	for _, registration := range []struct {
+		m    *BadezimmerMDNS
+		info *MDNSServiceInfo
+	}{{a, infoA}, {b, infoB}} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := registration.m.RegisterService(registration.info); err != nil {
+				t.Errorf("RegisterService: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if winner.Name != "Kitchen" || loser.Name != "Kitchen (2)" {
+		t.Errorf("winner registered %q and loser %q, want the winner to keep the name", winner.Name, loser.Name)
+	}
+}
+
+func TestNamesUnderProbeNotAnswered(t *testing.T) {
+	m, capture := startTestResponder(t)
+	info := testService("Kitchen")
+	domainName := addService(m, info)
+
+	m.probes.start(domainName, nil)
+	ask(m, info.Type)
+	capture.expectNone(t, 100*time.Millisecond)
+
+	// Let the first question out of the retransmission window
+	m.probes.stop(domainName)
+	time.Sleep(QuestionSuppressionWindow)
+	ask(m, info.Type)
+	if got := answeredNames(capture.next(t)); !reflect.DeepEqual(got, []string{domainName}) {
+		t.Errorf("answered %v once the probe ended", got)
+	}
+}
+
+func TestMembershipsReflectJoins(t *testing.T) {
+	clock := newFakeClock()
+	m, _ := startTestResponder(t, WithClock(clock), WithInterface("lo"))
+
+	want := []Membership{{Group: m.groupIP, Port: m.groupPort, Interface: "lo", JoinedAt: clock.Now()}}
+	if got := m.Memberships(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Memberships() = %+v, want %+v", got, want)
+	}
+
+	whileAdvancing(clock, func() { m.Close() })
+	if got := m.Memberships(); len(got) != 0 {
+		t.Errorf("Memberships() = %+v after Close", got)
+	}
+}
+
+func TestBindAddressReceivesOnItsInterface(t *testing.T) {
+	m, _ := startTestResponder(t, WithMulticastBindAddress("127.0.0.1"), WithUnicastAnswers(true))
+	domainName := addService(m, testService("Kitchen"))
+
+	local := m.LocalAddr().(*net.UDPAddr)
+	if !local.IP.Equal(net.IPv4(127, 0, 0, 1)) {
+		t.Errorf("bound to %v, want 127.0.0.1", local)
+	}
+	if got := m.Memberships(); len(got) != 1 || got[0].Interface != "lo" {
+		t.Errorf("Memberships() = %+v, want the group joined on lo", got)
+	}
+
+	querier := newPacketCapture(t)
+	rawBytes, err := prepareProtobufRequest(&badezimmer.MDNS{
+		TransactionId: 1,
+		Data: &badezimmer.MDNS_QueryRequest{QueryRequest: &badezimmer.MDNSQueryRequest{
+			Questions: []*badezimmer.MDNSQuestion{{Name: "_waterleak._tcp.local.", Type: badezimmer.MDNSType_MDNS_PTR}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("failed to frame query: %v", err)
+	}
+	if _, err := querier.conn.WriteToUDP(rawBytes, local); err != nil {
+		t.Fatalf("failed to send query: %v", err)
+	}
+	if got := answeredNames(querier.next(t)); !reflect.DeepEqual(got, []string{domainName}) {
+		t.Errorf("answered %v, want %s", got, domainName)
+	}
+}
+
+func TestBindAddressValidated(t *testing.T) {
+	group, port := testGroup()
+	for _, addr := range []string{"not an address", "192.0.2.77", "239.0.0.1"} {
+		m := NewBadezimmerMDNS(WithMulticastGroup(group, port), WithMulticastBindAddress(addr))
+		if err := m.Start(); err == nil {
+			m.Close()
+			t.Errorf("Start bound to %q succeeded", addr)
+		}
+	}
+}
+
+func TestGossipReannounceOnNewPeer(t *testing.T) {
+	clock := newFakeClock()
+	m, capture := startTestResponder(t, WithClock(clock), WithGossipReannounce(true))
+	t.Cleanup(func() { whileAdvancing(clock, func() { m.Close() }) })
+	domainName := addService(m, testService("Kitchen"))
+
+	reannounced := func(n uint64) {
+		t.Helper()
+		waitFor(t, func() bool {
+			clock.Advance(10 * time.Millisecond)
+			return m.Stats().GossipReannounces == n
+		})
+		if got := announcedName(capture.next(t)); got != domainName {
+			t.Errorf("re-announced %q, want %q", got, domainName)
+		}
+	}
+
+	announce(m, testService("Bathroom"))
+	reannounced(1)
+
+	// Another newcomer right after is throttled
+	announce(m, testService("Hallway"))
+	if n := m.Stats().GossipThrottled; n != 1 {
+		t.Errorf("GossipThrottled = %d, want 1", n)
+	}
+	capture.expectNone(t, 50*time.Millisecond)
+
+	// and one after the interval is not
+	clock.Advance(GossipReannounceInterval)
+	announce(m, testService("Attic"))
+	reannounced(2)
+
+	// Peers of types we do not advertise are no reason to re-announce
+	other := testService("Upstairs")
+	other.Type = "_toilet._tcp.local."
+	clock.Advance(GossipReannounceInterval)
+	announce(m, other)
+	if stats := m.Stats(); stats.GossipReannounces != 2 || stats.GossipThrottled != 1 {
+		t.Errorf("peer of another type changed the gossip stats: %+v", stats)
+	}
+}
+
+func TestResponseTargetCapturesMulticast(t *testing.T) {
+	m, capture := newCapturedResponder(t)
+	records := infoToRecords(testService("Kitchen"))
+	want := &badezimmer.MDNSQueryResponse{Answers: records[:1], AdditionalRecords: records[1:]}
+	if err := m.sendResponse(want); err != nil {
+		t.Fatalf("sendResponse: %v", err)
+	}
+
+	buffer := make([]byte, 65536)
+	capture.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, from, err := capture.conn.ReadFromUDP(buffer)
+	if err != nil {
+		t.Fatalf("nothing captured: %v", err)
+	}
+	if from.Port != m.conn.LocalAddr().(*net.UDPAddr).Port {
+		t.Errorf("captured from %v, want the responder's socket", from)
+	}
+
+	data, err := getProtobufData(buffer[:n])
+	if err != nil {
+		t.Fatalf("captured bytes are not a frame: %v", err)
+	}
+	packet := &badezimmer.MDNS{}
+	if err := proto.Unmarshal(data, packet); err != nil {
+		t.Fatalf("captured bytes do not decode: %v", err)
+	}
+	if got := packet.GetQueryResponse(); !proto.Equal(got, want) {
+		t.Errorf("captured %v, want %v", got, want)
+	}
+}
+
+func TestSharedAddressAdvertisedOnceSentOnEach(t *testing.T) {
+	// Two interfaces both claiming the address of one of them. The kernel
+	// sends from it whatever the interface, as long as it is ours.
+	ifaces, _ := multicastInterfaces(hostMulticastInterfaces(t))
+	if len(ifaces) < 2 {
+		t.Skipf("needs two multicast interfaces, found %d", len(ifaces))
+	}
+	var bridged []InterfaceAddrs
+	for _, iface := range ifaces[:2] {
+		bridged = append(bridged, InterfaceAddrs{
+			Name:  iface.name,
+			Index: iface.index,
+			Flags: net.FlagUp | net.FlagMulticast,
+			IPs:   []net.IP{ifaces[1].ip},
+		})
+	}
+	provider := newFakeAddressProvider(bridged...)
+
+	w := NewWaterLeakDetector(8080, WithDeviceAddressProvider(provider))
+	var aRecords int
+	for _, record := range infoToRecords(w.info) {
+		if record.GetARecord() != nil {
+			aRecords++
+		}
+	}
+	if aRecords != 1 {
+		t.Errorf("%d A records for the shared address, want 1", aRecords)
+	}
+
+	ip, port := testGroup()
+	m := NewBadezimmerMDNS(WithRandomSeed(1), WithMulticastGroup(ip, port),
+		WithAllInterfaces(true), WithAddressProvider(provider))
+	if err := m.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { m.Close() })
+	sent, err := m.writeMulticast(m.conn, []byte("hello"), &net.UDPAddr{IP: net.ParseIP(ip), Port: port})
+	if err != nil {
+		t.Fatalf("writeMulticast: %v", err)
+	}
+	if sent != 2 {
+		t.Errorf("sent %d copies, want one out of each bridged interface", sent)
+	}
+}
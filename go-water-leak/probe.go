@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// A name is claimed after ProbeAttempts queries, ProbeInterval plus up
+	// to ProbeJitter apart, go unanswered
+	ProbeAttempts = 3
+	ProbeInterval = 250 * time.Millisecond
+	ProbeJitter   = 50 * time.Millisecond
+
+	// Give up renaming after this many conflicting candidates
+	MaxRenameAttempts = 16
+)
+
+// CollisionStrategy builds the instance name tried after a name collision.
+// attempt starts at 2 for the first rename.
+type CollisionStrategy func(base string, attempt int) string
+
+// numericSuffixStrategy renames "Detector" to "Detector (2)", "Detector (3)"...
+func numericSuffixStrategy(base string, attempt int) string {
+	return fmt.Sprintf("%s (%d)", base, attempt)
+}
+
+// WithCollisionStrategy sets how a new instance name is derived when the
+// probed name is already taken on the network.
+func WithCollisionStrategy(strategy CollisionStrategy) Option {
+	return func(m *BadezimmerMDNS) {
+		m.collisionStrategy = strategy
+	}
+}
+
+// probe queries the network for the service type and renames info until no
+// other responder announces its domain name. The queries carry the records
+// we propose, so a simultaneous prober of the same name is settled by
+// tie-break; the loser renames as if the name was taken.
+func (m *BadezimmerMDNS) probe(info *MDNSServiceInfo) error {
+	base := info.Name
+	attempt := 1
+
+	var state *probeState
+	domainName := generateDomainName(info.Type, info.Name)
+	defer func() {
+		m.probes.stop(domainName)
+	}()
+
+	for i := 0; i < ProbeAttempts; {
+		lost := state != nil && state.lost.Load()
+		if lost || m.cache.contains(domainName, m.clock.Now()) {
+			attempt++
+			if attempt > MaxRenameAttempts {
+				return fmt.Errorf("no free name for %s after %d attempts", base, MaxRenameAttempts)
+			}
+			candidate := m.collisionStrategy(base, attempt)
+			log.Printf("Name %q is taken, probing %q", info.Name, candidate)
+			info.Name = candidate
+
+			m.probes.stop(domainName)
+			state = nil
+			domainName = generateDomainName(info.Type, info.Name)
+			i = 0
+			continue
+		}
+
+		if state == nil {
+			state = m.probes.start(domainName, m.serviceRecords(info.public()))
+		}
+
+		if err := m.sendProbe(info.Type, state.records); err != nil {
+			return fmt.Errorf("failed to probe %s: %w", info.Name, err)
+		}
+		if err := m.sleep(m.ctx, ProbeInterval+time.Duration(m.randIntn(int(ProbeJitter)))); err != nil {
+			return err
+		}
+		i++
+	}
+
+	return nil
+}
+
+func (m *BadezimmerMDNS) sendQuery(serviceType string) error {
+	return m.sendProbe(serviceType, nil)
+}
+
+// sendProbe queries serviceType, proposing records in the authority section
+// when probing.
+func (m *BadezimmerMDNS) sendProbe(serviceType string, records []*badezimmer.MDNSRecord) error {
+	packet := &badezimmer.MDNS{
+		TransactionId: m.nextTransactionID(),
+		InstanceId:    m.instanceID,
+		Timestamp:     timestamppb.Now(),
+		Data: &badezimmer.MDNS_QueryRequest{
+			QueryRequest: &badezimmer.MDNSQueryRequest{
+				Questions: []*badezimmer.MDNSQuestion{
+					{Name: serviceType, Type: badezimmer.MDNSType_MDNS_PTR},
+				},
+				AuthorityRecords: records,
+			},
+		},
+	}
+
+	return m.sendPacket(packet)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+	"google.golang.org/protobuf/proto"
+)
+
+// probeState is a name we are probing, with the records we propose for it.
+// lost is set when a simultaneous prober wins the tie-break.
+type probeState struct {
+	records []*badezimmer.MDNSRecord
+	lost    atomic.Bool
+}
+
+// probeTracker holds the names under active probe, keyed by domain name.
+type probeTracker struct {
+	mu     sync.Mutex
+	probes map[string]*probeState
+}
+
+func (t *probeTracker) start(domainName string, records []*badezimmer.MDNSRecord) *probeState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.probes == nil {
+		t.probes = make(map[string]*probeState)
+	}
+	state := &probeState{records: records}
+	t.probes[domainName] = state
+	return state
+}
+
+func (t *probeTracker) stop(domainName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.probes, domainName)
+}
+
+func (t *probeTracker) get(domainName string) *probeState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.probes[domainName]
+}
+
+// handleProbeRecords settles simultaneous probes: when a query proposes
+// records for a name we are probing too, the lexicographically later set
+// keeps the name and the other prober picks another one.
+func (m *BadezimmerMDNS) handleProbeRecords(records []*badezimmer.MDNSRecord) {
+	theirs := make(map[string][]*badezimmer.MDNSRecord)
+	for _, record := range records {
+		theirs[record.Name] = append(theirs[record.Name], record)
+	}
+
+	for name, proposed := range theirs {
+		state := m.probes.get(name)
+		if state == nil {
+			continue
+		}
+
+		if compareRecordSets(name, state.records, proposed) < 0 {
+			log.Printf("Lost simultaneous probe tie-break for %s", name)
+			state.lost.Store(true)
+		}
+	}
+}
+
+// compareRecordSets compares our records called name against theirs, both
+// sorted by their deterministic encoding, the way RFC 6762 section 8.2
+// compares the authority sections of two probes.
+func compareRecordSets(name string, ours, theirs []*badezimmer.MDNSRecord) int {
+	a := encodeRecords(ours, name)
+	b := encodeRecords(theirs, name)
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := bytes.Compare(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return len(a) - len(b)
+}
+
+// encodeRecords returns the sorted encodings of the records called name.
+func encodeRecords(records []*badezimmer.MDNSRecord, name string) [][]byte {
+	marshal := proto.MarshalOptions{Deterministic: true}
+
+	var encoded [][]byte
+	for _, record := range records {
+		if record.Name != name {
+			continue
+		}
+		data, err := marshal.Marshal(record)
+		if err != nil {
+			continue
+		}
+		encoded = append(encoded, data)
+	}
+	sort.Slice(encoded, func(i, j int) bool {
+		return bytes.Compare(encoded[i], encoded[j]) < 0
+	})
+	return encoded
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// TXT values are strings on the wire, so binary values are stored base64
+// encoded under keys carrying this prefix.
+const BinaryPropertyPrefix = "b64:"
+
+var (
+	ErrPropertyNotFound      = errors.New("property not found")
+	ErrInvalidBinaryProperty = errors.New("binary property is not base64")
+)
+
+// SetBinaryProperty stores value under key so it survives the TXT string map,
+// e.g. SetBinaryProperty("fingerprint", sum) sets "b64:fingerprint".
+func (info *MDNSServiceInfo) SetBinaryProperty(key string, value []byte) {
+	if info.Properties == nil {
+		info.Properties = make(map[string]string)
+	}
+	info.Properties[BinaryPropertyPrefix+key] = base64.StdEncoding.EncodeToString(value)
+}
+
+// SetBinaryProperty sets a binary TXT property and announces it, like
+// SetProperty.
+func (d *Device) SetBinaryProperty(key string, value []byte) error {
+	return d.SetProperty(BinaryPropertyPrefix+key, base64.StdEncoding.EncodeToString(value))
+}
+
+// checkBinaryProperties refuses binary properties whose value would not
+// decode with BinaryProperty.
+func checkBinaryProperties(props map[string]string) error {
+	for k, v := range props {
+		if !validBinaryProperty(k, v) {
+			return fmt.Errorf("%w: %s", ErrInvalidBinaryProperty, k)
+		}
+	}
+	return nil
+}
+
+// validBinaryProperty reports whether v is valid for k, which is always the
+// case for keys without BinaryPropertyPrefix.
+func validBinaryProperty(k, v string) bool {
+	if !strings.HasPrefix(k, BinaryPropertyPrefix) {
+		return true
+	}
+	_, err := base64.StdEncoding.DecodeString(v)
+	return err == nil
+}
+
+// BinaryProperty returns the value stored with SetBinaryProperty, locally or
+// by the responder a discovered service came from.
+func (info *MDNSServiceInfo) BinaryProperty(key string) ([]byte, error) {
+	encoded, ok := info.Properties[BinaryPropertyPrefix+key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPropertyNotFound, key)
+	}
+
+	value, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid binary property %s: %w", key, err)
+	}
+	return value, nil
+}
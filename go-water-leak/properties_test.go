@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+)
+
+func TestBinaryPropertyRoundTrip(t *testing.T) {
+	fingerprint := []byte{0x00, 0xff, 0x10, '=', 0x80}
+	info := testService("Kitchen")
+	info.SetBinaryProperty("fingerprint", fingerprint)
+
+	// Through the TXT string map as a browser sees it
+	discovered := recordsToInfo(infoToRecords(info))
+	got, err := discovered.BinaryProperty("fingerprint")
+	if err != nil {
+		t.Fatalf("BinaryProperty: %v", err)
+	}
+	if !bytes.Equal(got, fingerprint) {
+		t.Errorf("BinaryProperty = %x, want %x", got, fingerprint)
+	}
+
+	if _, err := discovered.BinaryProperty("missing"); !errors.Is(err, ErrPropertyNotFound) {
+		t.Errorf("missing property err = %v, want ErrPropertyNotFound", err)
+	}
+}
+
+func TestDeviceBinaryProperty(t *testing.T) {
+	fingerprint := []byte{0xde, 0xad, 0x00, 0xbe, 0xef}
+	d := NewDevice(testService("Kitchen"), nil, WithMDNSDisabled())
+	if err := d.SetBinaryProperty("fingerprint", fingerprint); err != nil {
+		t.Fatalf("SetBinaryProperty: %v", err)
+	}
+
+	got, err := d.snapshotInfo().BinaryProperty("fingerprint")
+	if err != nil {
+		t.Fatalf("BinaryProperty: %v", err)
+	}
+	if !bytes.Equal(got, fingerprint) {
+		t.Errorf("BinaryProperty = %x, want %x", got, fingerprint)
+	}
+}
+
+func TestInvalidBinaryPropertyRejected(t *testing.T) {
+	d := NewDevice(testService("Kitchen"), nil)
+	if err := d.SetProperty(BinaryPropertyPrefix+"fingerprint", "not base64!"); !errors.Is(err, ErrInvalidBinaryProperty) {
+		t.Errorf("SetProperty() = %v, want ErrInvalidBinaryProperty", err)
+	}
+
+	// A peer's value that does not decode is left out on discovery
+	info := testService("Kitchen")
+	info.Properties[BinaryPropertyPrefix+"fingerprint"] = "not base64!"
+	if _, ok := recordsToInfo(infoToRecords(info)).Properties[BinaryPropertyPrefix+"fingerprint"]; ok {
+		t.Error("undecodable binary property kept on discovery")
+	}
+}
+
+func TestPropertyBurstAnnouncedOnce(t *testing.T) {
+	clock := newFakeClock()
+	m, capture := startTestResponder(t, WithClock(clock))
+	t.Cleanup(func() { whileAdvancing(clock, func() { m.Close() }) })
+	clock.waitForPending(t, 2)
+
+	// A started device advertising through the test responder
+	d := NewDevice(testService("Kitchen"), nil, WithDeviceClock(clock))
+	t.Cleanup(d.cancel)
+	d.mdns = m
+	domainName := addService(m, d.snapshotInfo())
+	d.started.Store(true)
+
+	for _, change := range []map[string]string{{"severity": "5"}, {"location": "sink"}, {"status": "wet"}} {
+		if err := d.SetProperties(change); err != nil {
+			t.Fatalf("SetProperties(%v): %v", change, err)
+		}
+		// Each change restarts the window before it runs out
+		clock.waitForPending(t, 3)
+		clock.Advance(DefaultAnnounceDebounce / 2)
+	}
+	capture.expectNone(t, 50*time.Millisecond)
+
+	// The window may restart once more for the last change
+	var packet *badezimmer.MDNS
+	for packet == nil {
+		clock.waitForPending(t, 3)
+		clock.Advance(DefaultAnnounceDebounce)
+		packet, _ = capture.read(100 * time.Millisecond)
+	}
+	if got := announcedName(packet); got != domainName {
+		t.Errorf("announced %q, want %q", got, domainName)
+	}
+	entries := txtEntries(packet)
+	if entries["severity"] != "5" || entries["location"] != "sink" || entries["status"] != "wet" {
+		t.Errorf("announced TXT %v, want every change", entries)
+	}
+	capture.expectNone(t, 50*time.Millisecond)
+}
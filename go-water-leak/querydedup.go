@@ -0,0 +1,72 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// Queriers retransmit a question when the answer is slow to arrive; the same
+// question from the same source address and port is answered once per window.
+const QuestionSuppressionWindow = 1 * time.Second
+
+// questionTracker remembers when each (source, question) pair was last
+// answered.
+type questionTracker struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+func newQuestionTracker() *questionTracker {
+	return &questionTracker{seen: make(map[string]time.Time)}
+}
+
+// shouldAnswer reports whether key was not answered within the window, and
+// records it as answered now if so.
+func (t *questionTracker) shouldAnswer(key string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if last, ok := t.seen[key]; ok && now.Sub(last) < QuestionSuppressionWindow {
+		return false
+	}
+
+	// Forget stale pairs so sources that went away don't pile up
+	for k, last := range t.seen {
+		if now.Sub(last) >= QuestionSuppressionWindow {
+			delete(t.seen, k)
+		}
+	}
+
+	t.seen[key] = now
+	return true
+}
+
+// A record is multicast at most once per interval, whoever asks for it, as
+// RFC 6762 section 6 requires. Unicast answers are not limited.
+const MulticastAnswerInterval = 1 * time.Second
+
+// answerTracker remembers when each of our records was last multicast.
+type answerTracker struct {
+	mu   sync.Mutex
+	sent map[string]time.Time
+}
+
+func newAnswerTracker() *answerTracker {
+	return &answerTracker{sent: make(map[string]time.Time)}
+}
+
+// recentlySent reports whether the records of domainName were multicast
+// within the interval before now.
+func (t *answerTracker) recentlySent(domainName string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	last, ok := t.sent[domainName]
+	return ok && now.Sub(last) < MulticastAnswerInterval
+}
+
+func (t *answerTracker) markSent(domainName string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sent[domainName] = now
+}
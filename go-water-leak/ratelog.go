@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// LogRepeatInterval is how often the same error is logged at most, the
+// repeats in between are counted and summarized with the next one.
+const LogRepeatInterval = 10 * time.Second
+
+// logLimiter suppresses repeats of the same log message, e.g. a read error
+// logged by every loop iteration while the network is partitioned.
+type logLimiter struct {
+	mu      sync.Mutex
+	entries map[string]*limitedEntry
+}
+
+type limitedEntry struct {
+	last       time.Time
+	suppressed int
+}
+
+// allow reports whether the message under key may be logged at now and how
+// many repeats were suppressed since it was last logged.
+func (l *logLimiter) allow(key string, now time.Time) (bool, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.entries == nil {
+		l.entries = make(map[string]*limitedEntry)
+	}
+
+	entry, ok := l.entries[key]
+	if !ok {
+		l.entries[key] = &limitedEntry{last: now}
+		return true, 0
+	}
+	if now.Sub(entry.last) < LogRepeatInterval {
+		entry.suppressed++
+		return false, 0
+	}
+
+	suppressed := entry.suppressed
+	entry.last = now
+	entry.suppressed = 0
+	return true, suppressed
+}
+
+// logLimitedf logs like log.Printf, but at most once per LogRepeatInterval
+// for each key.
+func (m *BadezimmerMDNS) logLimitedf(key, format string, args ...any) {
+	ok, suppressed := m.errLog.allow(key, m.clock.Now())
+	if !ok {
+		return
+	}
+
+	msg := fmt.Sprintf(format, args...)
+	if suppressed > 0 {
+		msg = fmt.Sprintf("%s (%d similar messages suppressed)", msg, suppressed)
+	}
+	log.Output(2, msg)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRepeatedLogsRateLimited(t *testing.T) {
+	logs := captureLog(t)
+	clock := newFakeClock()
+	m := NewBadezimmerMDNS(WithClock(clock))
+
+	for range 5 {
+		m.logLimitedf("send", "Failed to send: %s", "network is unreachable")
+		m.logLimitedf("recv", "Failed to read: %s", "timeout")
+	}
+	if n := strings.Count(logs.String(), "Failed to send"); n != 1 {
+		t.Errorf("logged the send error %d times within the interval, want once", n)
+	}
+	if n := strings.Count(logs.String(), "Failed to read"); n != 1 {
+		t.Errorf("logged the read error %d times, want once despite the send errors", n)
+	}
+
+	clock.Advance(LogRepeatInterval)
+	m.logLimitedf("send", "Failed to send: %s", "network is unreachable")
+	if !logs.contains("Failed to send: network is unreachable (4 similar messages suppressed)") {
+		t.Errorf("no summary of the suppressed repeats:\n%s", logs)
+	}
+
+	// Nothing was suppressed since the summary
+	clock.Advance(LogRepeatInterval)
+	m.logLimitedf("send", "Failed to send: %s", "again")
+	if !strings.HasSuffix(strings.TrimSpace(logs.String()), "Failed to send: again") {
+		t.Errorf("last line carries a stale count:\n%s", logs)
+	}
+}
+
+func TestLogLimiterWindow(t *testing.T) {
+	var l logLimiter
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	if ok, _ := l.allow("key", start); !ok {
+		t.Fatal("first message suppressed")
+	}
+	if ok, _ := l.allow("key", start.Add(LogRepeatInterval-time.Nanosecond)); ok {
+		t.Error("repeat within the interval allowed")
+	}
+	if ok, suppressed := l.allow("key", start.Add(LogRepeatInterval)); !ok || suppressed != 1 {
+		t.Errorf("allow after the interval = %v with %d suppressed, want true with 1", ok, suppressed)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"sync"
+)
+
+// Recorded packets are length-prefixed like on the wire, each frame holding
+// a flags octet, the IPv4 peer address and port, then the packet itself
+const (
+	recordFlagSent    = 1 << 0
+	recordFlagUnicast = 1 << 1
+
+	recordHeaderSize = 1 + 4 + 2
+
+	// A header and the largest datagram the receive loop reads
+	maxRecordSize = recordHeaderSize + 65536
+)
+
+// RecordedPacket is a packet read back from a recording.
+type RecordedPacket struct {
+	// Sent is set for packets we sent, Addr is then their destination
+	Sent    bool
+	Unicast bool
+	Addr    *net.UDPAddr
+	Data    []byte
+}
+
+// packetRecorder appends packets to a recording file.
+type packetRecorder struct {
+	mu     sync.Mutex
+	file   *os.File
+	failed bool
+}
+
+// WithRecording appends every packet received and sent to the file at path,
+// for ReplayFile to feed back through a responder offline.
+func WithRecording(path string) Option {
+	return func(m *BadezimmerMDNS) {
+		m.recordingPath = path
+	}
+}
+
+func openRecorder(path string) (*packetRecorder, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	return &packetRecorder{file: file}, nil
+}
+
+// record appends a packet. A failed write is logged once, and the recording
+// stops rather than leaving a torn frame behind.
+func (r *packetRecorder) record(packet RecordedPacket) {
+	if r == nil {
+		return
+	}
+
+	var flags byte
+	if packet.Sent {
+		flags |= recordFlagSent
+	}
+	if packet.Unicast {
+		flags |= recordFlagUnicast
+	}
+
+	frame := make([]byte, 4+recordHeaderSize, 4+recordHeaderSize+len(packet.Data))
+	binary.BigEndian.PutUint32(frame[:4], uint32(recordHeaderSize+len(packet.Data)))
+	frame[4] = flags
+	if packet.Addr != nil {
+		if ip := packet.Addr.IP.To4(); ip != nil {
+			copy(frame[5:9], ip)
+		}
+		binary.BigEndian.PutUint16(frame[9:11], uint16(packet.Addr.Port))
+	}
+	frame = append(frame, packet.Data...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.failed {
+		return
+	}
+	if _, err := r.file.Write(frame); err != nil {
+		r.failed = true
+		log.Printf("Stopping recording after failed write: %v", err)
+	}
+}
+
+func (r *packetRecorder) close() error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// ReplayFile reads the recording at path and hands its packets to handler in
+// the order they were recorded. BadezimmerMDNS.HandleRecorded replays them
+// through a responder.
+func ReplayFile(path string, handler func(RecordedPacket)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	lengthPrefix := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(reader, lengthPrefix); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("%w: truncated length prefix", ErrMalformedFrame)
+		}
+
+		length := binary.BigEndian.Uint32(lengthPrefix)
+		if length < recordHeaderSize || length > maxRecordSize {
+			return fmt.Errorf("%w: recorded frame of %d bytes", ErrMalformedFrame, length)
+		}
+
+		frame := make([]byte, length)
+		if _, err := io.ReadFull(reader, frame); err != nil {
+			return fmt.Errorf("%w: truncated frame", ErrMalformedFrame)
+		}
+
+		handler(RecordedPacket{
+			Sent:    frame[0]&recordFlagSent != 0,
+			Unicast: frame[0]&recordFlagUnicast != 0,
+			Addr: &net.UDPAddr{
+				IP:   net.IPv4(frame[1], frame[2], frame[3], frame[4]),
+				Port: int(binary.BigEndian.Uint16(frame[5:7])),
+			},
+			Data: frame[recordHeaderSize:],
+		})
+	}
+}
+
+// HandleRecorded handles a recorded packet as if it had just been received.
+// Packets we sent are skipped, they were never handled.
+func (m *BadezimmerMDNS) HandleRecorded(packet RecordedPacket) {
+	if packet.Sent {
+		return
+	}
+	m.handlePacket(receivedPacket{data: packet.Data, addr: packet.Addr, unicast: packet.Unicast})
+}
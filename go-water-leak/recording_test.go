@@ -0,0 +1,63 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+)
+
+func TestRecordingReplaysTheSameOutcome(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.rec")
+	m, capture := startTestResponder(t, WithRecording(path))
+	info := testService("Kitchen")
+	addService(m, info)
+
+	// A peer announces itself, then someone asks for our type
+	peer := testService("Bathroom")
+	records := infoToRecords(peer)
+	deliver(t, m, &badezimmer.MDNS{
+		TransactionId: 1,
+		Data: &badezimmer.MDNS_QueryResponse{QueryResponse: &badezimmer.MDNSQueryResponse{
+			Answers: records[:1], AdditionalRecords: records[1:],
+		}},
+	})
+	deliver(t, m, &badezimmer.MDNS{
+		TransactionId: 2,
+		Data: &badezimmer.MDNS_QueryRequest{QueryRequest: &badezimmer.MDNSQueryRequest{
+			Questions: []*badezimmer.MDNSQuestion{{Name: info.Type, Type: badezimmer.MDNSType_MDNS_PTR}},
+		}},
+	})
+	live := answeredNames(capture.next(t))
+	peerName := generateDomainName(peer.Type, peer.Name)
+	waitFor(t, func() bool { return m.cache.contains(peerName, m.clock.Now()) })
+	m.Close()
+
+	var received, sent int
+	if err := ReplayFile(path, func(packet RecordedPacket) {
+		if packet.Sent {
+			sent++
+		} else {
+			received++
+		}
+	}); err != nil {
+		t.Fatalf("ReplayFile: %v", err)
+	}
+	if received != 2 || sent == 0 {
+		t.Errorf("recorded %d received and %d sent packets, want 2 received and some sent", received, sent)
+	}
+
+	// Replayed offline, another responder comes to the same conclusions
+	replayed, replayCapture := newCapturedResponder(t)
+	addService(replayed, testService("Kitchen"))
+	if err := ReplayFile(path, replayed.HandleRecorded); err != nil {
+		t.Fatalf("ReplayFile: %v", err)
+	}
+	if got := answeredNames(replayCapture.next(t)); !reflect.DeepEqual(got, live) {
+		t.Errorf("replay answered %v, live answered %v", got, live)
+	}
+	if !replayed.cache.contains(peerName, replayed.clock.Now()) {
+		t.Error("replay did not cache the peer")
+	}
+}
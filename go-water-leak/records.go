@@ -0,0 +1,198 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+)
+
+var ErrIncompleteRecordSet = errors.New("record set needs at least one PTR and one SRV record")
+
+// RecordSet builds the records announced for a single domain name. Every
+// method returns the set so calls can be chained:
+//
+//	records := NewRecordSet("Leak._waterleak._tcp.local.").
+//		AddPTR("_waterleak._tcp.local.").
+//		AddA("192.168.0.10").
+//		AddSRV(8080, badezimmer.TransportProtocol_TCP_PROTOCOL).
+//		AddTXT(map[string]string{"severity": "low"}).
+//		Build()
+type RecordSet struct {
+	domainName  string
+	serviceType string
+	target      string
+	service     string
+	ttl         int32
+	records     []*badezimmer.MDNSRecord
+}
+
+// NewRecordSet starts an empty record set for domainName using DefaultTTL.
+func NewRecordSet(domainName string) *RecordSet {
+	return &RecordSet{
+		domainName: domainName,
+		ttl:        DefaultTTL,
+	}
+}
+
+// WithTTL sets the TTL of the records added after it.
+func (s *RecordSet) WithTTL(ttl int32) *RecordSet {
+	s.ttl = ttl
+	return s
+}
+
+// WithTarget names the host that the SRV records added after it point at,
+// and the A records after it are named for. An empty target is the domain
+// name itself.
+func (s *RecordSet) WithTarget(target string) *RecordSet {
+	s.target = target
+	return s
+}
+
+// WithService sets the service label of the SRV records added after it. An
+// empty service is parsed from the service type.
+func (s *RecordSet) WithService(service string) *RecordSet {
+	s.service = service
+	return s
+}
+
+// host returns the SRV target and A record name.
+func (s *RecordSet) host() string {
+	if s.target != "" {
+		return s.target
+	}
+	return s.domainName
+}
+
+// AddPTR adds a shared PTR record pointing serviceType at the domain name.
+func (s *RecordSet) AddPTR(serviceType string) *RecordSet {
+	serviceType = normalizeServiceType(serviceType)
+	if s.serviceType == "" {
+		s.serviceType = serviceType
+	}
+
+	s.records = append(s.records, &badezimmer.MDNSRecord{
+		Name:       serviceType,
+		Ttl:        s.ttl,
+		CacheFlush: false,
+		Record: &badezimmer.MDNSRecord_PtrRecord{
+			PtrRecord: &badezimmer.MDNSPointerRecord{
+				Name:       serviceType,
+				DomainName: s.domainName,
+			},
+		},
+	})
+	return s
+}
+
+// AddA adds an address record for ip, named for the host.
+func (s *RecordSet) AddA(ip string) *RecordSet {
+	s.records = append(s.records, &badezimmer.MDNSRecord{
+		Name:       s.host(),
+		Ttl:        s.ttl,
+		CacheFlush: true,
+		Record: &badezimmer.MDNSRecord_ARecord{
+			ARecord: &badezimmer.MDNSARecord{
+				Name:    s.host(),
+				Address: ip,
+			},
+		},
+	})
+	return s
+}
+
+// AddSRV adds a service record for port targeting the host. The
+// instance and service labels come from the first PTR record added, or from
+// the domain name itself when there is none, unless set with WithService.
+func (s *RecordSet) AddSRV(port int32, protocol badezimmer.TransportProtocol) *RecordSet {
+	instance, serviceType := s.splitDomainName()
+
+	service := s.service
+	if service == "" {
+		service = serviceLabel(serviceType)
+	}
+
+	s.records = append(s.records, &badezimmer.MDNSRecord{
+		Name:       s.domainName,
+		Ttl:        s.ttl,
+		CacheFlush: true,
+		Record: &badezimmer.MDNSRecord_SrvRecord{
+			SrvRecord: &badezimmer.MDNSSRVRecord{
+				Name:     instance,
+				Protocol: protocol,
+				Service:  service,
+				Instance: instance,
+				Port:     port,
+				Target:   s.host(),
+			},
+		},
+	})
+	return s
+}
+
+// serviceLabel returns the service label of serviceType, "_http" when it
+// cannot be parsed.
+func serviceLabel(serviceType string) string {
+	if parts := splitServiceType(serviceType); len(parts) > 0 {
+		return parts[0]
+	}
+	return "_http"
+}
+
+// AddTXT adds a text record holding a copy of entries.
+func (s *RecordSet) AddTXT(entries map[string]string) *RecordSet {
+	copied := make(map[string]string, len(entries))
+	for k, v := range entries {
+		copied[k] = v
+	}
+
+	s.records = append(s.records, &badezimmer.MDNSRecord{
+		Name:       s.domainName,
+		Ttl:        s.ttl,
+		CacheFlush: true,
+		Record: &badezimmer.MDNSRecord_TxtRecord{
+			TxtRecord: &badezimmer.MDNSTextRecord{
+				Name:    s.domainName,
+				Entries: copied,
+			},
+		},
+	})
+	return s
+}
+
+// Validate reports whether the set can be resolved by a browser, which
+// needs a PTR to find the service and an SRV to reach it.
+func (s *RecordSet) Validate() error {
+	var hasPTR, hasSRV bool
+	for _, record := range s.records {
+		switch record.Record.(type) {
+		case *badezimmer.MDNSRecord_PtrRecord:
+			hasPTR = true
+		case *badezimmer.MDNSRecord_SrvRecord:
+			hasSRV = true
+		}
+	}
+
+	if !hasPTR || !hasSRV {
+		return ErrIncompleteRecordSet
+	}
+	return nil
+}
+
+// Build returns the records added so far, in insertion order.
+func (s *RecordSet) Build() []*badezimmer.MDNSRecord {
+	records := make([]*badezimmer.MDNSRecord, len(s.records))
+	copy(records, s.records)
+	return records
+}
+
+func (s *RecordSet) splitDomainName() (instance, serviceType string) {
+	if s.serviceType != "" {
+		if instance, ok := strings.CutSuffix(s.domainName, "."+s.serviceType); ok {
+			return instance, s.serviceType
+		}
+	}
+
+	instance, serviceType, _ = strings.Cut(s.domainName, ".")
+	return instance, serviceType
+}
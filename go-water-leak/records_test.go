@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+)
+
+const testDomainName = "Leak._waterleak._tcp.local."
+
+func TestRecordSetBuildsEachRecord(t *testing.T) {
+	records := NewRecordSet(testDomainName).
+		WithTTL(120).
+		AddPTR("_waterleak._tcp.local").
+		AddA("192.0.2.2").
+		AddSRV(8080, badezimmer.TransportProtocol_TCP_PROTOCOL).
+		AddSRV(8081, badezimmer.TransportProtocol_UDP_PROTOCOL).
+		AddTXT(map[string]string{"severity": "3"}).
+		AddTXT(map[string]string{"location": "bathroom"}).
+		Build()
+	if len(records) != 6 {
+		t.Fatalf("got %d records, want 6", len(records))
+	}
+	for _, record := range records {
+		if record.Ttl != 120 {
+			t.Errorf("%s has TTL %d, want 120", record.Name, record.Ttl)
+		}
+	}
+
+	ptr := records[0].GetPtrRecord()
+	if records[0].Name != "_waterleak._tcp.local." || ptr.GetDomainName() != testDomainName || records[0].CacheFlush {
+		t.Errorf("PTR = %v", records[0])
+	}
+
+	a := records[1].GetARecord()
+	if records[1].Name != testDomainName || a.GetAddress() != "192.0.2.2" {
+		t.Errorf("A = %v", records[1])
+	}
+
+	for i, port := range []int32{8080, 8081} {
+		srv := records[2+i].GetSrvRecord()
+		if srv.GetPort() != port || srv.GetInstance() != "Leak" || srv.GetService() != "_waterleak" || srv.GetTarget() != testDomainName {
+			t.Errorf("SRV %d = %v", i, records[2+i])
+		}
+	}
+
+	if txt := records[4].GetTxtRecord(); txt.GetEntries()["severity"] != "3" {
+		t.Errorf("first TXT = %v", records[4])
+	}
+	if txt := records[5].GetTxtRecord(); txt.GetEntries()["location"] != "bathroom" {
+		t.Errorf("second TXT = %v", records[5])
+	}
+}
+
+func TestRecordSetTarget(t *testing.T) {
+	records := NewRecordSet(testDomainName).
+		AddPTR("_waterleak._tcp.local.").
+		WithTarget("sensor.local.").
+		AddA("192.0.2.2").
+		AddSRV(8080, badezimmer.TransportProtocol_TCP_PROTOCOL).
+		Build()
+
+	if records[1].Name != "sensor.local." {
+		t.Errorf("A record named %q, want the target", records[1].Name)
+	}
+	if target := records[2].GetSrvRecord().GetTarget(); target != "sensor.local." {
+		t.Errorf("SRV target = %q, want the target", target)
+	}
+}
+
+func TestRecordSetTXTIsCopied(t *testing.T) {
+	entries := map[string]string{"severity": "3"}
+	set := NewRecordSet(testDomainName).AddTXT(entries)
+	entries["severity"] = "5"
+
+	if got := set.Build()[0].GetTxtRecord().GetEntries()["severity"]; got != "3" {
+		t.Errorf("TXT severity = %q after changing the caller's map", got)
+	}
+}
+
+func TestRecordSetValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		set  *RecordSet
+		want error
+	}{
+		{"complete", NewRecordSet(testDomainName).AddPTR("_waterleak._tcp.local.").AddSRV(8080, badezimmer.TransportProtocol_TCP_PROTOCOL), nil},
+		{"empty", NewRecordSet(testDomainName), ErrIncompleteRecordSet},
+		{"no SRV", NewRecordSet(testDomainName).AddPTR("_waterleak._tcp.local.").AddA("192.0.2.2"), ErrIncompleteRecordSet},
+		{"no PTR", NewRecordSet(testDomainName).AddSRV(8080, badezimmer.TransportProtocol_TCP_PROTOCOL), ErrIncompleteRecordSet},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.set.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+	"google.golang.org/protobuf/proto"
+)
+
+// ServiceDiff names the services changed by ReplaceServices, by domain name
+// in sorted order.
+type ServiceDiff struct {
+	Added     []string
+	Removed   []string
+	Updated   []string
+	Unchanged []string
+}
+
+// ReplaceServices makes infos the registered services in a single step, so
+// queries never see a mix of the old and new set. Removed services send a
+// goodbye, added and changed ones are announced and unchanged ones are left
+// alone. Added services are not probed. Nothing changes when one of infos is
+// invalid.
+func (m *BadezimmerMDNS) ReplaceServices(infos []*MDNSServiceInfo) (ServiceDiff, error) {
+	var diff ServiceDiff
+
+	desired := make(map[string]*MDNSServiceInfo, len(infos))
+	for _, info := range infos {
+		if err := info.Validate(); err != nil {
+			return diff, fmt.Errorf("invalid service %s: %w", info.Name, err)
+		}
+		if err := m.checkTXTEntries(info.Properties); err != nil {
+			return diff, fmt.Errorf("invalid service %s: %w", info.Name, err)
+		}
+
+		info = info.Clone()
+		info.Type = normalizeServiceType(info.Type)
+		domainName := generateDomainName(info.Type, info.Name)
+		if _, exists := desired[domainName]; exists {
+			return diff, fmt.Errorf("%w: %s given twice", ErrAlreadyRegistered, domainName)
+		}
+		desired[domainName] = info
+	}
+	if len(desired) > m.maxRegisteredServices {
+		return diff, fmt.Errorf("%w: limit is %d", ErrTooManyServices, m.maxRegisteredServices)
+	}
+
+	var removed, announced []*MDNSServiceInfo
+	var events []ServiceEvent
+
+	m.mu.Lock()
+	for domainName, current := range m.registeredServices {
+		if _, ok := desired[domainName]; !ok {
+			delete(m.registeredServices, domainName)
+			removed = append(removed, current)
+			diff.Removed = append(diff.Removed, domainName)
+			events = append(events, ServiceEvent{Type: ServiceUnregistered, Info: current})
+		}
+	}
+	for domainName, info := range desired {
+		current, ok := m.registeredServices[domainName]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, domainName)
+			events = append(events, ServiceEvent{Type: ServiceRegistered, Info: info})
+		case sameService(current, info):
+			diff.Unchanged = append(diff.Unchanged, domainName)
+			continue
+		default:
+			diff.Updated = append(diff.Updated, domainName)
+			events = append(events, ServiceEvent{Type: ServiceUpdated, Info: info})
+		}
+		m.registeredServices[domainName] = info
+		announced = append(announced, info)
+	}
+	m.mu.Unlock()
+
+	for _, names := range [][]string{diff.Added, diff.Removed, diff.Updated, diff.Unchanged} {
+		sort.Strings(names)
+	}
+
+	log.Printf("Replaced services: %d added, %d removed, %d updated, %d unchanged",
+		len(diff.Added), len(diff.Removed), len(diff.Updated), len(diff.Unchanged))
+
+	for _, evt := range events {
+		m.notifyServiceEvent(evt.Type, evt.Info)
+	}
+
+	var errs []error
+	for _, info := range removed {
+		if err := m.sendGoodbye(m.ctx, info); err != nil {
+			errs = append(errs, fmt.Errorf("goodbye for %s: %w", info.Name, err))
+		}
+	}
+	for _, info := range announced {
+		if err := m.announceService(m.ctx, info); err != nil {
+			errs = append(errs, fmt.Errorf("announcing %s: %w", info.Name, err))
+		}
+	}
+	return diff, errors.Join(errs...)
+}
+
+// sameService reports whether a and b announce the same records, so a nil
+// and an empty Properties or Addresses count as equal.
+func sameService(a, b *MDNSServiceInfo) bool {
+	if a.Ephemeral != b.Ephemeral || !slices.Equal(a.PublicProperties, b.PublicProperties) {
+		return false
+	}
+	return slices.EqualFunc(infoToRecords(a), infoToRecords(b), func(x, y *badezimmer.MDNSRecord) bool {
+		return proto.Equal(x, y)
+	})
+}
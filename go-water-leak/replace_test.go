@@ -0,0 +1,101 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestReplaceServicesAppliesTheDiff(t *testing.T) {
+	clock := newFakeClock()
+	m, capture := startTestResponder(t, WithClock(clock))
+	t.Cleanup(func() { whileAdvancing(clock, func() { m.Close() }) })
+	kitchen := addService(m, testService("Kitchen"))
+	bathroom := addService(m, testService("Bathroom"))
+	garage := addService(m, testService("Garage"))
+
+	moved := testService("Garage")
+	moved.Port = 9090
+	hallway := testService("Hallway")
+	var diff ServiceDiff
+	var err error
+	whileAdvancing(clock, func() {
+		diff, err = m.ReplaceServices([]*MDNSServiceInfo{testService("Bathroom"), moved, hallway})
+	})
+	if err != nil {
+		t.Fatalf("ReplaceServices: %v", err)
+	}
+
+	want := ServiceDiff{
+		Added:     []string{generateDomainName(hallway.Type, hallway.Name)},
+		Removed:   []string{kitchen},
+		Updated:   []string{garage},
+		Unchanged: []string{bathroom},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("diff = %+v, want %+v", diff, want)
+	}
+
+	// The goodbyes for the removed service come first
+	for i := range AnnounceBurstCount {
+		packet := capture.next(t)
+		if got := announcedName(packet); got != kitchen || packet.GetQueryResponse().GetAnswers()[0].Ttl != 0 {
+			t.Fatalf("packet %d announced %q, want a goodbye for %q", i, got, kitchen)
+		}
+	}
+	var announced []string
+	for range 2 {
+		packet := capture.next(t)
+		if packet.GetQueryResponse().GetAnswers()[0].Ttl == 0 {
+			t.Errorf("goodbye for %s", announcedName(packet))
+		}
+		announced = append(announced, announcedName(packet))
+	}
+	sort.Strings(announced)
+	if want := []string{garage, want.Added[0]}; !reflect.DeepEqual(announced, want) {
+		t.Errorf("announced %v, want %v", announced, want)
+	}
+	capture.expectNone(t, 100*time.Millisecond)
+
+	var registered []string
+	for _, info := range m.snapshotServices() {
+		registered = append(registered, info.Name)
+	}
+	sort.Strings(registered)
+	if want := []string{"Bathroom", "Garage", "Hallway"}; !reflect.DeepEqual(registered, want) {
+		t.Errorf("registered %v, want %v", registered, want)
+	}
+}
+
+func TestReplaceServicesIgnoresNilVersusEmpty(t *testing.T) {
+	clock := newFakeClock()
+	m, capture := startTestResponder(t, WithClock(clock))
+	t.Cleanup(func() { whileAdvancing(clock, func() { m.Close() }) })
+	registered := testService("Kitchen")
+	registered.Properties = nil
+	kitchen := addService(m, registered)
+	bathroom := addService(m, testService("Bathroom"))
+
+	same := testService("Kitchen")
+	same.Properties = map[string]string{}
+	moved := testService("Bathroom")
+	moved.Port = 9090
+	var diff ServiceDiff
+	var err error
+	whileAdvancing(clock, func() {
+		diff, err = m.ReplaceServices([]*MDNSServiceInfo{same, moved})
+	})
+	if err != nil {
+		t.Fatalf("ReplaceServices: %v", err)
+	}
+
+	want := ServiceDiff{Updated: []string{bathroom}, Unchanged: []string{kitchen}}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("diff = %+v, want %+v", diff, want)
+	}
+	if got := announcedName(capture.next(t)); got != bathroom {
+		t.Errorf("announced %q, want %q", got, bathroom)
+	}
+	capture.expectNone(t, 100*time.Millisecond)
+}
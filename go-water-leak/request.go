@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+)
+
+type requestIDKey struct{}
+
+func withRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+type headersKey struct{}
+
+// ContextWithHeaders attaches request headers, e.g. a trace id or an auth
+// token, to ctx. Clients send them with each request, and handlers find the
+// headers of the request they answer in their context.
+func ContextWithHeaders(ctx context.Context, headers map[string]string) context.Context {
+	return context.WithValue(ctx, headersKey{}, headers)
+}
+
+// HeadersFromContext returns the headers attached to ctx, or nil.
+func HeadersFromContext(ctx context.Context) map[string]string {
+	headers, _ := ctx.Value(headersKey{}).(map[string]string)
+	return headers
+}
+
+// correlate marks response as the answer to request: it carries the request
+// id and echoes the request headers, e.g. a trace id, except the auth token.
+func correlate(response *badezimmer.BadezimmerResponse, request *badezimmer.BadezimmerRequest) {
+	response.RequestId = request.RequestId
+	response.Headers = echoedHeaders(request.Headers)
+}
+
+// echoedHeaders returns the headers sent back on responses, nil if none.
+func echoedHeaders(headers map[string]string) map[string]string {
+	var echoed map[string]string
+	for k, v := range headers {
+		if k == AuthTokenHeader {
+			continue
+		}
+		if echoed == nil {
+			echoed = make(map[string]string, len(headers))
+		}
+		echoed[k] = v
+	}
+	return echoed
+}
+
+// newRequestID generates an id for requests that did not carry one.
+func newRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// logRequestf logs a line prefixed with the request id from ctx.
+func logRequestf(ctx context.Context, format string, args ...any) {
+	log.Output(2, fmt.Sprintf("[req %s] ", requestIDFromContext(ctx))+fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,56 @@
+package main
+
+import "sync"
+
+// sentTransactionsSize is how many of our latest transaction ids are
+// remembered, enough for the packets still in flight on the group.
+const sentTransactionsSize = 256
+
+// sentTransactions remembers the ids of the packets we sent recently, to
+// recognize them when the group loops them back. Unlike the packet bytes,
+// the id survives re-encoding and timestamp changes.
+type sentTransactions struct {
+	mu   sync.Mutex
+	ids  map[uint32]int
+	ring []uint32
+	next int
+}
+
+func (s *sentTransactions) add(id uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ids == nil {
+		s.ids = make(map[uint32]int, sentTransactionsSize)
+		s.ring = make([]uint32, 0, sentTransactionsSize)
+	}
+
+	// Evict the oldest id once the ring is full
+	if len(s.ring) == sentTransactionsSize {
+		evicted := s.ring[s.next]
+		if s.ids[evicted]--; s.ids[evicted] == 0 {
+			delete(s.ids, evicted)
+		}
+		s.ring[s.next] = id
+		s.next = (s.next + 1) % sentTransactionsSize
+	} else {
+		s.ring = append(s.ring, id)
+	}
+	s.ids[id]++
+}
+
+func (s *sentTransactions) contains(id uint32) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ids[id] > 0
+}
+
+// reset forgets the ids sent on a previous socket.
+func (s *sentTransactions) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ids = nil
+	s.ring = nil
+	s.next = 0
+}
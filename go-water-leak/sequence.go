@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strconv"
+	"sync"
+)
+
+// SequenceProperty is the TXT entry carrying the sequence number of an
+// announcement, see WithSequenceNumbers.
+const SequenceProperty = "seq"
+
+// WithSequenceNumbers adds a "seq" TXT entry to each service, incremented by
+// every announcement of it, including updates and renovations. Clients
+// receiving several announcements keep the one with the highest, which
+// unlike timestamps does not depend on the clocks of the hosts agreeing.
+func WithSequenceNumbers(enabled bool) Option {
+	return func(m *BadezimmerMDNS) {
+		m.sequenceNumbers = enabled
+	}
+}
+
+// sequenceTracker holds the announcement sequence number of each service,
+// by domain name. Numbers survive unregistering, so a service registered
+// again does not go back to older ones.
+type sequenceTracker struct {
+	mu   sync.Mutex
+	seqs map[string]uint64
+}
+
+// next increments and returns the sequence number of domainName.
+func (t *sequenceTracker) next(domainName string) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.seqs == nil {
+		t.seqs = make(map[string]uint64)
+	}
+	t.seqs[domainName]++
+	return t.seqs[domainName]
+}
+
+func (t *sequenceTracker) current(domainName string) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.seqs[domainName]
+}
+
+// sequenceEntry returns the TXT value of the current sequence number.
+func (m *BadezimmerMDNS) sequenceEntry(info *MDNSServiceInfo) string {
+	return strconv.FormatUint(m.sequences.current(generateDomainName(info.Type, info.Name)), 10)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Leak severities range from no leak to a flood
+const (
+	MinSeverity = 0
+	MaxSeverity = 10
+)
+
+var ErrInvalidSeverity = errors.New("severity must be between 0 and 10")
+
+// setSeverityLocked validates and stores severity. It reaches the TXT
+// properties once rendered by renderSeverityLocked. The caller must hold
+// propsMu.
+func (w *WaterLeakDetector) setSeverityLocked(severity int) error {
+	if severity < MinSeverity || severity > MaxSeverity {
+		return fmt.Errorf("%w, got %d", ErrInvalidSeverity, severity)
+	}
+	w.severity = severity
+	return nil
+}
+
+// renderSeverityLocked writes the severity into the TXT properties, where
+// it is a string, before they are published. The caller must hold propsMu.
+func (w *WaterLeakDetector) renderSeverityLocked() {
+	w.info.Properties["severity"] = strconv.Itoa(w.severity)
+}
+
+// CurrentSeverity returns the severity the detector currently advertises.
+func (w *WaterLeakDetector) CurrentSeverity() int {
+	w.propsMu.RLock()
+	defer w.propsMu.RUnlock()
+	return w.severity
+}
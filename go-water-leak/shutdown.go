@@ -0,0 +1,35 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"os"
+)
+
+// ForcedExitCode is the exit status when a second signal cuts the graceful
+// shutdown short.
+const ForcedExitCode = 1
+
+var ErrForcedShutdown = errors.New("shutdown forced by a second signal")
+
+// awaitShutdown waits for a signal, then runs the graceful stop. A second
+// signal arriving before stop returns calls exit right away, like container
+// runtimes expect after their grace period.
+func awaitShutdown(signals <-chan os.Signal, stop func() error, exit func(code int)) error {
+	sig := <-signals
+	log.Printf("Received %v, shutting down gracefully, signal again to exit immediately", sig)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- stop()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case sig := <-signals:
+		log.Printf("Received %v during shutdown, exiting immediately", sig)
+		exit(ForcedExitCode)
+		return ErrForcedShutdown
+	}
+}
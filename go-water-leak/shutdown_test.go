@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestSecondSignalForcesExit(t *testing.T) {
+	captureLog(t)
+	signals := make(chan os.Signal, 2)
+	signals <- syscall.SIGTERM
+
+	// The graceful stop never finishes on its own
+	stopping := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	stop := func() error {
+		close(stopping)
+		<-release
+		return nil
+	}
+	exitCode := -1
+	exit := func(code int) { exitCode = code }
+
+	done := make(chan error, 1)
+	go func() { done <- awaitShutdown(signals, stop, exit) }()
+	<-stopping
+	signals <- os.Interrupt
+
+	if err := <-done; !errors.Is(err, ErrForcedShutdown) {
+		t.Errorf("awaitShutdown = %v, want ErrForcedShutdown", err)
+	}
+	if exitCode != ForcedExitCode {
+		t.Errorf("exit code %d, want %d", exitCode, ForcedExitCode)
+	}
+}
+
+func TestSingleSignalStopsGracefully(t *testing.T) {
+	captureLog(t)
+	signals := make(chan os.Signal, 2)
+	signals <- os.Interrupt
+
+	stopErr := errors.New("stop failed")
+	exited := false
+	err := awaitShutdown(signals, func() error { return stopErr }, func(int) { exited = true })
+	if !errors.Is(err, stopErr) {
+		t.Errorf("awaitShutdown = %v, want the stop error", err)
+	}
+	if exited {
+		t.Error("exited without a second signal")
+	}
+}
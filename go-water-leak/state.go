@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HealthPortProperty is the TXT entry advertising the port of the state
+// server, so dashboards discovering the device can scrape it.
+const HealthPortProperty = "health_port"
+
+// DetectorState is a point-in-time snapshot of the device, served as JSON
+// on /state when the state server is enabled.
+type DetectorState struct {
+	Name          string            `json:"name"`
+	Type          string            `json:"type"`
+	Port          int32             `json:"port"`
+	Properties    map[string]string `json:"properties"`
+	MDNSEnabled   bool              `json:"mdns_enabled"`
+	Maintenance   bool              `json:"maintenance"`
+	MulticastAddr string            `json:"multicast_addr,omitempty"`
+	Memberships   []Membership      `json:"memberships"`
+	Stats         Stats             `json:"stats"`
+	Cache         CacheState        `json:"cache"`
+}
+
+// CacheState summarizes the discovered services cache.
+type CacheState struct {
+	Entries int        `json:"entries"`
+	Oldest  *time.Time `json:"oldest,omitempty"`
+	Newest  *time.Time `json:"newest,omitempty"`
+}
+
+func (d *Device) State() DetectorState {
+	info := d.snapshotInfo()
+	state := DetectorState{
+		Name:        info.Name,
+		Type:        info.Type,
+		Port:        info.Port,
+		Properties:  info.Properties,
+		MDNSEnabled: !d.mdnsDisabled,
+		Maintenance: d.mdns.InMaintenance(),
+		Memberships: d.mdns.Memberships(),
+		Stats:       d.Stats(),
+	}
+	if state.Properties == nil {
+		state.Properties = make(map[string]string)
+	}
+
+	entries, oldest, newest := d.mdns.CacheStats()
+	state.Cache.Entries = entries
+	if entries > 0 {
+		state.Cache.Oldest = &oldest
+		state.Cache.Newest = &newest
+	}
+
+	if addr := d.mdns.LocalAddr(); addr != nil {
+		state.MulticastAddr = addr.String()
+	}
+
+	return state
+}
+
+func (d *Device) startStateServer() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/state", d.handleState)
+
+	d.stateServer = &http.Server{
+		Addr:    d.stateAddr,
+		Handler: mux,
+	}
+
+	listener, err := net.Listen("tcp", d.stateAddr)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Serving detector state on http://%s/state", listener.Addr())
+
+	// The address may name port 0, the bound port is the one to advertise
+	d.propsMu.Lock()
+	if d.info.Properties == nil {
+		d.info.Properties = make(map[string]string)
+	}
+	d.info.Properties[HealthPortProperty] = strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+	d.propsMu.Unlock()
+
+	go func() {
+		if err := d.stateServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("Error serving state: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+func (d *Device) closeStateServer() {
+	if d.stateServer == nil {
+		return
+	}
+	if err := d.stateServer.Close(); err != nil {
+		log.Printf("Error closing state server: %v", err)
+	}
+}
+
+func (d *Device) handleState(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(d.State()); err != nil {
+		log.Printf("Error encoding state: %v", err)
+	}
+}
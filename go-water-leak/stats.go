@@ -0,0 +1,95 @@
+package main
+
+import "sync/atomic"
+
+// Stats is a snapshot of the runtime counters. It has no dependencies so it
+// can be read by embedded users without a metrics library.
+type Stats struct {
+	PacketsSent       uint64 `json:"packets_sent"`
+	PacketsReceived   uint64 `json:"packets_received"`
+	SelfSuppressed    uint64 `json:"self_suppressed"`
+	SiblingSuppressed uint64 `json:"sibling_suppressed"`
+	PacketsDropped    uint64 `json:"packets_dropped"`
+	SendFailures      uint64 `json:"send_failures"`
+	BreakerOpen       bool   `json:"breaker_open"`
+	ResponsesSent     uint64 `json:"responses_sent"`
+	RenovationCycles  uint64 `json:"renovation_cycles"`
+	FramingErrors     uint64 `json:"framing_errors"`
+	ContentErrors     uint64 `json:"content_errors"`
+	// Retransmitted questions left unanswered, see QuestionSuppressionWindow
+	DuplicateQuestions uint64 `json:"duplicate_questions"`
+	GoodbyesSent       uint64 `json:"goodbyes_sent"`
+	ActiveConnections  int64  `json:"active_connections"`
+
+	// Queries received without any question
+	EmptyQueries uint64 `json:"empty_queries"`
+
+	// Services left out of multicast answers, see MulticastAnswerInterval
+	RateLimitedAnswers uint64 `json:"rate_limited_answers"`
+
+	// Re-announces triggered by new peers, and those skipped by throttling
+	GossipReannounces uint64 `json:"gossip_reannounces"`
+	GossipThrottled   uint64 `json:"gossip_throttled"`
+
+	// Malformed frames and messages received on the TCP control channel
+	RequestFramingErrors uint64 `json:"request_framing_errors"`
+	RequestContentErrors uint64 `json:"request_content_errors"`
+
+	// Time from receiving a query to having sent its response
+	ResponseLatency LatencyHistogram `json:"response_latency"`
+}
+
+type mdnsCounters struct {
+	packetsSent       atomic.Uint64
+	packetsReceived   atomic.Uint64
+	selfSuppressed    atomic.Uint64
+	siblingSuppressed atomic.Uint64
+	packetsDropped    atomic.Uint64
+	sendFailures      atomic.Uint64
+	responsesSent     atomic.Uint64
+	renovationCycles  atomic.Uint64
+	framingErrors     atomic.Uint64
+	contentErrors     atomic.Uint64
+
+	duplicateQuestions atomic.Uint64
+	goodbyesSent       atomic.Uint64
+	emptyQueries       atomic.Uint64
+	gossipReannounces  atomic.Uint64
+	gossipThrottled    atomic.Uint64
+	rateLimitedAnswers atomic.Uint64
+
+	responseLatency latencyHistogram
+}
+
+// Stats returns the current mDNS counters.
+func (m *BadezimmerMDNS) Stats() Stats {
+	return Stats{
+		PacketsSent:        m.counters.packetsSent.Load(),
+		PacketsReceived:    m.counters.packetsReceived.Load(),
+		SelfSuppressed:     m.counters.selfSuppressed.Load(),
+		SiblingSuppressed:  m.counters.siblingSuppressed.Load(),
+		PacketsDropped:     m.counters.packetsDropped.Load(),
+		SendFailures:       m.counters.sendFailures.Load(),
+		BreakerOpen:        m.breaker.isOpen(),
+		ResponsesSent:      m.counters.responsesSent.Load(),
+		RenovationCycles:   m.counters.renovationCycles.Load(),
+		FramingErrors:      m.counters.framingErrors.Load(),
+		ContentErrors:      m.counters.contentErrors.Load(),
+		DuplicateQuestions: m.counters.duplicateQuestions.Load(),
+		GoodbyesSent:       m.counters.goodbyesSent.Load(),
+		EmptyQueries:       m.counters.emptyQueries.Load(),
+		GossipReannounces:  m.counters.gossipReannounces.Load(),
+		GossipThrottled:    m.counters.gossipThrottled.Load(),
+		RateLimitedAnswers: m.counters.rateLimitedAnswers.Load(),
+		ResponseLatency:    m.counters.responseLatency.snapshot(),
+	}
+}
+
+// Stats returns the mDNS counters along with the TCP connection counters.
+func (d *Device) Stats() Stats {
+	stats := d.mdns.Stats()
+	stats.ActiveConnections = d.activeConnections.Load()
+	stats.RequestFramingErrors = d.requestFramingErrors.Load()
+	stats.RequestContentErrors = d.requestContentErrors.Load()
+	return stats
+}
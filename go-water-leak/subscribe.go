@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/talDoFlemis/badezimmer/go-water-leak/badezimmer"
+)
+
+const (
+	// Pending updates buffered per subscriber before the oldest is dropped
+	subscriberBufferSize = 16
+	// Subscribers that lose this many updates are disconnected
+	maxDroppedUpdates = 64
+	// A single update write may block for at most this long
+	subscriberWriteTimeout = 5 * time.Second
+)
+
+// subscriber is a TCP client streaming leak updates. The generator never
+// blocks on it: when its buffer is full the oldest update is dropped.
+type subscriber struct {
+	conn    net.Conn
+	updates chan leakSample
+	dropped atomic.Uint64
+	done    chan struct{}
+	once    sync.Once
+}
+
+func newSubscriber(conn net.Conn) *subscriber {
+	return &subscriber{
+		conn:    conn,
+		updates: make(chan leakSample, subscriberBufferSize),
+		done:    make(chan struct{}),
+	}
+}
+
+// offer queues sample without blocking and reports whether the subscriber
+// is still within its drop budget.
+func (s *subscriber) offer(sample leakSample) bool {
+	select {
+	case s.updates <- sample:
+		return true
+	default:
+	}
+
+	// Make room by dropping the oldest pending update
+	select {
+	case <-s.updates:
+	default:
+	}
+	dropped := s.dropped.Add(1)
+
+	select {
+	case s.updates <- sample:
+	default:
+	}
+
+	return dropped < maxDroppedUpdates
+}
+
+func (s *subscriber) close() {
+	s.once.Do(func() {
+		close(s.done)
+		// Unblocks a write stuck on a client that stopped reading
+		s.conn.Close()
+	})
+}
+
+// publish fans sample out to every subscriber, disconnecting the ones that
+// fell too far behind.
+func (w *WaterLeakDetector) publish(sample leakSample) {
+	w.subscribersMu.Lock()
+	defer w.subscribersMu.Unlock()
+
+	for sub := range w.subscribers {
+		if !sub.offer(sample) {
+			logRequestf(context.Background(), "Disconnecting subscriber %s after %d dropped updates", sub.conn.RemoteAddr(), sub.dropped.Load())
+			delete(w.subscribers, sub)
+			sub.close()
+		}
+	}
+}
+
+// streamUpdates turns the connection into a stream of leak updates, starting
+// with the current sample, until the client goes away or the detector stops.
+func (w *WaterLeakDetector) streamUpdates(ctx context.Context, conn net.Conn, requestID string) {
+	sub := newSubscriber(conn)
+
+	w.subscribersMu.Lock()
+	w.subscribers[sub] = struct{}{}
+	w.subscribersMu.Unlock()
+
+	defer func() {
+		w.subscribersMu.Lock()
+		delete(w.subscribers, sub)
+		w.subscribersMu.Unlock()
+		sub.close()
+	}()
+
+	w.propsMu.RLock()
+	current := w.history.latest()
+	w.propsMu.RUnlock()
+	sub.offer(current)
+
+	logRequestf(ctx, "Streaming leak updates to %s", conn.RemoteAddr())
+
+	// Updates are compressed like any response once the client said hello
+	compression := compressionFromContext(ctx)
+
+	// The client may only ping while subscribed; reading also notices when
+	// it goes away between updates
+	pings := make(chan *badezimmer.BadezimmerRequest, 1)
+	go func() {
+		defer sub.close()
+		for {
+			messageBuf, err := readFrame(conn)
+			if err != nil {
+				return
+			}
+
+			request := &badezimmer.BadezimmerRequest{}
+			if err := unmarshalOptions.Unmarshal(messageBuf, request); err != nil || request.GetPing() == nil {
+				logRequestf(ctx, "Ignoring request from subscriber %s", conn.RemoteAddr())
+				continue
+			}
+
+			select {
+			case pings <- request:
+			default:
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sub.done:
+			return
+		case ping := <-pings:
+			response := pongResponse()
+			correlate(response, ping)
+
+			conn.SetWriteDeadline(time.Now().Add(subscriberWriteTimeout))
+			if err := writeResponseCompressed(conn, response, compression); err != nil {
+				logRequestf(ctx, "Subscriber %s gone: %v", conn.RemoteAddr(), err)
+				return
+			}
+		case sample := <-sub.updates:
+			response := &badezimmer.BadezimmerResponse{
+				Response: &badezimmer.BadezimmerResponse_LeakUpdate{
+					LeakUpdate: sample.toProto(),
+				},
+				RequestId: requestID,
+				Headers:   echoedHeaders(HeadersFromContext(ctx)),
+			}
+
+			conn.SetWriteDeadline(time.Now().Add(subscriberWriteTimeout))
+			if err := writeResponseCompressed(conn, response, compression); err != nil {
+				logRequestf(ctx, "Subscriber %s gone: %v", conn.RemoteAddr(), err)
+				return
+			}
+		}
+	}
+}
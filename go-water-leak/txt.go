@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"unicode/utf8"
+)
+
+// MaxTXTEntryLength is the DNS-SD limit of a single key=value TXT string.
+const MaxTXTEntryLength = 255
+
+var ErrTXTEntryTooLong = errors.New("TXT entry exceeds 255 octets")
+
+// TXTOverflowPolicy decides what happens to TXT entries longer than
+// MaxTXTEntryLength, which some parsers truncate or drop with the record.
+type TXTOverflowPolicy int
+
+const (
+	// TXTTruncate cuts the value to fit, on a UTF-8 boundary, and logs it
+	TXTTruncate TXTOverflowPolicy = iota
+	// TXTReject fails RegisterService and UpdateService
+	TXTReject
+)
+
+// WithTXTOverflowPolicy sets how over-length TXT entries are handled,
+// TXTTruncate by default.
+func WithTXTOverflowPolicy(policy TXTOverflowPolicy) Option {
+	return func(m *BadezimmerMDNS) {
+		m.txtOverflow = policy
+	}
+}
+
+// checkTXTEntries returns ErrTXTEntryTooLong for the first over-length
+// entry, in key order, when the policy rejects them.
+func (m *BadezimmerMDNS) checkTXTEntries(entries map[string]string) error {
+	if m.txtOverflow != TXTReject {
+		return nil
+	}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if length := len(key) + 1 + len(entries[key]); length > MaxTXTEntryLength {
+			return fmt.Errorf("%w: %q is %d octets", ErrTXTEntryTooLong, key, length)
+		}
+	}
+	return nil
+}
+
+// truncateTXTEntries shortens the over-length values of entries in place.
+// Keys too long to leave room for any value are dropped.
+func truncateTXTEntries(entries map[string]string) {
+	for key, value := range entries {
+		if len(key)+1+len(value) <= MaxTXTEntryLength {
+			continue
+		}
+
+		room := MaxTXTEntryLength - len(key) - 1
+		if room < 0 {
+			log.Printf("Warning: dropping TXT entry %q, the key alone exceeds %d octets", key, MaxTXTEntryLength)
+			delete(entries, key)
+			continue
+		}
+
+		// Cut before the rune straddling the limit
+		for room > 0 && !utf8.RuneStart(value[room]) {
+			room--
+		}
+		truncated := value[:room]
+		log.Printf("Warning: truncating TXT entry %q from %d to %d octets", key, len(key)+1+len(value), len(key)+1+len(truncated))
+		entries[key] = truncated
+	}
+}
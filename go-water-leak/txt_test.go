@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateTXTEntries(t *testing.T) {
+	captureLog(t)
+	key := "firmware"
+	room := MaxTXTEntryLength - len(key) - 1
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"fits", strings.Repeat("a", room), strings.Repeat("a", room)},
+		{"ascii", strings.Repeat("a", room+10), strings.Repeat("a", room)},
+		// The two octet rune straddling the limit goes entirely
+		{"utf8", strings.Repeat("a", room-1) + "é" + "b", strings.Repeat("a", room-1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := map[string]string{key: tt.value, "severity": "3"}
+			truncateTXTEntries(entries)
+			if got := entries[key]; got != tt.want {
+				t.Errorf("truncated to %d octets, want %d", len(got), len(tt.want))
+			}
+			if !utf8.ValidString(entries[key]) {
+				t.Error("truncated value is not valid UTF-8")
+			}
+			if entries["severity"] != "3" {
+				t.Errorf("short entry changed to %q", entries["severity"])
+			}
+		})
+	}
+
+	entries := map[string]string{strings.Repeat("k", MaxTXTEntryLength): "v"}
+	truncateTXTEntries(entries)
+	if len(entries) != 0 {
+		t.Errorf("entry with an over-length key kept: %v", entries)
+	}
+}
+
+func TestTXTOverflowPolicy(t *testing.T) {
+	captureLog(t)
+	long := strings.Repeat("a", MaxTXTEntryLength)
+
+	info := testService("Kitchen")
+	info.Properties["url"] = long
+	info.Properties["notes"] = long
+	m := NewBadezimmerMDNS(WithTXTOverflowPolicy(TXTReject))
+	err := m.RegisterService(info)
+	if !errors.Is(err, ErrTXTEntryTooLong) {
+		t.Fatalf("RegisterService() = %v, want ErrTXTEntryTooLong", err)
+	}
+	// The first entry in key order is reported, whatever the map order
+	if !strings.Contains(err.Error(), `"notes"`) {
+		t.Errorf("error %q does not name the first over-length key", err)
+	}
+
+	// Truncating, the default, answers with the entry cut to fit
+	m, capture := startTestResponder(t)
+	info = testService("Kitchen")
+	info.Properties["url"] = long
+	addService(m, info)
+	ask(m, info.Type)
+	if got := txtEntries(capture.next(t))["url"]; len("url=")+len(got) != MaxTXTEntryLength {
+		t.Errorf("answered url entry of %d octets, want %d", len("url=")+len(got), MaxTXTEntryLength)
+	}
+	if info.Properties["url"] != long {
+		t.Error("truncating changed the registered service")
+	}
+}
@@ -26,7 +26,7 @@ from google.protobuf import timestamp_pb2 as google_dot_protobuf_dot_timestamp__
 from google.protobuf import empty_pb2 as google_dot_protobuf_dot_empty__pb2
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x10\x62\x61\x64\x65zimmer.proto\x12\nbadezimmer\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\"\xfa\x02\n\x0f\x43onnectedDevice\x12\n\n\x02id\x18\x01 \x01(\t\x12\x13\n\x0b\x64\x65vice_name\x18\x02 \x01(\t\x12$\n\x04kind\x18\x03 \x01(\x0e\x32\x16.badezimmer.DeviceKind\x12(\n\x06status\x18\x04 \x01(\x0e\x32\x18.badezimmer.DeviceStatus\x12\x0b\n\x03ips\x18\x05 \x03(\t\x12\x0c\n\x04port\x18\x06 \x01(\x05\x12?\n\nproperties\x18\x07 \x03(\x0b\x32+.badezimmer.ConnectedDevice.PropertiesEntry\x12,\n\x08\x63\x61tegory\x18\x08 \x01(\x0e\x32\x1a.badezimmer.DeviceCategory\x12\x39\n\x12transport_protocol\x18\t \x01(\x0e\x32\x1d.badezimmer.TransportProtocol\x1a\x31\n\x0fPropertiesEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"\x89\x01\n\x1bListConnectedDevicesRequest\x12\x30\n\x0b\x66ilter_kind\x18\x01 \x01(\x0e\x32\x16.badezimmer.DeviceKindH\x00\x88\x01\x01\x12\x18\n\x0b\x66ilter_name\x18\x02 \x01(\tH\x01\x88\x01\x01\x42\x0e\n\x0c_filter_kindB\x0e\n\x0c_filter_name\"L\n\x1cListConnectedDevicesResponse\x12,\n\x07\x64\x65vices\x18\x01 \x03(\x0b\x32\x1b.badezimmer.ConnectedDevice\"\xab\x01\n\x1aSendActuatorCommandRequest\x12\x11\n\tdevice_id\x18\x01 \x01(\t\x12:\n\x0clight_action\x18\x02 \x01(\x0b\x32\".badezimmer.LightLampActionRequestH\x00\x12\x34\n\x0bsink_action\x18\x03 \x01(\x0b\x32\x1d.badezimmer.SinkActionRequestH\x00\x42\x08\n\x06\x61\x63tion\"\xaf\x01\n\x0c\x45rrorDetails\x12#\n\x04\x63ode\x18\x01 \x01(\x0e\x32\x15.badezimmer.ErrorCode\x12\x0f\n\x07message\x18\x02 \x01(\t\x12\x38\n\x08metadata\x18\x03 \x03(\x0b\x32&.badezimmer.ErrorDetails.MetadataEntry\x1a/\n\rMetadataEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"\xd1\x01\n\x11\x42\x61\x64\x65zimmerRequest\x12\'\n\x05\x65mpty\x18\x01 \x01(\x0b\x32\x16.google.protobuf.EmptyH\x00\x12?\n\x0clist_devices\x18\x02 \x01(\x0b\x32\'.badezimmer.ListConnectedDevicesRequestH\x00\x12G\n\x15send_actuator_command\x18\x03 \x01(\x0b\x32&.badezimmer.SendActuatorCommandRequestH\x00\x42\t\n\x07request\"\x92\x02\n\x12\x42\x61\x64\x65zimmerResponse\x12\'\n\x05\x65mpty\x18\x01 \x01(\x0b\x32\x16.google.protobuf.EmptyH\x00\x12)\n\x05\x65rror\x18\x02 \x01(\x0b\x32\x18.badezimmer.ErrorDetailsH\x00\x12I\n\x15list_devices_response\x18\x03 \x01(\x0b\x32(.badezimmer.ListConnectedDevicesResponseH\x00\x12Q\n\x1esend_actuator_command_response\x18\x04 \x01(\x0b\x32\'.badezimmer.SendActuatorCommandResponseH\x00\x42\n\n\x08response\"?\n\x1bSendActuatorCommandResponse\x12\x14\n\x07message\x18\x02 \x01(\tH\x00\x88\x01\x01\x42\n\n\x08_message\"\x16\n\x05\x43olor\x12\r\n\x05value\x18\x01 \x01(\x07\"\x93\x01\n\x16LightLampActionRequest\x12\x14\n\x07turn_on\x18\x01 \x01(\x08H\x00\x88\x01\x01\x12\x17\n\nbrightness\x18\x02 \x01(\x05H\x01\x88\x01\x01\x12%\n\x05\x63olor\x18\x03 \x01(\x0b\x32\x11.badezimmer.ColorH\x02\x88\x01\x01\x42\n\n\x08_turn_onB\r\n\x0b_brightnessB\x08\n\x06_color\"5\n\x11SinkActionRequest\x12\x14\n\x07turn_on\x18\x01 \x01(\x08H\x00\x88\x01\x01\x42\n\n\x08_turn_on\"@\n\x0cMDNSQuestion\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\"\n\x04type\x18\x02 \x01(\x0e\x32\x14.badezimmer.MDNSType\"?\n\x10MDNSQueryRequest\x12+\n\tquestions\x18\x01 \x03(\x0b\x32\x18.badezimmer.MDNSQuestion\"6\n\x11MDNSPointerRecord\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x13\n\x0b\x64omain_name\x18\x02 \x01(\t\"\x8f\x01\n\rMDNSSRVRecord\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0c\n\x04port\x18\x02 \x01(\x05\x12\x0e\n\x06target\x18\x03 \x01(\t\x12/\n\x08protocol\x18\x04 \x01(\x0e\x32\x1d.badezimmer.TransportProtocol\x12\x0f\n\x07service\x18\x05 \x01(\t\x12\x10\n\x08instance\x18\x06 \x01(\t\"\x88\x01\n\x0eMDNSTextRecord\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x38\n\x07\x65ntries\x18\x02 \x03(\x0b\x32\'.badezimmer.MDNSTextRecord.EntriesEntry\x1a.\n\x0c\x45ntriesEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\",\n\x0bMDNSARecord\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0f\n\x07\x61\x64\x64ress\x18\x02 \x01(\t\"\x8b\x02\n\nMDNSRecord\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0b\n\x03ttl\x18\x02 \x01(\x05\x12\x13\n\x0b\x63\x61\x63he_flush\x18\x03 \x01(\x08\x12\x33\n\nptr_record\x18\x04 \x01(\x0b\x32\x1d.badezimmer.MDNSPointerRecordH\x00\x12/\n\nsrv_record\x18\x05 \x01(\x0b\x32\x19.badezimmer.MDNSSRVRecordH\x00\x12\x30\n\ntxt_record\x18\x06 \x01(\x0b\x32\x1a.badezimmer.MDNSTextRecordH\x00\x12+\n\x08\x61_record\x18\x07 \x01(\x0b\x32\x17.badezimmer.MDNSARecordH\x00\x42\x08\n\x06record\"p\n\x11MDNSQueryResponse\x12\'\n\x07\x61nswers\x18\x01 \x03(\x0b\x32\x16.badezimmer.MDNSRecord\x12\x32\n\x12\x61\x64\x64itional_records\x18\x02 \x03(\x0b\x32\x16.badezimmer.MDNSRecord\"\xc5\x01\n\x04MDNS\x12\x16\n\x0etransaction_id\x18\x01 \x01(\x07\x12-\n\ttimestamp\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\x35\n\rquery_request\x18\x03 \x01(\x0b\x32\x1c.badezimmer.MDNSQueryRequestH\x00\x12\x37\n\x0equery_response\x18\x04 \x01(\x0b\x32\x1d.badezimmer.MDNSQueryResponseH\x00\x42\x06\n\x04\x64\x61ta*B\n\nDeviceKind\x12\x10\n\x0cUNKNOWN_KIND\x10\x00\x12\x0f\n\x0bSENSOR_KIND\x10\x01\x12\x11\n\rACTUATOR_KIND\x10\x02*w\n\x0c\x44\x65viceStatus\x12\x19\n\x15UNKNOWN_DEVICE_STATUS\x10\x00\x12\x19\n\x15OFFLINE_DEVICE_STATUS\x10\x01\x12\x18\n\x14ONLINE_DEVICE_STATUS\x10\x02\x12\x17\n\x13\x45RROR_DEVICE_STATUS\x10\x03*o\n\x0e\x44\x65viceCategory\x12\x14\n\x10UNKNOWN_CATEGORY\x10\x00\x12\x0e\n\nLIGHT_LAMP\x10\x01\x12\x11\n\rFART_DETECTOR\x10\x02\x12\n\n\x06TOILET\x10\x03\x12\x08\n\x04SINK\x10\x04\x12\x0e\n\nWATER_LEAK\x10\x05*M\n\x11TransportProtocol\x12\x14\n\x10UNKNOWN_PROTOCOL\x10\x00\x12\x10\n\x0cTCP_PROTOCOL\x10\x01\x12\x10\n\x0cUDP_PROTOCOL\x10\x02*s\n\tErrorCode\x12\x11\n\rUNKNOWN_ERROR\x10\x00\x12\x14\n\x10\x44\x45VICE_NOT_FOUND\x10\x01\x12\x13\n\x0fINVALID_COMMAND\x10\x02\x12\x12\n\x0e\x44\x45VICE_OFFLINE\x10\x03\x12\x14\n\x10VALIDATION_ERROR\x10\x04*@\n\x08MDNSType\x12\n\n\x06MDNS_A\x10\x00\x12\x0c\n\x08MDNS_PTR\x10\x01\x12\x0c\n\x08MDNS_SRV\x10\x02\x12\x0c\n\x08MDNS_TXT\x10\x03\x32\xea\x01\n\x11\x42\x61\x64\x65zimmerService\x12k\n\x14ListConnectedDevices\x12\'.badezimmer.ListConnectedDevicesRequest\x1a(.badezimmer.ListConnectedDevicesResponse\"\x00\x12h\n\x13SendActuatorCommand\x12&.badezimmer.SendActuatorCommandRequest\x1a\'.badezimmer.SendActuatorCommandResponse\"\x00\x42\x31Z/github.com/talDoFlemis/badezimmer-go/badezimmerb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x10\x62\x61\x64\x65zimmer.proto\x12\nbadezimmer\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\"\xfa\x02\n\x0f\x43onnectedDevice\x12\n\n\x02id\x18\x01 \x01(\t\x12\x13\n\x0b\x64\x65vice_name\x18\x02 \x01(\t\x12$\n\x04kind\x18\x03 \x01(\x0e\x32\x16.badezimmer.DeviceKind\x12(\n\x06status\x18\x04 \x01(\x0e\x32\x18.badezimmer.DeviceStatus\x12\x0b\n\x03ips\x18\x05 \x03(\t\x12\x0c\n\x04port\x18\x06 \x01(\x05\x12?\n\nproperties\x18\x07 \x03(\x0b\x32+.badezimmer.ConnectedDevice.PropertiesEntry\x12,\n\x08\x63\x61tegory\x18\x08 \x01(\x0e\x32\x1a.badezimmer.DeviceCategory\x12\x39\n\x12transport_protocol\x18\t \x01(\x0e\x32\x1d.badezimmer.TransportProtocol\x1a\x31\n\x0fPropertiesEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"\x89\x01\n\x1bListConnectedDevicesRequest\x12\x30\n\x0b\x66ilter_kind\x18\x01 \x01(\x0e\x32\x16.badezimmer.DeviceKindH\x00\x88\x01\x01\x12\x18\n\x0b\x66ilter_name\x18\x02 \x01(\tH\x01\x88\x01\x01\x42\x0e\n\x0c_filter_kindB\x0e\n\x0c_filter_name\"L\n\x1cListConnectedDevicesResponse\x12,\n\x07\x64\x65vices\x18\x01 \x03(\x0b\x32\x1b.badezimmer.ConnectedDevice\"\xab\x01\n\x1aSendActuatorCommandRequest\x12\x11\n\tdevice_id\x18\x01 \x01(\t\x12:\n\x0clight_action\x18\x02 \x01(\x0b\x32\".badezimmer.LightLampActionRequestH\x00\x12\x34\n\x0bsink_action\x18\x03 \x01(\x0b\x32\x1d.badezimmer.SinkActionRequestH\x00\x42\x08\n\x06\x61\x63tion\"\xaf\x01\n\x0c\x45rrorDetails\x12#\n\x04\x63ode\x18\x01 \x01(\x0e\x32\x15.badezimmer.ErrorCode\x12\x0f\n\x07message\x18\x02 \x01(\t\x12\x38\n\x08metadata\x18\x03 \x03(\x0b\x32&.badezimmer.ErrorDetails.MetadataEntry\x1a/\n\rMetadataEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"\xc0\x05\n\x11\x42\x61\x64\x65zimmerRequest\x12\'\n\x05\x65mpty\x18\x01 \x01(\x0b\x32\x16.google.protobuf.EmptyH\x00\x12?\n\x0clist_devices\x18\x02 \x01(\x0b\x32\'.badezimmer.ListConnectedDevicesRequestH\x00\x12G\n\x15send_actuator_command\x18\x03 \x01(\x0b\x32&.badezimmer.SendActuatorCommandRequestH\x00\x12\x34\n\x0bget_history\x18\x04 \x01(\x0b\x32\x1d.badezimmer.GetHistoryRequestH\x00\x12\x31\n\tsubscribe\x18\x05 \x01(\x0b\x32\x1c.badezimmer.SubscribeRequestH\x00\x12\'\n\x04ping\x18\x06 \x01(\x0b\x32\x17.badezimmer.PingRequestH\x00\x12)\n\x05hello\x18\x07 \x01(\x0b\x32\x18.badezimmer.HelloRequestH\x00\x12\x38\n\rlist_services\x18\x08 \x01(\x0b\x32\x1f.badezimmer.ListServicesRequestH\x00\x12\x41\n\x12get_severity_stats\x18\t \x01(\x0b\x32#.badezimmer.GetSeverityStatsRequestH\x00\x12\x32\n\nget_status\x18\n \x01(\x0b\x32\x1c.badezimmer.GetStatusRequestH\x00\x12\x12\n\nrequest_id\x18\x10 \x01(\t\x12;\n\x07headers\x18\x11 \x03(\x0b\x32*.badezimmer.BadezimmerRequest.HeadersEntry\x1a.\n\x0cHeadersEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\x42\t\n\x07request\"\x9f\x06\n\x12\x42\x61\x64\x65zimmerResponse\x12\'\n\x05\x65mpty\x18\x01 \x01(\x0b\x32\x16.google.protobuf.EmptyH\x00\x12)\n\x05\x65rror\x18\x02 \x01(\x0b\x32\x18.badezimmer.ErrorDetailsH\x00\x12I\n\x15list_devices_response\x18\x03 \x01(\x0b\x32(.badezimmer.ListConnectedDevicesResponseH\x00\x12Q\n\x1esend_actuator_command_response\x18\x04 \x01(\x0b\x32\'.badezimmer.SendActuatorCommandResponseH\x00\x12>\n\x14get_history_response\x18\x05 \x01(\x0b\x32\x1e.badezimmer.GetHistoryResponseH\x00\x12-\n\x0bleak_update\x18\x06 \x01(\x0b\x32\x16.badezimmer.LeakSampleH\x00\x12(\n\x04pong\x18\x07 \x01(\x0b\x32\x18.badezimmer.PongResponseH\x00\x12\x33\n\x0ehello_response\x18\x08 \x01(\x0b\x32\x19.badezimmer.HelloResponseH\x00\x12\x42\n\x16list_services_response\x18\t \x01(\x0b\x32 .badezimmer.ListServicesResponseH\x00\x12\x44\n\x17severity_stats_response\x18\n \x01(\x0b\x32!.badezimmer.SeverityStatsResponseH\x00\x12\x31\n\x0fstatus_response\x18\x0b \x01(\x0b\x32\x16.badezimmer.LeakSampleH\x00\x12\x12\n\nrequest_id\x18\x10 \x01(\t\x12<\n\x07headers\x18\x11 \x03(\x0b\x32+.badezimmer.BadezimmerResponse.HeadersEntry\x1a.\n\x0cHeadersEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\x42\n\n\x08response\"?\n\x1bSendActuatorCommandResponse\x12\x14\n\x07message\x18\x02 \x01(\tH\x00\x88\x01\x01\x42\n\n\x08_message\"\x13\n\x11GetHistoryRequest\"\x12\n\x10GetStatusRequest\"_\n\nLeakSample\x12\x10\n\x08severity\x18\x01 \x01(\t\x12\x10\n\x08location\x18\x02 \x01(\t\x12-\n\ttimestamp\x18\x03 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\"=\n\x12GetHistoryResponse\x12\'\n\x07samples\x18\x01 \x03(\x0b\x32\x16.badezimmer.LeakSample\"\x12\n\x10SubscribeRequest\"\r\n\x0bPingRequest\"?\n\x0cPongResponse\x12/\n\x0bserver_time\x18\x01 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\"#\n\x0cHelloRequest\x12\x13\n\x0b\x63ompression\x18\x01 \x03(\t\"$\n\rHelloResponse\x12\x13\n\x0b\x63ompression\x18\x01 \x01(\t\"\x15\n\x13ListServicesRequest\"3\n\x10ServiceTypeCount\x12\x0c\n\x04type\x18\x01 \x01(\t\x12\x11\n\tinstances\x18\x02 \x01(\x05\"F\n\x14ListServicesResponse\x12.\n\x08services\x18\x01 \x03(\x0b\x32\x1c.badezimmer.ServiceTypeCount\"1\n\x17GetSeverityStatsRequest\x12\x16\n\x0ewindow_seconds\x18\x01 \x01(\r\"Q\n\x15SeverityStatsResponse\x12\x0b\n\x03min\x18\x01 \x01(\x05\x12\x0b\n\x03max\x18\x02 \x01(\x05\x12\x0f\n\x07\x61verage\x18\x03 \x01(\x01\x12\r\n\x05\x63ount\x18\x04 \x01(\x05\"\x16\n\x05\x43olor\x12\r\n\x05value\x18\x01 \x01(\x07\"\x93\x01\n\x16LightLampActionRequest\x12\x14\n\x07turn_on\x18\x01 \x01(\x08H\x00\x88\x01\x01\x12\x17\n\nbrightness\x18\x02 \x01(\x05H\x01\x88\x01\x01\x12%\n\x05\x63olor\x18\x03 \x01(\x0b\x32\x11.badezimmer.ColorH\x02\x88\x01\x01\x42\n\n\x08_turn_onB\r\n\x0b_brightnessB\x08\n\x06_color\"5\n\x11SinkActionRequest\x12\x14\n\x07turn_on\x18\x01 \x01(\x08H\x00\x88\x01\x01\x42\n\n\x08_turn_on\"@\n\x0cMDNSQuestion\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\"\n\x04type\x18\x02 \x01(\x0e\x32\x14.badezimmer.MDNSType\"r\n\x10MDNSQueryRequest\x12+\n\tquestions\x18\x01 \x03(\x0b\x32\x18.badezimmer.MDNSQuestion\x12\x31\n\x11\x61uthority_records\x18\x02 \x03(\x0b\x32\x16.badezimmer.MDNSRecord\"6\n\x11MDNSPointerRecord\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x13\n\x0b\x64omain_name\x18\x02 \x01(\t\"\x8f\x01\n\rMDNSSRVRecord\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0c\n\x04port\x18\x02 \x01(\x05\x12\x0e\n\x06target\x18\x03 \x01(\t\x12/\n\x08protocol\x18\x04 \x01(\x0e\x32\x1d.badezimmer.TransportProtocol\x12\x0f\n\x07service\x18\x05 \x01(\t\x12\x10\n\x08instance\x18\x06 \x01(\t\"\x88\x01\n\x0eMDNSTextRecord\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x38\n\x07\x65ntries\x18\x02 \x03(\x0b\x32\'.badezimmer.MDNSTextRecord.EntriesEntry\x1a.\n\x0c\x45ntriesEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\",\n\x0bMDNSARecord\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0f\n\x07\x61\x64\x64ress\x18\x02 \x01(\t\"\x8b\x02\n\nMDNSRecord\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0b\n\x03ttl\x18\x02 \x01(\x05\x12\x13\n\x0b\x63\x61\x63he_flush\x18\x03 \x01(\x08\x12\x33\n\nptr_record\x18\x04 \x01(\x0b\x32\x1d.badezimmer.MDNSPointerRecordH\x00\x12/\n\nsrv_record\x18\x05 \x01(\x0b\x32\x19.badezimmer.MDNSSRVRecordH\x00\x12\x30\n\ntxt_record\x18\x06 \x01(\x0b\x32\x1a.badezimmer.MDNSTextRecordH\x00\x12+\n\x08\x61_record\x18\x07 \x01(\x0b\x32\x17.badezimmer.MDNSARecordH\x00\x42\x08\n\x06record\"p\n\x11MDNSQueryResponse\x12\'\n\x07\x61nswers\x18\x01 \x03(\x0b\x32\x16.badezimmer.MDNSRecord\x12\x32\n\x12\x61\x64\x64itional_records\x18\x02 \x03(\x0b\x32\x16.badezimmer.MDNSRecord\"\xda\x01\n\x04MDNS\x12\x16\n\x0etransaction_id\x18\x01 \x01(\x07\x12-\n\ttimestamp\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\x35\n\rquery_request\x18\x03 \x01(\x0b\x32\x1c.badezimmer.MDNSQueryRequestH\x00\x12\x37\n\x0equery_response\x18\x04 \x01(\x0b\x32\x1d.badezimmer.MDNSQueryResponseH\x00\x12\x13\n\x0binstance_id\x18\x05 \x01(\tB\x06\n\x04\x64\x61ta*B\n\nDeviceKind\x12\x10\n\x0cUNKNOWN_KIND\x10\x00\x12\x0f\n\x0bSENSOR_KIND\x10\x01\x12\x11\n\rACTUATOR_KIND\x10\x02*w\n\x0c\x44\x65viceStatus\x12\x19\n\x15UNKNOWN_DEVICE_STATUS\x10\x00\x12\x19\n\x15OFFLINE_DEVICE_STATUS\x10\x01\x12\x18\n\x14ONLINE_DEVICE_STATUS\x10\x02\x12\x17\n\x13\x45RROR_DEVICE_STATUS\x10\x03*o\n\x0e\x44\x65viceCategory\x12\x14\n\x10UNKNOWN_CATEGORY\x10\x00\x12\x0e\n\nLIGHT_LAMP\x10\x01\x12\x11\n\rFART_DETECTOR\x10\x02\x12\n\n\x06TOILET\x10\x03\x12\x08\n\x04SINK\x10\x04\x12\x0e\n\nWATER_LEAK\x10\x05*M\n\x11TransportProtocol\x12\x14\n\x10UNKNOWN_PROTOCOL\x10\x00\x12\x10\n\x0cTCP_PROTOCOL\x10\x01\x12\x10\n\x0cUDP_PROTOCOL\x10\x02*\x99\x01\n\tErrorCode\x12\x11\n\rUNKNOWN_ERROR\x10\x00\x12\x14\n\x10\x44\x45VICE_NOT_FOUND\x10\x01\x12\x13\n\x0fINVALID_COMMAND\x10\x02\x12\x12\n\x0e\x44\x45VICE_OFFLINE\x10\x03\x12\x14\n\x10VALIDATION_ERROR\x10\x04\x12\x0f\n\x0bUNAVAILABLE\x10\x05\x12\x13\n\x0fUNAUTHENTICATED\x10\x06*@\n\x08MDNSType\x12\n\n\x06MDNS_A\x10\x00\x12\x0c\n\x08MDNS_PTR\x10\x01\x12\x0c\n\x08MDNS_SRV\x10\x02\x12\x0c\n\x08MDNS_TXT\x10\x03\x32\xea\x01\n\x11\x42\x61\x64\x65zimmerService\x12k\n\x14ListConnectedDevices\x12\'.badezimmer.ListConnectedDevicesRequest\x1a(.badezimmer.ListConnectedDevicesResponse\"\x00\x12h\n\x13SendActuatorCommand\x12&.badezimmer.SendActuatorCommandRequest\x1a\'.badezimmer.SendActuatorCommandResponse\"\x00\x42\x31Z/github.com/talDoFlemis/badezimmer-go/badezimmerb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -38,20 +38,24 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['_CONNECTEDDEVICE_PROPERTIESENTRY']._serialized_options = b'8\001'
   _globals['_ERRORDETAILS_METADATAENTRY']._loaded_options = None
   _globals['_ERRORDETAILS_METADATAENTRY']._serialized_options = b'8\001'
+  _globals['_BADEZIMMERREQUEST_HEADERSENTRY']._loaded_options = None
+  _globals['_BADEZIMMERREQUEST_HEADERSENTRY']._serialized_options = b'8\001'
+  _globals['_BADEZIMMERRESPONSE_HEADERSENTRY']._loaded_options = None
+  _globals['_BADEZIMMERRESPONSE_HEADERSENTRY']._serialized_options = b'8\001'
   _globals['_MDNSTEXTRECORD_ENTRIESENTRY']._loaded_options = None
   _globals['_MDNSTEXTRECORD_ENTRIESENTRY']._serialized_options = b'8\001'
-  _globals['_DEVICEKIND']._serialized_start=2930
-  _globals['_DEVICEKIND']._serialized_end=2996
-  _globals['_DEVICESTATUS']._serialized_start=2998
-  _globals['_DEVICESTATUS']._serialized_end=3117
-  _globals['_DEVICECATEGORY']._serialized_start=3119
-  _globals['_DEVICECATEGORY']._serialized_end=3230
-  _globals['_TRANSPORTPROTOCOL']._serialized_start=3232
-  _globals['_TRANSPORTPROTOCOL']._serialized_end=3309
-  _globals['_ERRORCODE']._serialized_start=3311
-  _globals['_ERRORCODE']._serialized_end=3426
-  _globals['_MDNSTYPE']._serialized_start=3428
-  _globals['_MDNSTYPE']._serialized_end=3492
+  _globals['_DEVICEKIND']._serialized_start=4680
+  _globals['_DEVICEKIND']._serialized_end=4746
+  _globals['_DEVICESTATUS']._serialized_start=4748
+  _globals['_DEVICESTATUS']._serialized_end=4867
+  _globals['_DEVICECATEGORY']._serialized_start=4869
+  _globals['_DEVICECATEGORY']._serialized_end=4980
+  _globals['_TRANSPORTPROTOCOL']._serialized_start=4982
+  _globals['_TRANSPORTPROTOCOL']._serialized_end=5059
+  _globals['_ERRORCODE']._serialized_start=5062
+  _globals['_ERRORCODE']._serialized_end=5215
+  _globals['_MDNSTYPE']._serialized_start=5217
+  _globals['_MDNSTYPE']._serialized_end=5281
   _globals['_CONNECTEDDEVICE']._serialized_start=95
   _globals['_CONNECTEDDEVICE']._serialized_end=473
   _globals['_CONNECTEDDEVICE_PROPERTIESENTRY']._serialized_start=424
@@ -67,37 +71,69 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['_ERRORDETAILS_METADATAENTRY']._serialized_start=996
   _globals['_ERRORDETAILS_METADATAENTRY']._serialized_end=1043
   _globals['_BADEZIMMERREQUEST']._serialized_start=1046
-  _globals['_BADEZIMMERREQUEST']._serialized_end=1255
-  _globals['_BADEZIMMERRESPONSE']._serialized_start=1258
-  _globals['_BADEZIMMERRESPONSE']._serialized_end=1532
-  _globals['_SENDACTUATORCOMMANDRESPONSE']._serialized_start=1534
-  _globals['_SENDACTUATORCOMMANDRESPONSE']._serialized_end=1597
-  _globals['_COLOR']._serialized_start=1599
-  _globals['_COLOR']._serialized_end=1621
-  _globals['_LIGHTLAMPACTIONREQUEST']._serialized_start=1624
-  _globals['_LIGHTLAMPACTIONREQUEST']._serialized_end=1771
-  _globals['_SINKACTIONREQUEST']._serialized_start=1773
-  _globals['_SINKACTIONREQUEST']._serialized_end=1826
-  _globals['_MDNSQUESTION']._serialized_start=1828
-  _globals['_MDNSQUESTION']._serialized_end=1892
-  _globals['_MDNSQUERYREQUEST']._serialized_start=1894
-  _globals['_MDNSQUERYREQUEST']._serialized_end=1957
-  _globals['_MDNSPOINTERRECORD']._serialized_start=1959
-  _globals['_MDNSPOINTERRECORD']._serialized_end=2013
-  _globals['_MDNSSRVRECORD']._serialized_start=2016
-  _globals['_MDNSSRVRECORD']._serialized_end=2159
-  _globals['_MDNSTEXTRECORD']._serialized_start=2162
-  _globals['_MDNSTEXTRECORD']._serialized_end=2298
-  _globals['_MDNSTEXTRECORD_ENTRIESENTRY']._serialized_start=2252
-  _globals['_MDNSTEXTRECORD_ENTRIESENTRY']._serialized_end=2298
-  _globals['_MDNSARECORD']._serialized_start=2300
-  _globals['_MDNSARECORD']._serialized_end=2344
-  _globals['_MDNSRECORD']._serialized_start=2347
-  _globals['_MDNSRECORD']._serialized_end=2614
-  _globals['_MDNSQUERYRESPONSE']._serialized_start=2616
-  _globals['_MDNSQUERYRESPONSE']._serialized_end=2728
-  _globals['_MDNS']._serialized_start=2731
-  _globals['_MDNS']._serialized_end=2928
-  _globals['_BADEZIMMERSERVICE']._serialized_start=3495
-  _globals['_BADEZIMMERSERVICE']._serialized_end=3729
+  _globals['_BADEZIMMERREQUEST']._serialized_end=1750
+  _globals['_BADEZIMMERREQUEST_HEADERSENTRY']._serialized_start=1693
+  _globals['_BADEZIMMERREQUEST_HEADERSENTRY']._serialized_end=1739
+  _globals['_BADEZIMMERRESPONSE']._serialized_start=1753
+  _globals['_BADEZIMMERRESPONSE']._serialized_end=2552
+  _globals['_BADEZIMMERRESPONSE_HEADERSENTRY']._serialized_start=1693
+  _globals['_BADEZIMMERRESPONSE_HEADERSENTRY']._serialized_end=1739
+  _globals['_SENDACTUATORCOMMANDRESPONSE']._serialized_start=2554
+  _globals['_SENDACTUATORCOMMANDRESPONSE']._serialized_end=2617
+  _globals['_GETHISTORYREQUEST']._serialized_start=2619
+  _globals['_GETHISTORYREQUEST']._serialized_end=2638
+  _globals['_GETSTATUSREQUEST']._serialized_start=2640
+  _globals['_GETSTATUSREQUEST']._serialized_end=2658
+  _globals['_LEAKSAMPLE']._serialized_start=2660
+  _globals['_LEAKSAMPLE']._serialized_end=2755
+  _globals['_GETHISTORYRESPONSE']._serialized_start=2757
+  _globals['_GETHISTORYRESPONSE']._serialized_end=2818
+  _globals['_SUBSCRIBEREQUEST']._serialized_start=2820
+  _globals['_SUBSCRIBEREQUEST']._serialized_end=2838
+  _globals['_PINGREQUEST']._serialized_start=2840
+  _globals['_PINGREQUEST']._serialized_end=2853
+  _globals['_PONGRESPONSE']._serialized_start=2855
+  _globals['_PONGRESPONSE']._serialized_end=2918
+  _globals['_HELLOREQUEST']._serialized_start=2920
+  _globals['_HELLOREQUEST']._serialized_end=2955
+  _globals['_HELLORESPONSE']._serialized_start=2957
+  _globals['_HELLORESPONSE']._serialized_end=2993
+  _globals['_LISTSERVICESREQUEST']._serialized_start=2995
+  _globals['_LISTSERVICESREQUEST']._serialized_end=3016
+  _globals['_SERVICETYPECOUNT']._serialized_start=3018
+  _globals['_SERVICETYPECOUNT']._serialized_end=3069
+  _globals['_LISTSERVICESRESPONSE']._serialized_start=3071
+  _globals['_LISTSERVICESRESPONSE']._serialized_end=3141
+  _globals['_GETSEVERITYSTATSREQUEST']._serialized_start=3143
+  _globals['_GETSEVERITYSTATSREQUEST']._serialized_end=3192
+  _globals['_SEVERITYSTATSRESPONSE']._serialized_start=3194
+  _globals['_SEVERITYSTATSRESPONSE']._serialized_end=3275
+  _globals['_COLOR']._serialized_start=3277
+  _globals['_COLOR']._serialized_end=3299
+  _globals['_LIGHTLAMPACTIONREQUEST']._serialized_start=3302
+  _globals['_LIGHTLAMPACTIONREQUEST']._serialized_end=3449
+  _globals['_SINKACTIONREQUEST']._serialized_start=3451
+  _globals['_SINKACTIONREQUEST']._serialized_end=3504
+  _globals['_MDNSQUESTION']._serialized_start=3506
+  _globals['_MDNSQUESTION']._serialized_end=3570
+  _globals['_MDNSQUERYREQUEST']._serialized_start=3572
+  _globals['_MDNSQUERYREQUEST']._serialized_end=3686
+  _globals['_MDNSPOINTERRECORD']._serialized_start=3688
+  _globals['_MDNSPOINTERRECORD']._serialized_end=3742
+  _globals['_MDNSSRVRECORD']._serialized_start=3745
+  _globals['_MDNSSRVRECORD']._serialized_end=3888
+  _globals['_MDNSTEXTRECORD']._serialized_start=3891
+  _globals['_MDNSTEXTRECORD']._serialized_end=4027
+  _globals['_MDNSTEXTRECORD_ENTRIESENTRY']._serialized_start=3981
+  _globals['_MDNSTEXTRECORD_ENTRIESENTRY']._serialized_end=4027
+  _globals['_MDNSARECORD']._serialized_start=4029
+  _globals['_MDNSARECORD']._serialized_end=4073
+  _globals['_MDNSRECORD']._serialized_start=4076
+  _globals['_MDNSRECORD']._serialized_end=4343
+  _globals['_MDNSQUERYRESPONSE']._serialized_start=4345
+  _globals['_MDNSQUERYRESPONSE']._serialized_end=4457
+  _globals['_MDNS']._serialized_start=4460
+  _globals['_MDNS']._serialized_end=4678
+  _globals['_BADEZIMMERSERVICE']._serialized_start=5284
+  _globals['_BADEZIMMERSERVICE']._serialized_end=5518
 # @@protoc_insertion_point(module_scope)
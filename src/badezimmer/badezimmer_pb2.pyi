@@ -46,6 +46,8 @@ class ErrorCode(int, metaclass=_enum_type_wrapper.EnumTypeWrapper):
     INVALID_COMMAND: _ClassVar[ErrorCode]
     DEVICE_OFFLINE: _ClassVar[ErrorCode]
     VALIDATION_ERROR: _ClassVar[ErrorCode]
+    UNAVAILABLE: _ClassVar[ErrorCode]
+    UNAUTHENTICATED: _ClassVar[ErrorCode]
 
 class MDNSType(int, metaclass=_enum_type_wrapper.EnumTypeWrapper):
     __slots__ = ()
@@ -74,6 +76,8 @@ DEVICE_NOT_FOUND: ErrorCode
 INVALID_COMMAND: ErrorCode
 DEVICE_OFFLINE: ErrorCode
 VALIDATION_ERROR: ErrorCode
+UNAVAILABLE: ErrorCode
+UNAUTHENTICATED: ErrorCode
 MDNS_A: MDNSType
 MDNS_PTR: MDNSType
 MDNS_SRV: MDNSType
@@ -150,26 +154,76 @@ class ErrorDetails(_message.Message):
     def __init__(self, code: _Optional[_Union[ErrorCode, str]] = ..., message: _Optional[str] = ..., metadata: _Optional[_Mapping[str, str]] = ...) -> None: ...
 
 class BadezimmerRequest(_message.Message):
-    __slots__ = ("empty", "list_devices", "send_actuator_command")
+    __slots__ = ("empty", "list_devices", "send_actuator_command", "get_history", "subscribe", "ping", "hello", "list_services", "get_severity_stats", "get_status", "request_id", "headers")
+    class HeadersEntry(_message.Message):
+        __slots__ = ("key", "value")
+        KEY_FIELD_NUMBER: _ClassVar[int]
+        VALUE_FIELD_NUMBER: _ClassVar[int]
+        key: str
+        value: str
+        def __init__(self, key: _Optional[str] = ..., value: _Optional[str] = ...) -> None: ...
     EMPTY_FIELD_NUMBER: _ClassVar[int]
     LIST_DEVICES_FIELD_NUMBER: _ClassVar[int]
     SEND_ACTUATOR_COMMAND_FIELD_NUMBER: _ClassVar[int]
+    GET_HISTORY_FIELD_NUMBER: _ClassVar[int]
+    SUBSCRIBE_FIELD_NUMBER: _ClassVar[int]
+    PING_FIELD_NUMBER: _ClassVar[int]
+    HELLO_FIELD_NUMBER: _ClassVar[int]
+    LIST_SERVICES_FIELD_NUMBER: _ClassVar[int]
+    GET_SEVERITY_STATS_FIELD_NUMBER: _ClassVar[int]
+    GET_STATUS_FIELD_NUMBER: _ClassVar[int]
+    REQUEST_ID_FIELD_NUMBER: _ClassVar[int]
+    HEADERS_FIELD_NUMBER: _ClassVar[int]
     empty: _empty_pb2.Empty
     list_devices: ListConnectedDevicesRequest
     send_actuator_command: SendActuatorCommandRequest
-    def __init__(self, empty: _Optional[_Union[_empty_pb2.Empty, _Mapping]] = ..., list_devices: _Optional[_Union[ListConnectedDevicesRequest, _Mapping]] = ..., send_actuator_command: _Optional[_Union[SendActuatorCommandRequest, _Mapping]] = ...) -> None: ...
+    get_history: GetHistoryRequest
+    subscribe: SubscribeRequest
+    ping: PingRequest
+    hello: HelloRequest
+    list_services: ListServicesRequest
+    get_severity_stats: GetSeverityStatsRequest
+    get_status: GetStatusRequest
+    request_id: str
+    headers: _containers.ScalarMap[str, str]
+    def __init__(self, empty: _Optional[_Union[_empty_pb2.Empty, _Mapping]] = ..., list_devices: _Optional[_Union[ListConnectedDevicesRequest, _Mapping]] = ..., send_actuator_command: _Optional[_Union[SendActuatorCommandRequest, _Mapping]] = ..., get_history: _Optional[_Union[GetHistoryRequest, _Mapping]] = ..., subscribe: _Optional[_Union[SubscribeRequest, _Mapping]] = ..., ping: _Optional[_Union[PingRequest, _Mapping]] = ..., hello: _Optional[_Union[HelloRequest, _Mapping]] = ..., list_services: _Optional[_Union[ListServicesRequest, _Mapping]] = ..., get_severity_stats: _Optional[_Union[GetSeverityStatsRequest, _Mapping]] = ..., get_status: _Optional[_Union[GetStatusRequest, _Mapping]] = ..., request_id: _Optional[str] = ..., headers: _Optional[_Mapping[str, str]] = ...) -> None: ...
 
 class BadezimmerResponse(_message.Message):
-    __slots__ = ("empty", "error", "list_devices_response", "send_actuator_command_response")
+    __slots__ = ("empty", "error", "list_devices_response", "send_actuator_command_response", "get_history_response", "leak_update", "pong", "hello_response", "list_services_response", "severity_stats_response", "status_response", "request_id", "headers")
+    class HeadersEntry(_message.Message):
+        __slots__ = ("key", "value")
+        KEY_FIELD_NUMBER: _ClassVar[int]
+        VALUE_FIELD_NUMBER: _ClassVar[int]
+        key: str
+        value: str
+        def __init__(self, key: _Optional[str] = ..., value: _Optional[str] = ...) -> None: ...
     EMPTY_FIELD_NUMBER: _ClassVar[int]
     ERROR_FIELD_NUMBER: _ClassVar[int]
     LIST_DEVICES_RESPONSE_FIELD_NUMBER: _ClassVar[int]
     SEND_ACTUATOR_COMMAND_RESPONSE_FIELD_NUMBER: _ClassVar[int]
+    GET_HISTORY_RESPONSE_FIELD_NUMBER: _ClassVar[int]
+    LEAK_UPDATE_FIELD_NUMBER: _ClassVar[int]
+    PONG_FIELD_NUMBER: _ClassVar[int]
+    HELLO_RESPONSE_FIELD_NUMBER: _ClassVar[int]
+    LIST_SERVICES_RESPONSE_FIELD_NUMBER: _ClassVar[int]
+    SEVERITY_STATS_RESPONSE_FIELD_NUMBER: _ClassVar[int]
+    STATUS_RESPONSE_FIELD_NUMBER: _ClassVar[int]
+    REQUEST_ID_FIELD_NUMBER: _ClassVar[int]
+    HEADERS_FIELD_NUMBER: _ClassVar[int]
     empty: _empty_pb2.Empty
     error: ErrorDetails
     list_devices_response: ListConnectedDevicesResponse
     send_actuator_command_response: SendActuatorCommandResponse
-    def __init__(self, empty: _Optional[_Union[_empty_pb2.Empty, _Mapping]] = ..., error: _Optional[_Union[ErrorDetails, _Mapping]] = ..., list_devices_response: _Optional[_Union[ListConnectedDevicesResponse, _Mapping]] = ..., send_actuator_command_response: _Optional[_Union[SendActuatorCommandResponse, _Mapping]] = ...) -> None: ...
+    get_history_response: GetHistoryResponse
+    leak_update: LeakSample
+    pong: PongResponse
+    hello_response: HelloResponse
+    list_services_response: ListServicesResponse
+    severity_stats_response: SeverityStatsResponse
+    status_response: LeakSample
+    request_id: str
+    headers: _containers.ScalarMap[str, str]
+    def __init__(self, empty: _Optional[_Union[_empty_pb2.Empty, _Mapping]] = ..., error: _Optional[_Union[ErrorDetails, _Mapping]] = ..., list_devices_response: _Optional[_Union[ListConnectedDevicesResponse, _Mapping]] = ..., send_actuator_command_response: _Optional[_Union[SendActuatorCommandResponse, _Mapping]] = ..., get_history_response: _Optional[_Union[GetHistoryResponse, _Mapping]] = ..., leak_update: _Optional[_Union[LeakSample, _Mapping]] = ..., pong: _Optional[_Union[PongResponse, _Mapping]] = ..., hello_response: _Optional[_Union[HelloResponse, _Mapping]] = ..., list_services_response: _Optional[_Union[ListServicesResponse, _Mapping]] = ..., severity_stats_response: _Optional[_Union[SeverityStatsResponse, _Mapping]] = ..., status_response: _Optional[_Union[LeakSample, _Mapping]] = ..., request_id: _Optional[str] = ..., headers: _Optional[_Mapping[str, str]] = ...) -> None: ...
 
 class SendActuatorCommandResponse(_message.Message):
     __slots__ = ("message",)
@@ -177,6 +231,92 @@ class SendActuatorCommandResponse(_message.Message):
     message: str
     def __init__(self, message: _Optional[str] = ...) -> None: ...
 
+class GetHistoryRequest(_message.Message):
+    __slots__ = ()
+    def __init__(self) -> None: ...
+
+class GetStatusRequest(_message.Message):
+    __slots__ = ()
+    def __init__(self) -> None: ...
+
+class LeakSample(_message.Message):
+    __slots__ = ("severity", "location", "timestamp")
+    SEVERITY_FIELD_NUMBER: _ClassVar[int]
+    LOCATION_FIELD_NUMBER: _ClassVar[int]
+    TIMESTAMP_FIELD_NUMBER: _ClassVar[int]
+    severity: str
+    location: str
+    timestamp: _timestamp_pb2.Timestamp
+    def __init__(self, severity: _Optional[str] = ..., location: _Optional[str] = ..., timestamp: _Optional[_Union[datetime.datetime, _timestamp_pb2.Timestamp, _Mapping]] = ...) -> None: ...
+
+class GetHistoryResponse(_message.Message):
+    __slots__ = ("samples",)
+    SAMPLES_FIELD_NUMBER: _ClassVar[int]
+    samples: _containers.RepeatedCompositeFieldContainer[LeakSample]
+    def __init__(self, samples: _Optional[_Iterable[_Union[LeakSample, _Mapping]]] = ...) -> None: ...
+
+class SubscribeRequest(_message.Message):
+    __slots__ = ()
+    def __init__(self) -> None: ...
+
+class PingRequest(_message.Message):
+    __slots__ = ()
+    def __init__(self) -> None: ...
+
+class PongResponse(_message.Message):
+    __slots__ = ("server_time",)
+    SERVER_TIME_FIELD_NUMBER: _ClassVar[int]
+    server_time: _timestamp_pb2.Timestamp
+    def __init__(self, server_time: _Optional[_Union[datetime.datetime, _timestamp_pb2.Timestamp, _Mapping]] = ...) -> None: ...
+
+class HelloRequest(_message.Message):
+    __slots__ = ("compression",)
+    COMPRESSION_FIELD_NUMBER: _ClassVar[int]
+    compression: _containers.RepeatedScalarFieldContainer[str]
+    def __init__(self, compression: _Optional[_Iterable[str]] = ...) -> None: ...
+
+class HelloResponse(_message.Message):
+    __slots__ = ("compression",)
+    COMPRESSION_FIELD_NUMBER: _ClassVar[int]
+    compression: str
+    def __init__(self, compression: _Optional[str] = ...) -> None: ...
+
+class ListServicesRequest(_message.Message):
+    __slots__ = ()
+    def __init__(self) -> None: ...
+
+class ServiceTypeCount(_message.Message):
+    __slots__ = ("type", "instances")
+    TYPE_FIELD_NUMBER: _ClassVar[int]
+    INSTANCES_FIELD_NUMBER: _ClassVar[int]
+    type: str
+    instances: int
+    def __init__(self, type: _Optional[str] = ..., instances: _Optional[int] = ...) -> None: ...
+
+class ListServicesResponse(_message.Message):
+    __slots__ = ("services",)
+    SERVICES_FIELD_NUMBER: _ClassVar[int]
+    services: _containers.RepeatedCompositeFieldContainer[ServiceTypeCount]
+    def __init__(self, services: _Optional[_Iterable[_Union[ServiceTypeCount, _Mapping]]] = ...) -> None: ...
+
+class GetSeverityStatsRequest(_message.Message):
+    __slots__ = ("window_seconds",)
+    WINDOW_SECONDS_FIELD_NUMBER: _ClassVar[int]
+    window_seconds: int
+    def __init__(self, window_seconds: _Optional[int] = ...) -> None: ...
+
+class SeverityStatsResponse(_message.Message):
+    __slots__ = ("min", "max", "average", "count")
+    MIN_FIELD_NUMBER: _ClassVar[int]
+    MAX_FIELD_NUMBER: _ClassVar[int]
+    AVERAGE_FIELD_NUMBER: _ClassVar[int]
+    COUNT_FIELD_NUMBER: _ClassVar[int]
+    min: int
+    max: int
+    average: float
+    count: int
+    def __init__(self, min: _Optional[int] = ..., max: _Optional[int] = ..., average: _Optional[float] = ..., count: _Optional[int] = ...) -> None: ...
+
 class Color(_message.Message):
     __slots__ = ("value",)
     VALUE_FIELD_NUMBER: _ClassVar[int]
@@ -208,10 +348,12 @@ class MDNSQuestion(_message.Message):
     def __init__(self, name: _Optional[str] = ..., type: _Optional[_Union[MDNSType, str]] = ...) -> None: ...
 
 class MDNSQueryRequest(_message.Message):
-    __slots__ = ("questions",)
+    __slots__ = ("questions", "authority_records")
     QUESTIONS_FIELD_NUMBER: _ClassVar[int]
+    AUTHORITY_RECORDS_FIELD_NUMBER: _ClassVar[int]
     questions: _containers.RepeatedCompositeFieldContainer[MDNSQuestion]
-    def __init__(self, questions: _Optional[_Iterable[_Union[MDNSQuestion, _Mapping]]] = ...) -> None: ...
+    authority_records: _containers.RepeatedCompositeFieldContainer[MDNSRecord]
+    def __init__(self, questions: _Optional[_Iterable[_Union[MDNSQuestion, _Mapping]]] = ..., authority_records: _Optional[_Iterable[_Union[MDNSRecord, _Mapping]]] = ...) -> None: ...
 
 class MDNSPointerRecord(_message.Message):
     __slots__ = ("name", "domain_name")
@@ -287,13 +429,15 @@ class MDNSQueryResponse(_message.Message):
     def __init__(self, answers: _Optional[_Iterable[_Union[MDNSRecord, _Mapping]]] = ..., additional_records: _Optional[_Iterable[_Union[MDNSRecord, _Mapping]]] = ...) -> None: ...
 
 class MDNS(_message.Message):
-    __slots__ = ("transaction_id", "timestamp", "query_request", "query_response")
+    __slots__ = ("transaction_id", "timestamp", "query_request", "query_response", "instance_id")
     TRANSACTION_ID_FIELD_NUMBER: _ClassVar[int]
     TIMESTAMP_FIELD_NUMBER: _ClassVar[int]
     QUERY_REQUEST_FIELD_NUMBER: _ClassVar[int]
     QUERY_RESPONSE_FIELD_NUMBER: _ClassVar[int]
+    INSTANCE_ID_FIELD_NUMBER: _ClassVar[int]
     transaction_id: int
     timestamp: _timestamp_pb2.Timestamp
     query_request: MDNSQueryRequest
     query_response: MDNSQueryResponse
-    def __init__(self, transaction_id: _Optional[int] = ..., timestamp: _Optional[_Union[datetime.datetime, _timestamp_pb2.Timestamp, _Mapping]] = ..., query_request: _Optional[_Union[MDNSQueryRequest, _Mapping]] = ..., query_response: _Optional[_Union[MDNSQueryResponse, _Mapping]] = ...) -> None: ...
+    instance_id: str
+    def __init__(self, transaction_id: _Optional[int] = ..., timestamp: _Optional[_Union[datetime.datetime, _timestamp_pb2.Timestamp, _Mapping]] = ..., query_request: _Optional[_Union[MDNSQueryRequest, _Mapping]] = ..., query_response: _Optional[_Union[MDNSQueryResponse, _Mapping]] = ..., instance_id: _Optional[str] = ...) -> None: ...